@@ -0,0 +1,54 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/kotatut/cluster_import_cleaner/hclmodifier/types"
+)
+
+// changelogSuffix is appended to a file's path to name the sidecar written by --changelog.
+const changelogSuffix = ".changes.txt"
+
+// formatChangelog returns a human-readable summary of report for the file shown as displayName: every change,
+// grouped by resource in the order the resources were first changed, followed by the warnings.
+func formatChangelog(displayName string, report types.ChangeReport) string {
+	var sb strings.Builder
+	if len(report.Changes) == 0 {
+		fmt.Fprintf(&sb, "No changes to %s.\n", displayName)
+	} else {
+		fmt.Fprintf(&sb, "Changes to %s:\n", displayName)
+	}
+
+	var resources []string
+	changesByResource := make(map[string][]types.ChangeEntry)
+	for _, change := range report.Changes {
+		resource := strings.Join(change.ResourceLabels, ".")
+		if _, ok := changesByResource[resource]; !ok {
+			resources = append(resources, resource)
+		}
+		changesByResource[resource] = append(changesByResource[resource], change)
+	}
+	for _, resource := range resources {
+		fmt.Fprintf(&sb, "  %s\n", resource)
+		for _, change := range changesByResource[resource] {
+			fmt.Fprintf(&sb, "    - %s %s (%s)\n", change.ActionType, strings.Join(change.Path, "."), change.RuleName)
+		}
+	}
+
+	if len(report.Warnings) > 0 {
+		sb.WriteString("Warnings:\n")
+		for _, warning := range report.Warnings {
+			fmt.Fprintf(&sb, "  %s: %s (%s)\n", strings.Join(warning.ResourceLabels, "."), warning.Message, warning.RuleName)
+		}
+	}
+	return sb.String()
+}
+
+// writeChangelog writes the changelog of report to filePath plus changelogSuffix, replacing any previous one.
+func writeChangelog(filePath string, report types.ChangeReport) error {
+	changelog := formatChangelog(filepath.Base(filePath), report)
+	return os.WriteFile(filePath+changelogSuffix, []byte(changelog), 0644)
+}
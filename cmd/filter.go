@@ -0,0 +1,58 @@
+package cmd
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/kotatut/cluster_import_cleaner/hclmodifier/types"
+)
+
+// filterRules returns the rules of allRules selected by the --rule-include and --rule-exclude patterns, in their
+// original order. With no include patterns every rule is included; a rule matching any exclude pattern is always
+// left out, even if it matches an include pattern too. It is an error for a pattern to be invalid or for no rule
+// to be left.
+func filterRules(allRules []types.Rule, include, exclude []string) ([]types.Rule, error) {
+	var selected []types.Rule
+	for _, rule := range allRules {
+		included := len(include) == 0
+		if !included {
+			var err error
+			if included, err = matchesAnyRulePattern(rule.Name, include); err != nil {
+				return nil, fmt.Errorf("invalid --rule-include pattern: %w", err)
+			}
+		}
+		excluded, err := matchesAnyRulePattern(rule.Name, exclude)
+		if err != nil {
+			return nil, fmt.Errorf("invalid --rule-exclude pattern: %w", err)
+		}
+		if included && !excluded {
+			selected = append(selected, rule)
+		}
+	}
+	if len(selected) == 0 {
+		return nil, fmt.Errorf("no rules left to apply after --rule-include and --rule-exclude filtering")
+	}
+	return selected, nil
+}
+
+// matchesAnyRulePattern reports whether name contains one of patterns, or matches one of them as a glob pattern
+// (see path.Match) if the pattern contains glob metacharacters.
+func matchesAnyRulePattern(name string, patterns []string) (bool, error) {
+	for _, pattern := range patterns {
+		if strings.Contains(name, pattern) {
+			return true, nil
+		}
+		if !isGlobPattern(pattern) {
+			continue
+		}
+		matched, err := path.Match(pattern, name)
+		if err != nil {
+			return false, fmt.Errorf("%q: %w", pattern, err)
+		}
+		if matched {
+			return true, nil
+		}
+	}
+	return false, nil
+}
@@ -0,0 +1,113 @@
+package cmd
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// doubleStar is the glob path segment matching any number of directories, including none.
+const doubleStar = "**"
+
+// isGlobPattern reports whether path contains glob metacharacters.
+func isGlobPattern(path string) bool {
+	return strings.ContainsAny(path, "*?[")
+}
+
+// expandFileGlob returns the regular files matching pattern, in lexical order. Besides the filepath.Match
+// syntax, a `**` path segment matches any number of directories, e.g. `modules/**/cluster.tf`.
+// It is an error for pattern to match no file.
+func expandFileGlob(pattern string) ([]string, error) {
+	var matches []string
+	var err error
+	if strings.Contains(pattern, doubleStar) {
+		matches, err = expandDoubleStarGlob(pattern)
+	} else {
+		matches, err = filepath.Glob(pattern)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid glob pattern %q: %w", pattern, err)
+	}
+
+	var filePaths []string
+	for _, match := range matches {
+		if info, err := os.Stat(match); err == nil && info.Mode().IsRegular() {
+			filePaths = append(filePaths, match)
+		}
+	}
+	if len(filePaths) == 0 {
+		return nil, fmt.Errorf("glob pattern %q matched no files", pattern)
+	}
+	sort.Strings(filePaths)
+	return filePaths, nil
+}
+
+// expandDoubleStarGlob walks the directory named by the part of pattern before its first glob segment
+// and returns every path matching the remaining segments.
+func expandDoubleStarGlob(pattern string) ([]string, error) {
+	segments := strings.Split(filepath.ToSlash(filepath.Clean(pattern)), "/")
+	rootIndex := 0
+	for rootIndex < len(segments) && !isGlobPattern(segments[rootIndex]) {
+		rootIndex++
+	}
+	root := filepath.FromSlash(strings.Join(segments[:rootIndex], "/"))
+	if root == "" {
+		root = "."
+		if filepath.IsAbs(pattern) {
+			root = string(filepath.Separator)
+		}
+	}
+	patternSegments := segments[rootIndex:]
+
+	// Validate every segment up front, as filepath.Glob does.
+	for _, segment := range patternSegments {
+		if _, err := filepath.Match(segment, ""); err != nil {
+			return nil, err
+		}
+	}
+
+	var matches []string
+	err := filepath.WalkDir(root, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			if path == root && os.IsNotExist(err) {
+				return filepath.SkipDir
+			}
+			return err
+		}
+		rel, err := filepath.Rel(root, path)
+		if err != nil || rel == "." {
+			return err
+		}
+		if matchSegments(patternSegments, strings.Split(filepath.ToSlash(rel), "/")) {
+			matches = append(matches, path)
+		}
+		return nil
+	})
+	return matches, err
+}
+
+// matchSegments reports whether the path segments match the pattern segments, where a `**` pattern
+// segment matches zero or more path segments and any other segment is matched with filepath.Match.
+func matchSegments(patternSegments, pathSegments []string) bool {
+	if len(patternSegments) == 0 {
+		return len(pathSegments) == 0
+	}
+	if patternSegments[0] == doubleStar {
+		for skip := 0; skip <= len(pathSegments); skip++ {
+			if matchSegments(patternSegments[1:], pathSegments[skip:]) {
+				return true
+			}
+		}
+		return false
+	}
+	if len(pathSegments) == 0 {
+		return false
+	}
+	if matched, _ := filepath.Match(patternSegments[0], pathSegments[0]); !matched {
+		return false
+	}
+	return matchSegments(patternSegments[1:], pathSegments[1:])
+}
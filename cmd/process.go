@@ -0,0 +1,314 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sync"
+
+	"github.com/kotatut/cluster_import_cleaner/hclmodifier"
+	"github.com/kotatut/cluster_import_cleaner/hclmodifier/types"
+	"go.uber.org/zap"
+)
+
+// ProcessOptions holds the settings that control how ProcessFile handles a single file.
+type ProcessOptions struct {
+	// Analyze logs the changes and warnings instead of writing the file.
+	Analyze bool
+	// Check applies the rules to a copy of the file and never writes; the result tells whether the file would change.
+	Check bool
+	// Verify fails without writing if a second pass of the rules would still modify the file.
+	Verify bool
+	// Lint logs warnings about HCL features the rules may not handle before applying them.
+	Lint bool
+	// Backup copies the original file to its path plus BackupSuffix before writing.
+	Backup       bool
+	BackupSuffix string
+	// RemoveEmptyResources removes resources left with only EmptyResourceAttributes after cleanup.
+	RemoveEmptyResources    bool
+	EmptyResourceAttributes []string
+	// Sort reorders the top-level attributes of cluster resources alphabetically once the cleanup is done.
+	Sort bool
+	// ResolveReferences lets conditions see through references to literal attributes elsewhere in the file.
+	ResolveReferences bool
+	// FixReferences removes or warns about references to resources and attributes removed by the cleanup.
+	FixReferences bool
+	// MaxErrors caps how many rule errors are logged for the file, summarizing the others as "+N more".
+	// Zero means no limit.
+	MaxErrors int
+	// Changelog writes a human-readable summary of the changes to the file's path plus changelogSuffix, once the
+	// file itself is written.
+	Changelog bool
+}
+
+// FileResult summarizes the outcome of processing a single file.
+type FileResult struct {
+	FilePath string
+	// Modifications is the number of changes made (or that would be made, in check and analyze modes).
+	Modifications int
+	// LinesAdded and LinesRemoved compare the original content with the cleaned content.
+	LinesAdded   int
+	LinesRemoved int
+	// Written is true if the cleaned content was written back to FilePath.
+	Written bool
+	// Report holds the changes made and the warnings raised by the rules.
+	Report types.ChangeReport
+	// RuleErrors are the errors returned by individual rules; the error returned by ProcessFile only counts them.
+	RuleErrors []error
+}
+
+// ProcessFile parses filePath, applies rulesToApply to it and, unless opts says otherwise, writes the result back
+// in place. The file is still written when some rules fail, as the others may have succeeded; the rule errors are
+// then returned together with the result.
+func ProcessFile(filePath string, rulesToApply []types.Rule, opts ProcessOptions, logger *zap.Logger) (FileResult, error) {
+	result := FileResult{FilePath: filePath}
+
+	logger.Info("Processing file", zap.String("filePath", filePath))
+	hclFile, err := hclmodifier.NewFromFile(filePath, logger)
+	if err != nil {
+		return result, fmt.Errorf("failed to parse HCL file %s: %w", filePath, err)
+	}
+	originalContent := hclFile.File().Bytes()
+	hclFile.ResolveReferences = opts.ResolveReferences
+
+	// In check mode the rules run against a copy, so nothing that happens here can reach the file.
+	if opts.Check {
+		hclFile = hclFile.Clone()
+		if hclFile == nil {
+			return result, fmt.Errorf("failed to copy HCL file %s for checking", filePath)
+		}
+	}
+
+	if err := cleanUp(hclFile, rulesToApply, opts, &result, logger); err != nil {
+		return result, err
+	}
+	ruleErrors := result.RuleErrors
+
+	result.LinesAdded, result.LinesRemoved = hclmodifier.DiffStat(originalContent, hclFile.File().Bytes())
+
+	if opts.Analyze || opts.Check {
+		if len(ruleErrors) > 0 {
+			return result, ruleErrorsToError(ruleErrors, filePath, opts.MaxErrors, logger)
+		}
+		logger.Info("File was not modified", zap.String("filePath", filePath))
+		return result, nil
+	}
+
+	// Keep a copy of the original file; if it can't be written, leave the original untouched.
+	if opts.Backup {
+		backupPath := filePath + opts.BackupSuffix
+		if err := backupFile(filePath, backupPath); err != nil {
+			return result, fmt.Errorf("failed to write backup file, original file was not modified: %w", err)
+		}
+		logger.Info("Backup written", zap.String("backupPath", backupPath))
+	}
+
+	// Write the modified HCL content back to the file.
+	// This should happen regardless of rule application errors, as some rules might have succeeded.
+	if err := hclFile.WriteToFile(filePath); err != nil {
+		return result, fmt.Errorf("failed to write modified HCL file: %w", err)
+	}
+	result.Written = true
+
+	if opts.Changelog {
+		if err := writeChangelog(filePath, result.Report); err != nil {
+			return result, fmt.Errorf("failed to write changelog for %s: %w", filePath, err)
+		}
+		logger.Info("Changelog written", zap.String("changelogPath", filePath+changelogSuffix))
+	}
+
+	if len(ruleErrors) > 0 {
+		return result, ruleErrorsToError(ruleErrors, filePath, opts.MaxErrors, logger)
+	}
+
+	logger.Info("Successfully processed and saved HCL file", zap.String("filePath", filePath))
+	return result, nil
+}
+
+// ProcessStream reads HCL from in, applies rulesToApply to it and writes the result to out; name identifies the
+// content in logs and errors. As with ProcessFile, the cleaned content is written even when some rules fail, and
+// the rule errors are then returned.
+func ProcessStream(in io.Reader, out io.Writer, name string, rulesToApply []types.Rule, opts ProcessOptions, logger *zap.Logger) (FileResult, error) {
+	result := FileResult{FilePath: name}
+
+	logger.Info("Processing stream", zap.String("filePath", name))
+	content, err := io.ReadAll(in)
+	if err != nil {
+		return result, fmt.Errorf("failed to read %s: %w", name, err)
+	}
+	hclFile, err := hclmodifier.NewFromBytes(content, name, logger)
+	if err != nil {
+		return result, fmt.Errorf("failed to parse HCL from %s: %w", name, err)
+	}
+	originalContent := hclFile.File().Bytes()
+	hclFile.ResolveReferences = opts.ResolveReferences
+
+	if err := cleanUp(hclFile, rulesToApply, opts, &result, logger); err != nil {
+		return result, err
+	}
+
+	cleanedContent := hclFile.File().Bytes()
+	result.LinesAdded, result.LinesRemoved = hclmodifier.DiffStat(originalContent, cleanedContent)
+	if _, err := out.Write(cleanedContent); err != nil {
+		return result, fmt.Errorf("failed to write cleaned HCL: %w", err)
+	}
+	result.Written = true
+
+	if len(result.RuleErrors) > 0 {
+		return result, ruleErrorsToError(result.RuleErrors, name, opts.MaxErrors, logger)
+	}
+	return result, nil
+}
+
+// cleanUp applies rulesToApply to hclFile along with the optional passes enabled in opts, recording the outcome in
+// result. It only returns an error when a pass can't be completed; rule errors are left in result.RuleErrors.
+func cleanUp(hclFile *hclmodifier.Modifier, rulesToApply []types.Rule, opts ProcessOptions, result *FileResult, logger *zap.Logger) error {
+	filePath := result.FilePath
+
+	// Point out templated constructs that rules may silently skip over.
+	if opts.Lint {
+		lintWarnings := hclFile.Lint()
+		for _, warning := range lintWarnings {
+			logger.Warn("Lint warning", zap.Strings("resourceLabels", warning.ResourceLabels), zap.String("message", warning.Message))
+		}
+		logger.Info("Lint completed", zap.Int("warnings", len(lintWarnings)), zap.String("filePath", filePath))
+	}
+
+	// Keep the file as it was before cleanup, to tell which references the cleanup orphaned.
+	var original *hclmodifier.Modifier
+	if opts.FixReferences {
+		original = hclFile.Clone()
+		if original == nil {
+			return fmt.Errorf("failed to copy HCL file %s for fixing references", filePath)
+		}
+	}
+
+	logger.Info("Applying generic rules...", zap.Int("ruleCount", len(rulesToApply)))
+	report, ruleErrors := hclFile.ApplyRulesWithReport(rulesToApply)
+	result.Modifications = len(report.Changes)
+	result.Report = report
+	result.RuleErrors = ruleErrors
+	logger.Info("Generic rules application completed", zap.Int("totalModifications", len(report.Changes)), zap.Int("warnings", len(report.Warnings)), zap.String("filePath", filePath))
+	logger.Info("Rules that made changes", zap.Strings("appliedRules", report.AppliedRuleNames()), zap.String("filePath", filePath))
+
+	// A second pass over the cleaned file must be a no-op; otherwise some rules undo each other.
+	if opts.Verify {
+		idempotent, secondPassModifications, err := hclFile.VerifyIdempotent(rulesToApply)
+		if err != nil {
+			return fmt.Errorf("failed to verify idempotency: %w", err)
+		}
+		if !idempotent {
+			return fmt.Errorf("rules are not idempotent: a second pass would make %d more modification(s) to %s, file was not modified", secondPassModifications, filePath)
+		}
+		logger.Info("Idempotency verified, a second pass makes no changes", zap.String("filePath", filePath))
+	}
+
+	// In analyze mode, only report what would change and what looks suspicious; never write.
+	if opts.Analyze {
+		stats := hclFile.Stats()
+		logger.Info("File stats", zap.Int("resources", stats.Resources), zap.Int("clusters", stats.Clusters), zap.Int("nodePools", stats.NodePools), zap.Int("attributes", stats.Attributes), zap.Int("blocks", stats.Blocks), zap.Int("maxBlockDepth", stats.MaxBlockDepth), zap.String("filePath", filePath))
+		for _, change := range report.Changes {
+			logger.Info("Would change", zap.String("rule", change.RuleName), zap.Strings("resourceLabels", change.ResourceLabels), zap.String("action", string(change.ActionType)), zap.Strings("path", change.Path))
+		}
+		for _, warning := range report.Warnings {
+			logger.Warn("Analysis warning", zap.String("rule", warning.RuleName), zap.Strings("resourceLabels", warning.ResourceLabels), zap.String("message", warning.Message))
+		}
+	}
+
+	// Optionally drop resources that have nothing left but identifying attributes.
+	if opts.RemoveEmptyResources {
+		removed := hclFile.RemoveEmptyResources("google_container_cluster", opts.EmptyResourceAttributes)
+		result.Modifications += removed
+		logger.Info("Empty resources removal completed", zap.Int("resourcesRemoved", removed), zap.String("filePath", filePath))
+	}
+
+	// Finally, deal with references to whatever the rules and the empty resources removal took away.
+	if opts.FixReferences {
+		removed, warnings := hclFile.FixOrphanedReferences(original)
+		for _, warning := range warnings {
+			logger.Warn("Orphaned reference", zap.Strings("resourceLabels", warning.ResourceLabels), zap.String("message", warning.Message))
+		}
+		result.Modifications += removed
+		logger.Info("Orphaned references pass completed", zap.Int("attributesRemoved", removed), zap.Int("warnings", len(warnings)), zap.String("filePath", filePath))
+	}
+
+	if opts.Sort {
+		sorted, err := hclFile.SortResourceAttributes()
+		if err != nil {
+			return fmt.Errorf("failed to sort attributes in %s: %w", filePath, err)
+		}
+		result.Modifications += sorted
+		logger.Info("Attribute sorting completed", zap.Int("resourcesSorted", sorted), zap.String("filePath", filePath))
+	}
+
+	// A rule bug could leave settings the provider rejects, or even HCL that no longer parses.
+	for _, err := range hclFile.Validate() {
+		logger.Warn("Validation problem after cleanup", zap.Error(err), zap.String("filePath", filePath))
+	}
+
+	return nil
+}
+
+// ProcessFiles runs ProcessFile on every file of filePaths, processing up to concurrency files at a time.
+// The results and errors are returned in the order of filePaths, whatever order the files finish in.
+func ProcessFiles(filePaths []string, rulesToApply []types.Rule, opts ProcessOptions, concurrency int, logger *zap.Logger) ([]FileResult, []error) {
+	results := make([]FileResult, len(filePaths))
+	errs := make([]error, len(filePaths))
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	// Each file gets its own Modifier, so workers only share the read-only rules and their own slots of the slices.
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for worker := 0; worker < min(concurrency, len(filePaths)); worker++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				results[i], errs[i] = ProcessFile(filePaths[i], rulesToApply, opts, logger)
+			}
+		}()
+	}
+	for i := range filePaths {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+	return results, errs
+}
+
+// ruleErrorsToError logs the rule application errors, at most maxErrors of them unless it is zero, and returns a
+// single error summarizing them.
+func ruleErrorsToError(ruleErrors []error, filePath string, maxErrors int, logger *zap.Logger) error {
+	logger.Error("One or more rules encountered errors during processing file.", zap.String("filePath", filePath))
+	shown, more := limitErrors(ruleErrors, maxErrors)
+	for _, ruleErr := range shown {
+		logger.Error("Rule application error", zap.Error(ruleErr))
+	}
+	if more > 0 {
+		logger.Error(fmt.Sprintf("+%d more rule application error(s)", more), zap.String("filePath", filePath))
+	}
+	return fmt.Errorf("encountered %d error(s) during rule processing on file %s. See logs for details", len(ruleErrors), filePath)
+}
+
+// limitErrors returns the first maxErrors of errs and how many were left out. A maxErrors of zero means no limit.
+func limitErrors(errs []error, maxErrors int) ([]error, int) {
+	if maxErrors <= 0 || len(errs) <= maxErrors {
+		return errs, 0
+	}
+	return errs[:maxErrors], len(errs) - maxErrors
+}
+
+// backupFile copies the contents of filePath to backupPath, keeping the original file mode.
+func backupFile(filePath, backupPath string) error {
+	info, err := os.Stat(filePath)
+	if err != nil {
+		return err
+	}
+	content, err := os.ReadFile(filePath)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(backupPath, content, info.Mode().Perm())
+}
@@ -1,17 +1,49 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
+	"io"
+	"io/fs"
 	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
 
-	"github.com/kotatut/cluster_import_cleaner/hclmodifier"
 	"github.com/kotatut/cluster_import_cleaner/hclmodifier/rules"
 	"github.com/kotatut/cluster_import_cleaner/hclmodifier/types"
 	"github.com/spf13/cobra"
 	"go.uber.org/zap"
 )
 
-var filePathFlag string
+var (
+	filePathFlag                string
+	dirPathFlag                 string
+	removeEmptyResourcesFlag    bool
+	emptyResourceAttributesFlag []string
+	analyzeFlag                 bool
+	checkFlag                   bool
+	backupFlag                  bool
+	backupSuffixFlag            string
+	verifyFlag                  bool
+	lintFlag                    bool
+	fixReferencesFlag           bool
+	resolveReferencesFlag       bool
+	sortFlag                    bool
+	jsonFlag                    bool
+	concurrencyFlag             int
+	ruleIncludeFlag             []string
+	ruleExcludeFlag             []string
+	stdinFlag                   bool
+	listRulesFlag               bool
+	changelogFlag               bool
+	verboseFlag                 bool
+	maxErrorsFlag               int
+	removeLifecycleFlag         bool
+	emptyAuthorizedNetworksFlag bool
+	nodeDiskSizeGbFlag          int
+	nodeDiskTypeFlag            string
+)
 
 func NewRootCmd(logger *zap.Logger) *cobra.Command {
 	cmd := &cobra.Command{
@@ -22,71 +54,262 @@ It applies a predefined set of rules to clean up common issues found in configur
 for Google Kubernetes Engine (GKE) clusters, especially those generated from Terraform imports
 or older templates. The tool modifies the file in-place.`,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			logger.Info("Processing file", zap.String("filePath", filePathFlag))
-			hclFile, err := hclmodifier.NewFromFile(filePathFlag, logger)
-			if err != nil {
-				return fmt.Errorf("failed to parse HCL file: %w", err)
-			}
-
-			// Define all rules to be applied by the generic ApplyRules engine.
-			allRules := []types.Rule{
-				rules.ClusterIPV4CIDRRuleDefinition,
-				rules.MasterCIDRRuleDefinition,
-				rules.ServicesIPV4CIDRRuleDefinition,
-				rules.PodIPV4CIDRRuleDefinition,
-				rules.BinaryAuthorizationRuleDefinition,
-				rules.RuleRemoveLoggingService,
-				rules.RemoveLoggingServiceOnConfigPresentRule,
-				rules.RuleRemoveMonitoringService,
-				rules.SetMinVersionRule,
-				rules.HpaProfileRuleDefinition,
-				rules.DiskSizeRuleDefinition,
-				rules.OsVersionRuleDefinition,
-				rules.OsVersionNodePoolRuleDefinition,
-				rules.InitialNodeCountRuleDefinition,
-				rules.RuleHandleAutopilotFalse,
-				rules.RuleTerraformLabel,
-			}
-			allRules = append(allRules, rules.AutopilotRules...)
-			allRules = append(allRules, rules.TopLevelComputedAttributesRules...)
-			allRules = append(allRules, rules.OtherComputedAttributesRules...)
-
-			var encounteredErrors []error
-
-			logger.Info("Applying generic rules...", zap.Int("ruleCount", len(allRules)))
-			modifications, genericRuleErrors := hclFile.ApplyRules(allRules)
-			if len(genericRuleErrors) > 0 {
-				encounteredErrors = append(encounteredErrors, genericRuleErrors...)
-			}
-			logger.Info("Generic rules application completed", zap.Int("totalModifications", modifications), zap.String("filePath", filePathFlag))
-
-			// Write the modified HCL content back to the file.
-			// This should happen regardless of rule application errors, as some rules might have succeeded.
-			err = hclFile.WriteToFile(filePathFlag)
+			if listRulesFlag {
+				return listRules(cmd.OutOrStdout(), registeredRules(), ruleIncludeFlag, ruleExcludeFlag)
+			}
+			if stdinFlag {
+				if filePathFlag != "" || dirPathFlag != "" {
+					return fmt.Errorf("--stdin can't be combined with --file or --dir")
+				}
+				if analyzeFlag || checkFlag || backupFlag || jsonFlag || changelogFlag || verboseFlag {
+					return fmt.Errorf("--stdin can't be combined with --analyze, --check, --backup, --json, --changelog or --verbose")
+				}
+			} else if (filePathFlag == "") == (dirPathFlag == "") {
+				return fmt.Errorf("exactly one of --file, --dir or --stdin must be set")
+			}
+			// Without writing, the changelog is printed to stdout, where it would corrupt the JSON summary.
+			if changelogFlag && jsonFlag && (analyzeFlag || checkFlag) {
+				return fmt.Errorf("--changelog can't be combined with --json in --analyze or --check mode")
+			}
+			if verboseFlag && jsonFlag {
+				return fmt.Errorf("--verbose can't be combined with --json")
+			}
+			if maxErrorsFlag < 0 {
+				return fmt.Errorf("--max-errors must not be negative, got %d", maxErrorsFlag)
+			}
+			if concurrencyFlag < 1 {
+				return fmt.Errorf("--concurrency must be at least 1, got %d", concurrencyFlag)
+			}
+
+			filePaths := []string{filePathFlag}
+			if isGlobPattern(filePathFlag) {
+				var err error
+				filePaths, err = expandFileGlob(filePathFlag)
+				if err != nil {
+					return err
+				}
+				logger.Info("Processing files matching glob pattern", zap.String("pattern", filePathFlag), zap.Int("fileCount", len(filePaths)))
+			}
+			if dirPathFlag != "" {
+				var err error
+				filePaths, err = collectTerraformFiles(dirPathFlag)
+				if err != nil {
+					return fmt.Errorf("failed to list Terraform files in %s: %w", dirPathFlag, err)
+				}
+				logger.Info("Processing directory", zap.String("dirPath", dirPathFlag), zap.Int("fileCount", len(filePaths)))
+			}
+
+			opts := ProcessOptions{
+				Analyze:                 analyzeFlag,
+				Check:                   checkFlag,
+				Verify:                  verifyFlag,
+				Lint:                    lintFlag,
+				Backup:                  backupFlag,
+				BackupSuffix:            backupSuffixFlag,
+				RemoveEmptyResources:    removeEmptyResourcesFlag,
+				EmptyResourceAttributes: emptyResourceAttributesFlag,
+				FixReferences:           fixReferencesFlag,
+				ResolveReferences:       resolveReferencesFlag,
+				Sort:                    sortFlag,
+				Changelog:               changelogFlag,
+				MaxErrors:               maxErrorsFlag,
+			}
+			allRules, err := filterRules(rulesOf(registeredRules()), ruleIncludeFlag, ruleExcludeFlag)
 			if err != nil {
-				return fmt.Errorf("failed to write modified HCL file: %w", err)
+				return err
+			}
+			// Catch malformed rules up front instead of as warnings halfway through the files.
+			if validationErrors := rules.Validate(allRules); len(validationErrors) > 0 {
+				for _, validationErr := range validationErrors {
+					logger.Error("Invalid rule", zap.Error(validationErr))
+				}
+				return fmt.Errorf("%d invalid rule(s), no file was modified: %w", len(validationErrors), errors.Join(validationErrors...))
+			}
+			if len(ruleIncludeFlag) > 0 || len(ruleExcludeFlag) > 0 {
+				logger.Info("Filtered rules", zap.Int("ruleCount", len(allRules)), zap.Strings("include", ruleIncludeFlag), zap.Strings("exclude", ruleExcludeFlag))
+			}
+
+			// With --stdin, stdout only gets the cleaned HCL.
+			if stdinFlag {
+				cmd.SilenceUsage = true
+				_, err := ProcessStream(cmd.InOrStdin(), cmd.OutOrStdout(), "<stdin>", allRules, opts, logger)
+				return err
 			}
 
-			// Report any errors encountered during rule processing.
-			if len(encounteredErrors) > 0 {
-				logger.Error("One or more rules encountered errors during processing file.", zap.String("filePath", filePathFlag))
-				for _, ruleErr := range encounteredErrors {
-					logger.Error("Rule application error", zap.Error(ruleErr))
+			// With --json, stdout only gets the JSON summary; usage text would corrupt it.
+			if jsonFlag {
+				cmd.SilenceUsage = true
+			}
+			summary := jsonSummary{Files: []jsonFileSummary{}, maxErrors: maxErrorsFlag}
+
+			var failedFiles, uncleanFiles []string
+			totalAdded, totalRemoved, cleanedFiles := 0, 0, 0
+			results, errs := ProcessFiles(filePaths, allRules, opts, concurrencyFlag, logger)
+			for i, filePath := range filePaths {
+				result, err := results[i], errs[i]
+				if err != nil {
+					logger.Error("Failed to process file", zap.String("filePath", filePath), zap.Error(err))
+					failedFiles = append(failedFiles, filePath)
+					if len(filePaths) == 1 && !jsonFlag {
+						return err
+					}
+				}
+
+				displayName := displayPath(filePath)
+				summary.add(displayName, result, err)
+				if result.Written {
+					if !jsonFlag {
+						fmt.Fprintf(cmd.OutOrStdout(), "cleaned %s: -%d +%d lines\n", displayName, result.LinesRemoved, result.LinesAdded)
+					}
+					totalAdded += result.LinesAdded
+					totalRemoved += result.LinesRemoved
+					cleanedFiles++
+				}
+				if verboseFlag {
+					if err := writeRuleStats(cmd.OutOrStdout(), displayName, result.Report.RuleStats); err != nil {
+						return fmt.Errorf("failed to write rule stats: %w", err)
+					}
+				}
+				// Files that aren't written don't get a sidecar; print what it would contain instead.
+				if changelogFlag && (analyzeFlag || checkFlag) && err == nil {
+					fmt.Fprint(cmd.OutOrStdout(), formatChangelog(displayName, result.Report))
+				}
+				if checkFlag && result.Modifications > 0 {
+					if !jsonFlag {
+						fmt.Fprintf(cmd.OutOrStdout(), "would clean %s: -%d +%d lines\n", displayName, result.LinesRemoved, result.LinesAdded)
+					}
+					uncleanFiles = append(uncleanFiles, displayName)
 				}
-				return fmt.Errorf("encountered %d error(s) during rule processing on file %s. See logs for details", len(encounteredErrors), filePathFlag)
+			}
+			if jsonFlag {
+				if err := summary.write(cmd.OutOrStdout()); err != nil {
+					return fmt.Errorf("failed to write JSON summary: %w", err)
+				}
+			} else if len(filePaths) > 1 && cleanedFiles > 0 {
+				fmt.Fprintf(cmd.OutOrStdout(), "cleaned %d file(s): -%d +%d lines\n", cleanedFiles, totalRemoved, totalAdded)
 			}
 
-			logger.Info("Successfully processed and saved HCL file", zap.String("filePath", filePathFlag))
+			if len(failedFiles) > 0 {
+				return fmt.Errorf("failed to process %d file(s): %s. See logs for details", len(failedFiles), strings.Join(failedFiles, ", "))
+			}
+			if len(uncleanFiles) > 0 {
+				return fmt.Errorf("%d file(s) would be modified by the cleaner: %s", len(uncleanFiles), strings.Join(uncleanFiles, ", "))
+			}
 			return nil
 		},
 	}
 
-	cmd.PersistentFlags().StringVar(&filePathFlag, "file", "", "Path to the HCL file to modify (required)")
-	cmd.MarkPersistentFlagRequired("file")
+	cmd.PersistentFlags().StringVar(&filePathFlag, "file", "", "Path to the HCL file to modify, or a glob pattern (** matches any number of directories)")
+	cmd.PersistentFlags().StringVar(&dirPathFlag, "dir", "", "Directory whose .tf files (recursively) are modified, instead of a single --file")
+	cmd.PersistentFlags().BoolVar(&listRulesFlag, "list-rules", false, "Print the name, category and description of every rule that would be applied, then exit")
+	cmd.PersistentFlags().BoolVar(&stdinFlag, "stdin", false, "Read HCL from stdin and write the cleaned HCL to stdout, instead of modifying files")
+	cmd.PersistentFlags().StringArrayVar(&ruleIncludeFlag, "rule-include", nil, "Only apply rules whose name contains this text or matches this glob pattern (repeatable)")
+	cmd.PersistentFlags().StringArrayVar(&ruleExcludeFlag, "rule-exclude", nil, "Don't apply rules whose name contains this text or matches this glob pattern, even if included (repeatable)")
+	cmd.PersistentFlags().BoolVar(&analyzeFlag, "analyze", false, "Report changes and warnings without modifying the file")
+	cmd.PersistentFlags().BoolVar(&checkFlag, "check", false, "Don't modify any file; fail if a file would be modified")
+	cmd.PersistentFlags().BoolVar(&removeEmptyResourcesFlag, "remove-empty-resources", false, "Remove google_container_cluster resources left without meaningful content after cleanup")
+	cmd.PersistentFlags().StringSliceVar(&emptyResourceAttributesFlag, "empty-resource-attributes", []string{"name", "location"}, "Attributes that don't count as meaningful content for --remove-empty-resources")
+	cmd.PersistentFlags().BoolVar(&fixReferencesFlag, "fix-references", false, "After cleanup, remove attributes that only reference removed resources and warn about other orphaned references")
+	cmd.PersistentFlags().BoolVar(&resolveReferencesFlag, "resolve-references", false, "Let rule conditions resolve references to literal attributes of other resources, data sources and locals in the same file")
+	cmd.PersistentFlags().BoolVar(&sortFlag, "sort", false, "After cleanup, sort the top-level attributes of google_container_cluster resources alphabetically")
+	cmd.PersistentFlags().BoolVar(&jsonFlag, "json", false, "Print a JSON summary of the results to stdout instead of the human-readable summary lines")
+	cmd.PersistentFlags().IntVar(&maxErrorsFlag, "max-errors", 0, "Maximum number of rule errors logged per file, the others being summarized as \"+N more\" (0 means no limit)")
+	cmd.PersistentFlags().IntVar(&concurrencyFlag, "concurrency", runtime.NumCPU(), "Maximum number of files processed in parallel")
+	cmd.PersistentFlags().BoolVar(&lintFlag, "lint", false, "Warn about HCL features (count, for_each, dynamic blocks, non-literal values) that rules may not handle")
+	cmd.PersistentFlags().BoolVar(&verifyFlag, "verify", false, "Fail without writing if applying the rules a second time would still modify the file")
+	cmd.PersistentFlags().BoolVar(&verboseFlag, "verbose", false, "Print, for each file, how many resources every rule matched, met the conditions of and acted on")
+	cmd.PersistentFlags().BoolVar(&changelogFlag, "changelog", false, "Write a summary of the changes made to each file to <file>.changes.txt; with --analyze or --check, print it instead")
+	cmd.PersistentFlags().BoolVar(&removeLifecycleFlag, "remove-lifecycle", false, "Also remove lifecycle blocks from google_container_cluster resources, e.g. when ignore_changes lists attributes the cleanup removes")
+	cmd.PersistentFlags().BoolVar(&emptyAuthorizedNetworksFlag, "remove-empty-authorized-networks", false, "Also remove empty master_authorized_networks_config blocks; this disables authorized networks, opening the control plane endpoint")
+	cmd.PersistentFlags().IntVar(&nodeDiskSizeGbFlag, "node-disk-size-gb", rules.DefaultNodeDiskSizeGb, "Default node pool boot disk size in GB: node_config.disk_size_gb is removed when it equals it")
+	cmd.PersistentFlags().StringVar(&nodeDiskTypeFlag, "node-disk-type", rules.DefaultNodeDiskType, "Default node pool boot disk type: node_config.disk_type is removed when it equals it")
+	cmd.PersistentFlags().BoolVar(&backupFlag, "backup", false, "Copy the original file to <file><backup-suffix> before modifying it")
+	cmd.PersistentFlags().StringVar(&backupSuffixFlag, "backup-suffix", ".bak", "Suffix appended to the file path for the --backup copy")
 
 	return cmd
 }
 
+// registeredRules returns the rules of the default set, using the node pool disk defaults set by flags, followed by
+// the opt-in rules enabled by flags.
+func registeredRules() []rules.RegisteredRule {
+	registry := rules.Registry()
+	if nodeDiskSizeGbFlag != rules.DefaultNodeDiskSizeGb || nodeDiskTypeFlag != rules.DefaultNodeDiskType {
+		registry = rules.WithNodeConfigDiskDefaults(registry, nodeDiskSizeGbFlag, nodeDiskTypeFlag)
+	}
+	if removeLifecycleFlag {
+		registry = append(registry, rules.LifecycleRegisteredRule)
+	}
+	if emptyAuthorizedNetworksFlag {
+		registry = append(registry, rules.EmptyMasterAuthorizedNetworksConfigRegisteredRule)
+	}
+	return registry
+}
+
+// rulesOf returns the rules of registry, in order.
+func rulesOf(registry []rules.RegisteredRule) []types.Rule {
+	registryRules := make([]types.Rule, 0, len(registry))
+	for _, registered := range registry {
+		registryRules = append(registryRules, registered.Rule)
+	}
+	return registryRules
+}
+
+// listRules writes the name, category and description of every rule of registry selected by include and exclude
+// (see filterRules) to out, in the order they are applied.
+func listRules(out io.Writer, registry []rules.RegisteredRule, include, exclude []string) error {
+	selected, err := filterRules(rulesOf(registry), include, exclude)
+	if err != nil {
+		return err
+	}
+	selectedNames := make(map[string]bool, len(selected))
+	for _, rule := range selected {
+		selectedNames[rule.Name] = true
+	}
+	for _, registered := range registry {
+		if selectedNames[registered.Rule.Name] {
+			fmt.Fprintf(out, "%s\n    [%s] %s\n", registered.Rule.Name, registered.Category, registered.Description)
+		}
+	}
+	return nil
+}
+
+// collectTerraformFiles returns the paths of all .tf files under dirPath, in lexical order.
+// Hidden directories such as `.terraform` are skipped.
+func collectTerraformFiles(dirPath string) ([]string, error) {
+	var filePaths []string
+	err := filepath.WalkDir(dirPath, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() {
+			if path != dirPath && strings.HasPrefix(entry.Name(), ".") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if filepath.Ext(path) == ".tf" {
+			filePaths = append(filePaths, path)
+		}
+		return nil
+	})
+	return filePaths, err
+}
+
+// displayPath returns how filePath is shown in the summary output: relative to --dir in directory mode,
+// as matched for a --file glob pattern, otherwise just the file name.
+func displayPath(filePath string) string {
+	if isGlobPattern(filePathFlag) {
+		return filePath
+	}
+	if dirPathFlag != "" {
+		if rel, err := filepath.Rel(dirPathFlag, filePath); err == nil {
+			return rel
+		}
+		return filePath
+	}
+	return filepath.Base(filePath)
+}
+
 func Execute(logger *zap.Logger) {
 	// It's good practice to sync the logger before exiting.
 	defer func() {
@@ -0,0 +1,718 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+
+	"github.com/kotatut/cluster_import_cleaner/hclmodifier/rules"
+	"github.com/kotatut/cluster_import_cleaner/hclmodifier/types"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+const emptyClusterHCL = `resource "google_container_cluster" "empty" {
+  name     = "empty-cluster"
+  location = "us-central1"
+  id       = "projects/p/locations/us-central1/clusters/empty-cluster"
+}
+`
+
+// writeTempHCL writes content to a new .tf file in a temporary directory and returns its path.
+func writeTempHCL(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "cluster.tf")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write temp file: %v", err)
+	}
+	return path
+}
+
+// runRootCmd executes the root command with args and returns the resulting error.
+func runRootCmd(t *testing.T, args ...string) error {
+	t.Helper()
+	rootCmd := NewRootCmd(zap.NewNop())
+	rootCmd.SetArgs(args)
+	return rootCmd.Execute()
+}
+
+func TestRemoveEmptyResourcesFlag(t *testing.T) {
+	t.Run("Off by default", func(t *testing.T) {
+		path := writeTempHCL(t, emptyClusterHCL)
+		err := runRootCmd(t, "--file", path)
+		assert.NoError(t, err)
+
+		content, err := os.ReadFile(path)
+		assert.NoError(t, err)
+		assert.Contains(t, string(content), `resource "google_container_cluster" "empty"`)
+		assert.NotContains(t, string(content), "id ", "Computed id should still be removed by the default rules")
+	})
+
+	t.Run("Enabled removes the empty resource", func(t *testing.T) {
+		path := writeTempHCL(t, emptyClusterHCL)
+		err := runRootCmd(t, "--file", path, "--remove-empty-resources")
+		assert.NoError(t, err)
+
+		content, err := os.ReadFile(path)
+		assert.NoError(t, err)
+		assert.NotContains(t, string(content), `resource "google_container_cluster" "empty"`)
+	})
+}
+
+func TestAnalyzeFlag(t *testing.T) {
+	original := `resource "google_container_cluster" "primary" {
+  name           = "my-cluster"
+  location       = "us-central1-a"
+  node_locations = ["us-central1-b", "us-central1-c"]
+  id             = "projects/p/locations/us-central1-a/clusters/my-cluster"
+}
+`
+	path := writeTempHCL(t, original)
+	err := runRootCmd(t, "--file", path, "--analyze")
+	assert.NoError(t, err)
+
+	content, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, original, string(content), "Analyze mode must not modify the file")
+}
+
+func TestBackupFlag(t *testing.T) {
+	t.Run("Writes the original content to the backup file", func(t *testing.T) {
+		path := writeTempHCL(t, emptyClusterHCL)
+		err := runRootCmd(t, "--file", path, "--backup")
+		assert.NoError(t, err)
+
+		backup, err := os.ReadFile(path + ".bak")
+		assert.NoError(t, err)
+		assert.Equal(t, emptyClusterHCL, string(backup))
+
+		content, err := os.ReadFile(path)
+		assert.NoError(t, err)
+		assert.NotContains(t, string(content), "id ")
+	})
+
+	t.Run("Custom suffix", func(t *testing.T) {
+		path := writeTempHCL(t, emptyClusterHCL)
+		err := runRootCmd(t, "--file", path, "--backup", "--backup-suffix", ".orig")
+		assert.NoError(t, err)
+		assert.FileExists(t, path+".orig")
+		assert.NoFileExists(t, path+".bak")
+	})
+
+	t.Run("Failed backup leaves the original untouched", func(t *testing.T) {
+		path := writeTempHCL(t, emptyClusterHCL)
+		err := runRootCmd(t, "--file", path, "--backup", "--backup-suffix", "/missing-dir/backup")
+		assert.Error(t, err)
+
+		content, err := os.ReadFile(path)
+		assert.NoError(t, err)
+		assert.Equal(t, emptyClusterHCL, string(content))
+	})
+
+	t.Run("No backup in analyze mode", func(t *testing.T) {
+		path := writeTempHCL(t, emptyClusterHCL)
+		err := runRootCmd(t, "--file", path, "--backup", "--analyze")
+		assert.NoError(t, err)
+		assert.NoFileExists(t, path+".bak")
+	})
+}
+
+func TestChangelogFlag(t *testing.T) {
+	idRule := rules.TopLevelComputedAttributesRules[2].Name
+
+	t.Run("Writes the changes next to the file", func(t *testing.T) {
+		path := writeTempHCL(t, emptyClusterHCL)
+		err := runRootCmd(t, "--file", path, "--changelog")
+		assert.NoError(t, err)
+
+		changelog, err := os.ReadFile(path + ".changes.txt")
+		assert.NoError(t, err)
+		assert.Contains(t, string(changelog), "Changes to cluster.tf:")
+		assert.Contains(t, string(changelog), "google_container_cluster.empty")
+		assert.Contains(t, string(changelog), fmt.Sprintf("- RemoveAttribute id (%s)", idRule))
+	})
+
+	t.Run("Prints the changes in check mode", func(t *testing.T) {
+		path := writeTempHCL(t, emptyClusterHCL)
+		var out bytes.Buffer
+		rootCmd := NewRootCmd(zap.NewNop())
+		rootCmd.SetOut(&out)
+		rootCmd.SetArgs([]string{"--file", path, "--changelog", "--check"})
+		assert.Error(t, rootCmd.Execute())
+
+		assert.Contains(t, out.String(), fmt.Sprintf("- RemoveAttribute id (%s)", idRule))
+		assert.NoFileExists(t, path+".changes.txt")
+	})
+
+	t.Run("Rejected with --json in check mode", func(t *testing.T) {
+		path := writeTempHCL(t, emptyClusterHCL)
+		assert.Error(t, runRootCmd(t, "--file", path, "--changelog", "--check", "--json"))
+	})
+}
+
+func TestVerboseFlag(t *testing.T) {
+	path := writeTempHCL(t, emptyClusterHCL)
+	var out bytes.Buffer
+	rootCmd := NewRootCmd(zap.NewNop())
+	rootCmd.SetOut(&out)
+	rootCmd.SetArgs([]string{"--file", path, "--verbose", "--rule-include", "Remove attribute '[id]'", "--rule-include", "Initial Node Count"})
+	assert.NoError(t, rootCmd.Execute())
+
+	assert.Contains(t, out.String(), "rules applied to cluster.tf:")
+	assert.Regexp(t, `RULE +MATCHED +CONDITIONS MET +ACTED`, out.String())
+	assert.Regexp(t, `Remove attribute '\[id\]' from 'google_container_cluster' +1 +1 +1\n`, out.String())
+	assert.Regexp(t, regexp.QuoteMeta(rules.InitialNodeCountRuleDefinition.Name)+` +1 +0 +0\n`, out.String())
+}
+
+func TestMaxErrors(t *testing.T) {
+	var hcl bytes.Buffer
+	for i := 0; i < 5; i++ {
+		fmt.Fprintf(&hcl, "resource \"google_container_cluster\" \"cluster%d\" {\n  name = \"cluster%d\"\n}\n\n", i, i)
+	}
+	path := writeTempHCL(t, hcl.String())
+	// A ForEachNestedBlock rule without NestedBlockTargetType fails once per cluster.
+	brokenRule := types.Rule{
+		Name:               "Broken rule",
+		TargetResourceType: "google_container_cluster",
+		ExecutionType:      types.RuleExecutionForEachNestedBlock,
+		Actions:            []types.RuleAction{{Type: types.RemoveAttribute, Path: []string{"name"}}},
+	}
+
+	core, logs := observer.New(zap.ErrorLevel)
+	result, err := ProcessFile(path, []types.Rule{brokenRule}, ProcessOptions{MaxErrors: 2}, zap.New(core))
+	if assert.Error(t, err, "The file must still fail") {
+		assert.Contains(t, err.Error(), "encountered 5 error(s)")
+	}
+	assert.Len(t, result.RuleErrors, 5)
+	assert.Equal(t, 2, logs.FilterMessage("Rule application error").Len())
+	assert.Equal(t, 1, logs.FilterMessage("+3 more rule application error(s)").Len())
+	assert.Equal(t, 4, logs.FilterLevelExact(zap.ErrorLevel).Len(), "Only the file error, the two rule errors and the +N more line are logged as errors")
+
+	summary := jsonSummary{maxErrors: 2}
+	summary.add("cluster.tf", result, err)
+	assert.Len(t, summary.Files[0].Errors, 4, "Two rule errors, the +N more line and the file error")
+	assert.Equal(t, "+3 more", summary.Files[0].Errors[2])
+
+	assert.Error(t, runRootCmd(t, "--file", path, "--max-errors", "-1"))
+}
+
+func TestVerifyFlag(t *testing.T) {
+	path := writeTempHCL(t, emptyClusterHCL)
+	err := runRootCmd(t, "--file", path, "--verify")
+	assert.NoError(t, err)
+
+	content, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.NotContains(t, string(content), "id ")
+}
+
+func TestDiffStatOutput(t *testing.T) {
+	path := writeTempHCL(t, emptyClusterHCL)
+	var out bytes.Buffer
+	rootCmd := NewRootCmd(zap.NewNop())
+	rootCmd.SetOut(&out)
+	rootCmd.SetArgs([]string{"--file", path})
+	assert.NoError(t, rootCmd.Execute())
+
+	// Only the computed id line is removed.
+	assert.Equal(t, "cleaned cluster.tf: -1 +0 lines\n", out.String())
+}
+
+func TestLintFlag(t *testing.T) {
+	path := writeTempHCL(t, `resource "google_container_cluster" "primary" {
+  count = 2
+  name  = "primary"
+}
+`)
+	core, logs := observer.New(zap.WarnLevel)
+	rootCmd := NewRootCmd(zap.New(core))
+	rootCmd.SetArgs([]string{"--file", path, "--lint"})
+	assert.NoError(t, rootCmd.Execute())
+	assert.Equal(t, 1, logs.FilterMessage("Lint warning").Len())
+}
+
+func TestValidationWarnings(t *testing.T) {
+	path := writeTempHCL(t, `resource "google_container_cluster" "primary" {
+  name            = "primary"
+  logging_service = "logging.googleapis.com/kubernetes"
+
+  logging_config {
+    enable_components = ["SYSTEM_COMPONENTS"]
+  }
+}
+`)
+	core, logs := observer.New(zap.WarnLevel)
+	rootCmd := NewRootCmd(zap.New(core))
+	rootCmd.SetArgs([]string{"--file", path, "--rule-exclude", "Logging Service Rule"})
+	assert.NoError(t, rootCmd.Execute())
+	assert.Equal(t, 1, logs.FilterMessage("Validation problem after cleanup").Len())
+}
+
+func TestRemoveLifecycleFlag(t *testing.T) {
+	const hcl = `resource "google_container_cluster" "primary" {
+  name = "primary"
+
+  lifecycle {
+    ignore_changes = [node_config]
+  }
+}
+`
+	t.Run("Off by default", func(t *testing.T) {
+		path := writeTempHCL(t, hcl)
+		assert.NoError(t, runRootCmd(t, "--file", path))
+		content, err := os.ReadFile(path)
+		assert.NoError(t, err)
+		assert.Equal(t, hcl, string(content))
+	})
+
+	t.Run("Enabled", func(t *testing.T) {
+		path := writeTempHCL(t, hcl)
+		assert.NoError(t, runRootCmd(t, "--file", path, "--remove-lifecycle"))
+		content, err := os.ReadFile(path)
+		assert.NoError(t, err)
+		assert.NotContains(t, string(content), "lifecycle")
+	})
+}
+
+func TestRemoveEmptyAuthorizedNetworksFlag(t *testing.T) {
+	const hcl = `resource "google_container_cluster" "primary" {
+  name = "primary"
+
+  master_authorized_networks_config {
+  }
+}
+`
+	t.Run("Off by default", func(t *testing.T) {
+		path := writeTempHCL(t, hcl)
+		assert.NoError(t, runRootCmd(t, "--file", path))
+		content, err := os.ReadFile(path)
+		assert.NoError(t, err)
+		assert.Equal(t, hcl, string(content))
+	})
+
+	t.Run("Enabled", func(t *testing.T) {
+		path := writeTempHCL(t, hcl)
+		assert.NoError(t, runRootCmd(t, "--file", path, "--remove-empty-authorized-networks"))
+		content, err := os.ReadFile(path)
+		assert.NoError(t, err)
+		assert.NotContains(t, string(content), "master_authorized_networks_config")
+	})
+}
+
+func TestNodeDiskDefaultsFlags(t *testing.T) {
+	const hcl = `resource "google_container_cluster" "primary" {
+  name = "primary"
+
+  node_pool {
+    name = "default-pool"
+
+    node_config {
+      disk_size_gb = 100
+      disk_type    = "pd-balanced"
+      machine_type = "e2-medium"
+    }
+  }
+
+  node_pool {
+    name = "ssd-pool"
+
+    node_config {
+      disk_size_gb = 500
+      disk_type    = "pd-ssd"
+      machine_type = "e2-medium"
+    }
+  }
+}
+`
+	t.Run("GKE defaults", func(t *testing.T) {
+		path := writeTempHCL(t, hcl)
+		assert.NoError(t, runRootCmd(t, "--file", path))
+		content, err := os.ReadFile(path)
+		assert.NoError(t, err)
+		assert.NotContains(t, string(content), "disk_size_gb = 100")
+		assert.NotContains(t, string(content), `"pd-balanced"`)
+		assert.Contains(t, string(content), "disk_size_gb = 500")
+		assert.Contains(t, string(content), `"pd-ssd"`)
+	})
+
+	t.Run("Custom defaults", func(t *testing.T) {
+		path := writeTempHCL(t, hcl)
+		assert.NoError(t, runRootCmd(t, "--file", path, "--node-disk-size-gb", "500", "--node-disk-type", "pd-ssd"))
+		content, err := os.ReadFile(path)
+		assert.NoError(t, err)
+		assert.Contains(t, string(content), "disk_size_gb = 100")
+		assert.Contains(t, string(content), `"pd-balanced"`)
+		assert.NotContains(t, string(content), "disk_size_gb = 500")
+		assert.NotContains(t, string(content), `"pd-ssd"`)
+	})
+}
+
+func TestCheckFlag(t *testing.T) {
+	t.Run("Clean file exits zero", func(t *testing.T) {
+		clean := `resource "google_container_cluster" "primary" {
+  name     = "my-cluster"
+  location = "us-central1"
+}
+`
+		path := writeTempHCL(t, clean)
+		assert.NoError(t, runRootCmd(t, "--file", path, "--check"))
+
+		content, err := os.ReadFile(path)
+		assert.NoError(t, err)
+		assert.Equal(t, clean, string(content))
+	})
+
+	t.Run("Unclean file exits non-zero without writing", func(t *testing.T) {
+		path := writeTempHCL(t, emptyClusterHCL)
+		var out bytes.Buffer
+		rootCmd := NewRootCmd(zap.NewNop())
+		rootCmd.SetOut(&out)
+		rootCmd.SetArgs([]string{"--file", path, "--check"})
+		err := rootCmd.Execute()
+		if assert.Error(t, err) {
+			assert.Contains(t, err.Error(), "cluster.tf")
+		}
+		assert.Contains(t, out.String(), "would clean cluster.tf: -1 +0 lines")
+
+		content, err := os.ReadFile(path)
+		assert.NoError(t, err)
+		assert.Equal(t, emptyClusterHCL, string(content), "Check mode must not modify the file")
+	})
+
+	t.Run("Composes with --dir", func(t *testing.T) {
+		dir := t.TempDir()
+		assert.NoError(t, os.MkdirAll(filepath.Join(dir, "envs", "prod"), 0755))
+		assert.NoError(t, os.WriteFile(filepath.Join(dir, "clean.tf"), []byte(`resource "google_container_cluster" "a" {
+  name = "a"
+}
+`), 0644))
+		assert.NoError(t, os.WriteFile(filepath.Join(dir, "envs", "prod", "cluster.tf"), []byte(emptyClusterHCL), 0644))
+
+		err := runRootCmd(t, "--dir", dir, "--check")
+		if assert.Error(t, err) {
+			assert.Contains(t, err.Error(), "1 file(s)")
+			assert.Contains(t, err.Error(), filepath.Join("envs", "prod", "cluster.tf"))
+			assert.NotContains(t, err.Error(), "clean.tf")
+		}
+	})
+}
+
+func TestDirFlag(t *testing.T) {
+	dir := t.TempDir()
+	assert.NoError(t, os.MkdirAll(filepath.Join(dir, "modules"), 0755))
+	assert.NoError(t, os.MkdirAll(filepath.Join(dir, ".terraform"), 0755))
+	for _, name := range []string{"main.tf", filepath.Join("modules", "cluster.tf"), filepath.Join(".terraform", "cached.tf")} {
+		assert.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(emptyClusterHCL), 0644))
+	}
+
+	var out bytes.Buffer
+	rootCmd := NewRootCmd(zap.NewNop())
+	rootCmd.SetOut(&out)
+	rootCmd.SetArgs([]string{"--dir", dir})
+	assert.NoError(t, rootCmd.Execute())
+	assert.Equal(t, "cleaned main.tf: -1 +0 lines\ncleaned modules/cluster.tf: -1 +0 lines\ncleaned 2 file(s): -2 +0 lines\n", out.String())
+
+	cached, err := os.ReadFile(filepath.Join(dir, ".terraform", "cached.tf"))
+	assert.NoError(t, err)
+	assert.Equal(t, emptyClusterHCL, string(cached), "Hidden directories must be skipped")
+
+	assert.Error(t, runRootCmd(t, "--dir", dir, "--file", filepath.Join(dir, "main.tf")), "--file and --dir are mutually exclusive")
+	assert.Error(t, runRootCmd(t), "One of --file or --dir is required")
+}
+
+func TestFixReferencesFlag(t *testing.T) {
+	path := writeTempHCL(t, emptyClusterHCL+`
+resource "google_container_node_pool" "pool" {
+  name    = "pool"
+  cluster = google_container_cluster.empty.name
+}
+`)
+	core, logs := observer.New(zap.WarnLevel)
+	rootCmd := NewRootCmd(zap.New(core))
+	rootCmd.SetArgs([]string{"--file", path, "--remove-empty-resources", "--fix-references"})
+	assert.NoError(t, rootCmd.Execute())
+	assert.Equal(t, 1, logs.FilterMessage("Orphaned reference").Len())
+
+	content, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.NotContains(t, string(content), "google_container_cluster.empty")
+	assert.Contains(t, string(content), `resource "google_container_node_pool" "pool"`)
+}
+
+func TestFileGlob(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{
+		filepath.Join("modules", "cluster.tf"),
+		filepath.Join("modules", "prod", "cluster.tf"),
+		filepath.Join("modules", "prod", "network.tf"),
+		filepath.Join("envs", "cluster.tf"),
+	} {
+		assert.NoError(t, os.MkdirAll(filepath.Dir(filepath.Join(dir, name)), 0755))
+		assert.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(emptyClusterHCL), 0644))
+	}
+
+	t.Run("Double star matches a subset of files", func(t *testing.T) {
+		var out bytes.Buffer
+		rootCmd := NewRootCmd(zap.NewNop())
+		rootCmd.SetOut(&out)
+		rootCmd.SetArgs([]string{"--file", filepath.Join(dir, "modules", "**", "cluster.tf"), "--check"})
+		err := rootCmd.Execute()
+		if assert.Error(t, err) {
+			assert.Contains(t, err.Error(), "2 file(s)")
+		}
+		assert.Contains(t, out.String(), "would clean "+filepath.Join(dir, "modules", "cluster.tf")+": -1 +0 lines\n"+
+			"would clean "+filepath.Join(dir, "modules", "prod", "cluster.tf")+": -1 +0 lines\n")
+		assert.NotContains(t, out.String(), "network.tf")
+	})
+
+	t.Run("Single star pattern", func(t *testing.T) {
+		assert.NoError(t, runRootCmd(t, "--file", filepath.Join(dir, "envs", "*.tf")))
+
+		content, err := os.ReadFile(filepath.Join(dir, "envs", "cluster.tf"))
+		assert.NoError(t, err)
+		assert.NotContains(t, string(content), "id ")
+		content, err = os.ReadFile(filepath.Join(dir, "modules", "cluster.tf"))
+		assert.NoError(t, err)
+		assert.Equal(t, emptyClusterHCL, string(content), "Files not matching the pattern must not be modified")
+	})
+
+	t.Run("No match is an error", func(t *testing.T) {
+		err := runRootCmd(t, "--file", filepath.Join(dir, "**", "missing.tf"))
+		if assert.Error(t, err) {
+			assert.Contains(t, err.Error(), "matched no files")
+		}
+	})
+}
+
+func TestSortFlag(t *testing.T) {
+	path := writeTempHCL(t, `resource "google_container_cluster" "primary" {
+  name     = "my-cluster"
+  location = "us-central1"
+}
+`)
+	assert.NoError(t, runRootCmd(t, "--file", path, "--sort"))
+
+	content, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, `resource "google_container_cluster" "primary" {
+  location = "us-central1"
+  name     = "my-cluster"
+}
+`, string(content))
+}
+
+func TestJSONFlag(t *testing.T) {
+	dir := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "a.tf"), []byte(emptyClusterHCL), 0644))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "b.tf"), []byte(`resource "google_container_cluster" "b" {
+  name = "b"
+}
+`), 0644))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "broken.tf"), []byte(`resource "google_container_cluster" {`), 0644))
+
+	var out bytes.Buffer
+	rootCmd := NewRootCmd(zap.NewNop())
+	rootCmd.SetOut(&out)
+	rootCmd.SetArgs([]string{"--dir", dir, "--json"})
+	assert.Error(t, rootCmd.Execute(), "The broken file must still make the command fail")
+
+	var summary jsonSummary
+	if !assert.NoError(t, json.Unmarshal(out.Bytes(), &summary), "stdout must be valid JSON: %s", out.String()) {
+		return
+	}
+	assert.Equal(t, 1, summary.TotalModifications)
+	assert.Equal(t, 1, summary.FilesModified)
+	assert.Equal(t, 1, summary.FilesFailed)
+	if assert.Len(t, summary.Files, 3) {
+		assert.Equal(t, "a.tf", summary.Files[0].File)
+		assert.Equal(t, 1, summary.Files[0].Modifications)
+		assert.True(t, summary.Files[0].Written)
+		assert.Equal(t, []string{"Remove attribute '[id]' from 'google_container_cluster'"}, summary.Files[0].AppliedRules)
+		assert.Empty(t, summary.Files[0].Errors)
+
+		assert.Equal(t, 0, summary.Files[1].Modifications)
+		assert.Empty(t, summary.Files[1].AppliedRules)
+
+		assert.Equal(t, "broken.tf", summary.Files[2].File)
+		assert.Len(t, summary.Files[2].Errors, 1)
+	}
+}
+
+func TestConcurrencyFlag(t *testing.T) {
+	const fileCount = 50
+	for _, concurrency := range []string{"1", "8", "100"} {
+		t.Run("concurrency "+concurrency, func(t *testing.T) {
+			dir := t.TempDir()
+			for i := 0; i < fileCount; i++ {
+				content := emptyClusterHCL
+				if i%2 == 1 {
+					content = "resource \"google_container_cluster\" \"clean\" {\n  name = \"clean\"\n}\n"
+				}
+				assert.NoError(t, os.WriteFile(filepath.Join(dir, fmt.Sprintf("cluster_%02d.tf", i)), []byte(content), 0644))
+			}
+
+			var out bytes.Buffer
+			rootCmd := NewRootCmd(zap.NewNop())
+			rootCmd.SetOut(&out)
+			rootCmd.SetArgs([]string{"--dir", dir, "--json", "--concurrency", concurrency})
+			assert.NoError(t, rootCmd.Execute())
+
+			var summary jsonSummary
+			if !assert.NoError(t, json.Unmarshal(out.Bytes(), &summary), "stdout must be valid JSON: %s", out.String()) {
+				return
+			}
+			assert.Equal(t, fileCount/2, summary.TotalModifications)
+			assert.Equal(t, fileCount/2, summary.FilesModified)
+			assert.Equal(t, 0, summary.FilesFailed)
+			if assert.Len(t, summary.Files, fileCount) {
+				for i, file := range summary.Files {
+					assert.Equal(t, fmt.Sprintf("cluster_%02d.tf", i), file.File, "Results must keep the order of the files")
+					assert.Equal(t, 1-i%2, file.Modifications)
+				}
+			}
+		})
+	}
+
+	assert.Error(t, runRootCmd(t, "--dir", t.TempDir(), "--concurrency", "0"))
+}
+
+func TestRuleIncludeExcludeFlags(t *testing.T) {
+	const hclContent = `resource "google_container_cluster" "primary" {
+  name               = "primary-cluster"
+  location           = "us-central1"
+  id                 = "projects/p/locations/us-central1/clusters/primary-cluster"
+  enable_legacy_abac = false
+}
+`
+	tests := []struct {
+		name            string
+		args            []string
+		expectIDRemoved bool
+		expectABACKept  bool
+	}{
+		{
+			name:            "Only included rules run",
+			args:            []string{"--rule-include", "Legacy ABAC"},
+			expectIDRemoved: false,
+			expectABACKept:  false,
+		},
+		{
+			name:            "Excluded rule leaves its attribute untouched",
+			args:            []string{"--rule-exclude", "'[id]'"},
+			expectIDRemoved: false,
+			expectABACKept:  false,
+		},
+		{
+			name:            "Exclusion wins over inclusion",
+			args:            []string{"--rule-include", "Legacy ABAC", "--rule-include", "'[id]'", "--rule-exclude", "Legacy*"},
+			expectIDRemoved: true,
+			expectABACKept:  true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			path := writeTempHCL(t, hclContent)
+			assert.NoError(t, runRootCmd(t, append([]string{"--file", path}, tc.args...)...))
+
+			content, err := os.ReadFile(path)
+			assert.NoError(t, err)
+			assert.Equal(t, !tc.expectIDRemoved, bytes.Contains(content, []byte("id ")), "id attribute: %s", content)
+			assert.Equal(t, tc.expectABACKept, bytes.Contains(content, []byte("enable_legacy_abac")), "enable_legacy_abac attribute: %s", content)
+		})
+	}
+
+	t.Run("Filtering out every rule is an error", func(t *testing.T) {
+		path := writeTempHCL(t, hclContent)
+		assert.Error(t, runRootCmd(t, "--file", path, "--rule-exclude", "*"))
+		content, err := os.ReadFile(path)
+		assert.NoError(t, err)
+		assert.Equal(t, hclContent, string(content))
+	})
+}
+
+func TestStdinFlag(t *testing.T) {
+	t.Run("Writes cleaned HCL to stdout", func(t *testing.T) {
+		var out bytes.Buffer
+		rootCmd := NewRootCmd(zap.NewNop())
+		rootCmd.SetIn(bytes.NewBufferString(emptyClusterHCL))
+		rootCmd.SetOut(&out)
+		rootCmd.SetArgs([]string{"--stdin"})
+		assert.NoError(t, rootCmd.Execute())
+
+		assert.Equal(t, `resource "google_container_cluster" "empty" {
+  name     = "empty-cluster"
+  location = "us-central1"
+}
+`, out.String())
+	})
+
+	t.Run("Invalid HCL fails", func(t *testing.T) {
+		var out bytes.Buffer
+		rootCmd := NewRootCmd(zap.NewNop())
+		rootCmd.SetIn(bytes.NewBufferString(`resource "google_container_cluster" {`))
+		rootCmd.SetOut(&out)
+		rootCmd.SetArgs([]string{"--stdin"})
+		assert.Error(t, rootCmd.Execute())
+		assert.Empty(t, out.String(), "Nothing must be written for HCL that can't be parsed")
+	})
+
+	t.Run("Can't be combined with --file", func(t *testing.T) {
+		path := writeTempHCL(t, emptyClusterHCL)
+		err := runRootCmd(t, "--stdin", "--file", path)
+		assert.ErrorContains(t, err, "--stdin can't be combined with --file or --dir")
+
+		content, readErr := os.ReadFile(path)
+		assert.NoError(t, readErr)
+		assert.Equal(t, emptyClusterHCL, string(content), "The file must not be modified")
+	})
+}
+
+func TestRulesRegistry(t *testing.T) {
+	registry := rules.Registry()
+	assert.Len(t, rules.AllRules(), len(registry))
+
+	names := make(map[string]bool, len(registry))
+	for _, registered := range registry {
+		assert.False(t, names[registered.Rule.Name], "Rule names must be unique: %q", registered.Rule.Name)
+		names[registered.Rule.Name] = true
+		assert.NotEmpty(t, registered.Category, "Rule %q has no category", registered.Rule.Name)
+		assert.NotEmpty(t, registered.Description, "Rule %q has no description", registered.Rule.Name)
+	}
+	for _, known := range []types.Rule{
+		rules.ClusterIPV4CIDRRuleDefinition,
+		rules.SetMinVersionRule,
+		rules.PodSecurityPolicyConfigRuleDefinition,
+		rules.EmptyNodeConfigRuleDefinition,
+		rules.KubeletConfigDefaultsRules[0],
+		rules.AnalysisRules[0],
+	} {
+		assert.True(t, names[known.Name], "Registry is missing %q", known.Name)
+	}
+}
+
+func TestListRulesFlag(t *testing.T) {
+	var out bytes.Buffer
+	rootCmd := NewRootCmd(zap.NewNop())
+	rootCmd.SetOut(&out)
+	rootCmd.SetArgs([]string{"--list-rules"})
+	assert.NoError(t, rootCmd.Execute(), "--list-rules doesn't need --file or --dir")
+
+	for _, registered := range rules.Registry() {
+		assert.Contains(t, out.String(), registered.Rule.Name+"\n    ["+registered.Category+"] "+registered.Description+"\n")
+	}
+
+	t.Run("Honors rule filters", func(t *testing.T) {
+		var out bytes.Buffer
+		rootCmd := NewRootCmd(zap.NewNop())
+		rootCmd.SetOut(&out)
+		rootCmd.SetArgs([]string{"--list-rules", "--rule-include", "Pod Security Policy Rule"})
+		assert.NoError(t, rootCmd.Execute())
+		assert.Equal(t, rules.PodSecurityPolicyConfigRuleDefinition.Name+"\n    [security] Removes the deprecated pod_security_policy_config block.\n", out.String())
+	})
+}
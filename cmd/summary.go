@@ -0,0 +1,93 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"github.com/kotatut/cluster_import_cleaner/hclmodifier/types"
+)
+
+// jsonSummary is the document printed to stdout by --json.
+type jsonSummary struct {
+	Files []jsonFileSummary `json:"files"`
+	// TotalModifications is the sum of the modifications of all files.
+	TotalModifications int `json:"totalModifications"`
+	// FilesModified is the number of files with at least one modification.
+	FilesModified int `json:"filesModified"`
+	// FilesFailed is the number of files with at least one error.
+	FilesFailed int `json:"filesFailed"`
+
+	// maxErrors caps the rule errors listed per file, as --max-errors does; zero means no limit.
+	maxErrors int
+}
+
+// jsonFileSummary describes the outcome of processing a single file.
+type jsonFileSummary struct {
+	File          string   `json:"file"`
+	Modifications int      `json:"modifications"`
+	LinesAdded    int      `json:"linesAdded"`
+	LinesRemoved  int      `json:"linesRemoved"`
+	Written       bool     `json:"written"`
+	AppliedRules  []string `json:"appliedRules"`
+	Warnings      int      `json:"warnings"`
+	// Errors holds the message of every rule error (or of the first --max-errors ones, then "+N more"), followed by
+	// the error that stopped processing the file, if any.
+	Errors []string `json:"errors"`
+}
+
+// add records the outcome of processing one file; err is the error returned by ProcessFile.
+func (s *jsonSummary) add(displayName string, result FileResult, err error) {
+	fileSummary := jsonFileSummary{
+		File:          displayName,
+		Modifications: result.Modifications,
+		LinesAdded:    result.LinesAdded,
+		LinesRemoved:  result.LinesRemoved,
+		Written:       result.Written,
+		AppliedRules:  result.Report.AppliedRuleNames(),
+		Warnings:      len(result.Report.Warnings),
+		Errors:        []string{},
+	}
+	if fileSummary.AppliedRules == nil {
+		fileSummary.AppliedRules = []string{}
+	}
+	shown, more := limitErrors(result.RuleErrors, s.maxErrors)
+	for _, ruleErr := range shown {
+		fileSummary.Errors = append(fileSummary.Errors, ruleErr.Error())
+	}
+	if more > 0 {
+		fileSummary.Errors = append(fileSummary.Errors, fmt.Sprintf("+%d more", more))
+	}
+	if err != nil {
+		fileSummary.Errors = append(fileSummary.Errors, err.Error())
+	}
+
+	s.Files = append(s.Files, fileSummary)
+	s.TotalModifications += result.Modifications
+	if result.Modifications > 0 {
+		s.FilesModified++
+	}
+	if len(fileSummary.Errors) > 0 {
+		s.FilesFailed++
+	}
+}
+
+// write prints the summary as indented JSON.
+func (s *jsonSummary) write(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(s)
+}
+
+// writeRuleStats prints the table shown by --verbose: for each rule applied to the file shown as displayName, how
+// many resources it matched, how many met its conditions and how many it acted on.
+func writeRuleStats(w io.Writer, displayName string, stats []types.RuleStats) error {
+	fmt.Fprintf(w, "rules applied to %s:\n", displayName)
+	table := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(table, "RULE\tMATCHED\tCONDITIONS MET\tACTED")
+	for _, ruleStats := range stats {
+		fmt.Fprintf(table, "%s\t%d\t%d\t%d\n", ruleStats.RuleName, ruleStats.Matched, ruleStats.ConditionsMet, ruleStats.Acted)
+	}
+	return table.Flush()
+}
@@ -0,0 +1,99 @@
+package hclmodifier
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+
+	"github.com/kotatut/cluster_import_cleaner/hclmodifier/rules"
+	"github.com/kotatut/cluster_import_cleaner/hclmodifier/types"
+)
+
+func TestAnalyzeZonalLocationRule(t *testing.T) {
+	tests := []struct {
+		name             string
+		fixture          string
+		expectedWarnings int
+	}{
+		{
+			name:             "Zonal location with multi-zone node_locations",
+			fixture:          "testdata/TestAnalyzeLocationRule_ZonalMultiZone.tf",
+			expectedWarnings: 1,
+		},
+		{
+			name:             "Zonal location with a single node location",
+			fixture:          "testdata/TestAnalyzeLocationRule_ZonalSingleZone.tf",
+			expectedWarnings: 0,
+		},
+		{
+			name:             "Regional location with multi-zone node_locations",
+			fixture:          "testdata/TestAnalyzeLocationRule_RegionalMultiZone.tf",
+			expectedWarnings: 0,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			original, err := os.ReadFile(tc.fixture)
+			if err != nil {
+				t.Fatalf("Failed to read fixture %s: %v", tc.fixture, err)
+			}
+			modifier, err := NewFromFile(tc.fixture, zap.NewNop())
+			if err != nil {
+				t.Fatalf("NewFromFile() error = %v", err)
+			}
+
+			report, errs := modifier.ApplyRulesWithReport(rules.AnalysisRules)
+			assert.Empty(t, errs)
+			assert.Empty(t, report.Changes, "Analysis rules must not modify the file")
+			assert.Len(t, report.Warnings, tc.expectedWarnings)
+			for _, warning := range report.Warnings {
+				assert.Equal(t, rules.ZonalLocationMultiZoneNodeLocationsRule.Name, warning.RuleName)
+				assert.Equal(t, []string{"google_container_cluster", "primary"}, warning.ResourceLabels)
+			}
+			assert.Equal(t, string(original), string(modifier.File().Bytes()))
+		})
+	}
+}
+
+func TestAnalyzeKubernetesAlphaRule(t *testing.T) {
+	tests := []struct {
+		name                  string
+		fixture               string
+		expectedModifications int
+		expectedWarnings      int
+	}{
+		{
+			name:                  "enable_kubernetes_alpha is false",
+			fixture:               "testdata/TestApplyRemovedFeatureFlagsRules_KubernetesAlphaFalse.tf",
+			expectedModifications: 1,
+			expectedWarnings:      0,
+		},
+		{
+			name:                  "enable_kubernetes_alpha is true",
+			fixture:               "testdata/TestApplyRemovedFeatureFlagsRules_AlphaCluster.tf",
+			expectedModifications: 0,
+			expectedWarnings:      1,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			core, logs := observer.New(zap.WarnLevel)
+			modifier, err := NewFromFile(tc.fixture, zap.New(core))
+			if err != nil {
+				t.Fatalf("NewFromFile() error = %v", err)
+			}
+
+			rulesToApply := append([]types.Rule{rules.KubernetesAlphaClusterRule}, rules.RemovedFeatureFlagsRules...)
+			modifications, errs := modifier.ApplyRules(rulesToApply)
+			assert.Empty(t, errs)
+			assert.Equal(t, tc.expectedModifications, modifications)
+			assert.Equal(t, tc.expectedWarnings, logs.FilterMessage("Rule reported a warning.").Len())
+			assertMatchesGolden(t, modifier, tc.fixture+".golden")
+		})
+	}
+}
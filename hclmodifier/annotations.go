@@ -0,0 +1,71 @@
+package hclmodifier
+
+import (
+	"strings"
+
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+)
+
+// disableAnnotation is the comment directive that opts a resource out of cleanup. It must be placed in
+// the comment lines directly above the resource block:
+//
+//	# gke-tf-cleaner:disable                        skips all rules for the resource
+//	# gke-tf-cleaner:disable=Autopilot Cleanup      skips the rules whose name starts with "Autopilot Cleanup"
+//
+// Several rule names (or name prefixes) can be given, separated by commas.
+const disableAnnotation = "gke-tf-cleaner:disable"
+
+// isRuleDisabledForBlock reports whether a disable annotation in the comments leading block
+// disables the rule named ruleName.
+func isRuleDisabledForBlock(block *hclwrite.Block, ruleName string) bool {
+	for _, token := range block.BuildTokens(nil) {
+		if token.Type != hclsyntax.TokenComment {
+			// Lead comments come first; the block itself starts at the first other token.
+			break
+		}
+		disabledRules, ok := parseDisableAnnotation(string(token.Bytes))
+		if !ok {
+			continue
+		}
+		if len(disabledRules) == 0 {
+			return true
+		}
+		for _, disabledRule := range disabledRules {
+			if strings.HasPrefix(ruleName, disabledRule) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// parseDisableAnnotation parses a single comment. ok is true if it's a disable annotation, in which case
+// the returned names are the rules it disables, or empty if it disables all rules.
+func parseDisableAnnotation(comment string) (ruleNames []string, ok bool) {
+	text := strings.TrimSpace(comment)
+	for _, marker := range []string{"#", "//", "/*"} {
+		if strings.HasPrefix(text, marker) {
+			text = strings.TrimPrefix(text, marker)
+			break
+		}
+	}
+	text = strings.TrimSpace(strings.TrimSuffix(text, "*/"))
+
+	if !strings.HasPrefix(text, disableAnnotation) {
+		return nil, false
+	}
+	rest := strings.TrimPrefix(text, disableAnnotation)
+	if rest == "" {
+		return nil, true
+	}
+	if !strings.HasPrefix(rest, "=") {
+		return nil, false
+	}
+	for _, name := range strings.Split(strings.TrimPrefix(rest, "="), ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			ruleNames = append(ruleNames, name)
+		}
+	}
+	return ruleNames, true
+}
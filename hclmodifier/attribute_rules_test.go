@@ -92,6 +92,50 @@ func TestRuleSetAttributeValue(t *testing.T) {
 				new_attribute = "new_value"
 			}`,
 		},
+		{
+			name: "OnlyIfAbsent sets missing attribute",
+			hclContent: `resource "google_container_cluster" "test" {
+			}`,
+			rule: types.Rule{
+				Name:               "TestOnlyIfAbsentMissing",
+				TargetResourceType: "google_container_cluster",
+				Actions:            []types.RuleAction{{Type: types.SetAttributeValue, Path: []string{"networking_mode"}, ValueToSet: "VPC_NATIVE", OnlyIfAbsent: true}},
+			},
+			expectedModifications: 1,
+			expectedHCLContent: `resource "google_container_cluster" "test" {
+				networking_mode = "VPC_NATIVE"
+			}`,
+		},
+		{
+			name: "OnlyIfAbsent keeps existing different value",
+			hclContent: `resource "google_container_cluster" "test" {
+				networking_mode = "ROUTES"
+			}`,
+			rule: types.Rule{
+				Name:               "TestOnlyIfAbsentDifferent",
+				TargetResourceType: "google_container_cluster",
+				Actions:            []types.RuleAction{{Type: types.SetAttributeValue, Path: []string{"networking_mode"}, ValueToSet: "VPC_NATIVE", OnlyIfAbsent: true}},
+			},
+			expectedModifications: 0,
+			expectedHCLContent: `resource "google_container_cluster" "test" {
+				networking_mode = "ROUTES"
+			}`,
+		},
+		{
+			name: "OnlyIfAbsent keeps existing same value",
+			hclContent: `resource "google_container_cluster" "test" {
+				networking_mode = "VPC_NATIVE"
+			}`,
+			rule: types.Rule{
+				Name:               "TestOnlyIfAbsentSame",
+				TargetResourceType: "google_container_cluster",
+				Actions:            []types.RuleAction{{Type: types.SetAttributeValue, Path: []string{"networking_mode"}, ValueToSet: "VPC_NATIVE", OnlyIfAbsent: true}},
+			},
+			expectedModifications: 0,
+			expectedHCLContent: `resource "google_container_cluster" "test" {
+				networking_mode = "VPC_NATIVE"
+			}`,
+		},
 	}
 
 	for _, tc := range tests {
@@ -165,8 +209,9 @@ func TestApplyMoreComputedAttributesRules(t *testing.T) {
 			expectedModifications: 1,
 		},
 		{
-			name: "Remove node_pool.autoscaling total_counts",
+			name: "Remove node_pool.autoscaling total_counts for zonal cluster",
 			hclContent: `resource "google_container_cluster" "test_np_computed" {
+  location = "us-central1-a"
   node_pool {
     name = "pool1"
     autoscaling {
@@ -198,6 +243,7 @@ func TestApplyMoreComputedAttributesRules(t *testing.T) {
   }
 }`,
 			expectedHCLContent: `resource "google_container_cluster" "test_np_computed" {
+  location = "us-central1-a"
   node_pool {
     name = "pool1"
     autoscaling {
@@ -224,7 +270,7 @@ func TestApplyMoreComputedAttributesRules(t *testing.T) {
 }
   }
 }`,
-			rulesToApply:          rules.OtherComputedAttributesRules,
+			rulesToApply:          []types.Rule{rules.ZonalTotalNodeCountsRuleDefinition},
 			expectedModifications: 4, // pool1 (max, min), pool2 (max), pool5 (min)
 		},
 	}
@@ -269,3 +315,742 @@ func TestApplyMoreComputedAttributesRules(t *testing.T) {
 		})
 	}
 }
+
+func TestRuleRemoveListElementsMatching(t *testing.T) {
+	logger := zap.NewNop()
+	tests := []struct {
+		name                  string
+		hclContent            string
+		pattern               string
+		expectedModifications int
+		expectError           bool
+		expectedHCLContent    string
+	}{
+		{
+			name: "Some string elements match",
+			hclContent: `resource "google_container_cluster" "test" {
+  node_locations = ["us-central1-a", "us-east1-b", "us-central1-c"]
+}`,
+			pattern:               "^us-central1-",
+			expectedModifications: 2,
+			expectedHCLContent: `resource "google_container_cluster" "test" {
+  node_locations = ["us-east1-b"]
+}`,
+		},
+		{
+			name: "No elements match",
+			hclContent: `resource "google_container_cluster" "test" {
+  node_locations = ["us-east1-b", "us-east1-c"]
+}`,
+			pattern:               "^us-central1-",
+			expectedModifications: 0,
+			expectedHCLContent: `resource "google_container_cluster" "test" {
+  node_locations = ["us-east1-b", "us-east1-c"]
+}`,
+		},
+		{
+			name: "All elements match removes the attribute",
+			hclContent: `resource "google_container_cluster" "test" {
+  name           = "test"
+  node_locations = ["us-central1-a"]
+}`,
+			pattern:               "^us-central1-",
+			expectedModifications: 1,
+			expectedHCLContent: `resource "google_container_cluster" "test" {
+  name = "test"
+}`,
+		},
+		{
+			name: "Numeric elements are matched by string form",
+			hclContent: `resource "google_container_cluster" "test" {
+  node_locations = [8080, 443]
+}`,
+			pattern:               "^80",
+			expectedModifications: 1,
+			expectedHCLContent: `resource "google_container_cluster" "test" {
+  node_locations = [443]
+}`,
+		},
+		{
+			name: "Attribute missing is a no-op",
+			hclContent: `resource "google_container_cluster" "test" {
+  name = "test"
+}`,
+			pattern:               "^us-central1-",
+			expectedModifications: 0,
+			expectedHCLContent: `resource "google_container_cluster" "test" {
+  name = "test"
+}`,
+		},
+		{
+			name: "Non-list attribute returns an error",
+			hclContent: `resource "google_container_cluster" "test" {
+  node_locations = "us-central1-a"
+}`,
+			pattern:               "^us-central1-",
+			expectedModifications: 0,
+			expectError:           true,
+			expectedHCLContent: `resource "google_container_cluster" "test" {
+  node_locations = "us-central1-a"
+}`,
+		},
+		{
+			name: "Non-literal attribute is a no-op",
+			hclContent: `resource "google_container_cluster" "test" {
+  node_locations = var.zones
+}`,
+			pattern:               "^us-central1-",
+			expectedModifications: 0,
+			expectedHCLContent: `resource "google_container_cluster" "test" {
+  node_locations = var.zones
+}`,
+		},
+		{
+			name: "Invalid pattern returns an error",
+			hclContent: `resource "google_container_cluster" "test" {
+  node_locations = ["us-central1-a"]
+}`,
+			pattern:               "([",
+			expectedModifications: 0,
+			expectError:           true,
+			expectedHCLContent: `resource "google_container_cluster" "test" {
+  node_locations = ["us-central1-a"]
+}`,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			hclFile, diags := hclwrite.ParseConfig([]byte(tc.hclContent), "test.hcl", hcl.InitialPos)
+			assert.False(t, diags.HasErrors(), "Failed to parse HCL content: %v", diags)
+			modifier := &Modifier{file: hclFile, Logger: logger}
+
+			rule := types.Rule{
+				Name:               "TestRemoveListElementsMatching",
+				TargetResourceType: "google_container_cluster",
+				Actions:            []types.RuleAction{{Type: types.RemoveListElementsMatching, Path: []string{"node_locations"}, Pattern: tc.pattern}},
+			}
+			modifications, errs := modifier.ApplyRules([]types.Rule{rule})
+			if tc.expectError {
+				assert.NotEmpty(t, errs, "ApplyRules should return an error")
+			} else {
+				assert.Empty(t, errs, "ApplyRules should not return errors")
+			}
+			assert.Equal(t, tc.expectedModifications, modifications)
+
+			expectedF, diags := hclwrite.ParseConfig([]byte(tc.expectedHCLContent), "expected.hcl", hcl.InitialPos)
+			assert.False(t, diags.HasErrors(), "Failed to parse expected HCL content: %v", diags)
+			assert.Equal(t, string(hclwrite.Format(expectedF.Bytes())), string(hclwrite.Format(modifier.File().Bytes())), "HCL content mismatch")
+		})
+	}
+}
+
+func TestApplyStatusComputedAttributesRules(t *testing.T) {
+	tests := []struct {
+		name                  string
+		fixture               string
+		expectedModifications int
+	}{
+		{
+			name:                  "Computed attributes are removed from clusters",
+			fixture:               "testdata/TestApplyStatusComputedAttributesRules_Computed.tf",
+			expectedModifications: 4,
+		},
+		{
+			name:                  "Other resource types are kept",
+			fixture:               "testdata/TestApplyStatusComputedAttributesRules_OtherResourceType.tf",
+			expectedModifications: 0,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			modifier, modifications := applyRulesToFixture(t, tc.fixture, rules.StatusComputedAttributesRules)
+			assert.Equal(t, tc.expectedModifications, modifications)
+			assertMatchesGolden(t, modifier, tc.fixture+".golden")
+		})
+	}
+}
+
+func TestApplyFleetComputedAttributesRules(t *testing.T) {
+	tests := []struct {
+		name                  string
+		fixture               string
+		expectedModifications int
+	}{
+		{
+			name:                  "Computed fields are removed",
+			fixture:               "testdata/TestApplyFleetComputedAttributesRules_Computed.tf",
+			expectedModifications: 4,
+		},
+		{
+			name:                  "User-configured fleet is kept",
+			fixture:               "testdata/TestApplyFleetComputedAttributesRules_UserConfigured.tf",
+			expectedModifications: 0,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			modifier, modifications := applyRulesToFixture(t, tc.fixture, rules.FleetComputedAttributesRules)
+			assert.Equal(t, tc.expectedModifications, modifications)
+			assertMatchesGolden(t, modifier, tc.fixture+".golden")
+		})
+	}
+}
+
+func TestApplyEmptyResourceLabelsRule(t *testing.T) {
+	tests := []struct {
+		name                  string
+		fixture               string
+		expectedModifications int
+	}{
+		{
+			name:                  "Empty resource_labels is removed",
+			fixture:               "testdata/TestApplyEmptyResourceLabelsRule_Empty.tf",
+			expectedModifications: 1,
+		},
+		{
+			name:                  "Non-empty resource_labels is kept",
+			fixture:               "testdata/TestApplyEmptyResourceLabelsRule_Mixed.tf",
+			expectedModifications: 0,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			modifier, modifications := applyRulesToFixture(t, tc.fixture, []types.Rule{rules.EmptyResourceLabelsRuleDefinition})
+			assert.Equal(t, tc.expectedModifications, modifications)
+			assertMatchesGolden(t, modifier, tc.fixture+".golden")
+		})
+	}
+}
+
+func TestApplyManagedResourceLabelsRule(t *testing.T) {
+	tests := []struct {
+		name                  string
+		fixture               string
+		expectedModifications int
+	}{
+		{
+			name:                  "Managed and user labels",
+			fixture:               "testdata/TestApplyManagedResourceLabelsRule_Mixed.tf",
+			expectedModifications: 2,
+		},
+		{
+			name:                  "Only managed labels",
+			fixture:               "testdata/TestApplyManagedResourceLabelsRule_OnlyManaged.tf",
+			expectedModifications: 3, // two labels, then the empty resource_labels
+		},
+		{
+			name:                  "Only user labels",
+			fixture:               "testdata/TestApplyManagedResourceLabelsRule_UserOnly.tf",
+			expectedModifications: 0,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			// Listed first, the empty labels rule must still run after the managed labels are removed.
+			labelRules := []types.Rule{rules.EmptyResourceLabelsRuleDefinition, rules.ManagedResourceLabelsRuleDefinition}
+			modifier, modifications := applyRulesToFixture(t, tc.fixture, labelRules)
+			assert.Equal(t, tc.expectedModifications, modifications)
+			assertMatchesGolden(t, modifier, tc.fixture+".golden")
+		})
+	}
+}
+
+func TestApplyEmptyNodeLocationsRule(t *testing.T) {
+	tests := []struct {
+		name                  string
+		fixture               string
+		expectedModifications int
+	}{
+		{
+			name:                  "Empty list",
+			fixture:               "testdata/TestApplyEmptyNodeLocationsRule_Empty.tf",
+			expectedModifications: 1,
+		},
+		{
+			name:                  "Non-empty list",
+			fixture:               "testdata/TestApplyEmptyNodeLocationsRule_NonEmpty.tf",
+			expectedModifications: 0,
+		},
+		{
+			name:                  "Not a list",
+			fixture:               "testdata/TestApplyEmptyNodeLocationsRule_NotAList.tf",
+			expectedModifications: 0,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			modifier, modifications := applyRulesToFixture(t, tc.fixture, []types.Rule{rules.EmptyNodeLocationsRuleDefinition})
+			assert.Equal(t, tc.expectedModifications, modifications)
+			assertMatchesGolden(t, modifier, tc.fixture+".golden")
+		})
+	}
+}
+
+func TestApplyZonalNodeLocationsOverlapRule(t *testing.T) {
+	tests := []struct {
+		name                  string
+		fixture               string
+		expectedModifications int
+	}{
+		{
+			name:                  "Cluster zone is removed",
+			fixture:               "testdata/TestApplyZonalNodeLocationsOverlapRule_Overlap.tf",
+			expectedModifications: 1,
+		},
+		{
+			name:                  "Only the cluster zone",
+			fixture:               "testdata/TestApplyZonalNodeLocationsOverlapRule_OnlyClusterZone.tf",
+			expectedModifications: 1,
+		},
+		{
+			name:                  "No overlap",
+			fixture:               "testdata/TestApplyZonalNodeLocationsOverlapRule_NoOverlap.tf",
+			expectedModifications: 0,
+		},
+		{
+			name:                  "Regional location",
+			fixture:               "testdata/TestApplyZonalNodeLocationsOverlapRule_Regional.tf",
+			expectedModifications: 0,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			modifier, modifications := applyRulesToFixture(t, tc.fixture, []types.Rule{rules.ZonalNodeLocationsOverlapRuleDefinition})
+			assert.Equal(t, tc.expectedModifications, modifications)
+			assertMatchesGolden(t, modifier, tc.fixture+".golden")
+		})
+	}
+}
+
+func TestApplyWorkloadIdentityRule(t *testing.T) {
+	tests := []struct {
+		name                  string
+		fixture               string
+		expectedModifications int
+	}{
+		{
+			name:                  "Deprecated identity_namespace is renamed",
+			fixture:               "testdata/TestApplyWorkloadIdentityRule_IdentityNamespace.tf",
+			expectedModifications: 1,
+		},
+		{
+			name:                  "workload_pool is left unchanged",
+			fixture:               "testdata/TestApplyWorkloadIdentityRule_WorkloadPool.tf",
+			expectedModifications: 0,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			modifier, modifications := applyRulesToFixture(t, tc.fixture, []types.Rule{rules.WorkloadIdentityNamespaceRuleDefinition})
+			assert.Equal(t, tc.expectedModifications, modifications)
+			assertMatchesGolden(t, modifier, tc.fixture+".golden")
+		})
+	}
+}
+
+func TestApplyWorkloadIdentityOauthScopesRule(t *testing.T) {
+	tests := []struct {
+		name                  string
+		fixture               string
+		expectedModifications int
+	}{
+		{
+			name:                  "Workload Identity enabled",
+			fixture:               "testdata/TestApplyWorkloadIdentityOauthScopesRule_Enabled.tf",
+			expectedModifications: 1,
+		},
+		{
+			name:                  "Workload Identity not configured",
+			fixture:               "testdata/TestApplyWorkloadIdentityOauthScopesRule_Disabled.tf",
+			expectedModifications: 0,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			modifier, modifications := applyRulesToFixture(t, tc.fixture, []types.Rule{rules.WorkloadIdentityOauthScopesRuleDefinition})
+			assert.Equal(t, tc.expectedModifications, modifications)
+			assertMatchesGolden(t, modifier, tc.fixture+".golden")
+		})
+	}
+}
+
+func TestApplyWorkloadIdentityDuplicateNamespaceRule(t *testing.T) {
+	tests := []struct {
+		name                  string
+		fixture               string
+		expectedModifications int
+	}{
+		{
+			name:                  "Both present",
+			fixture:               "testdata/TestApplyWorkloadIdentityDuplicateRule_BothPresent.tf",
+			expectedModifications: 1,
+		},
+		{
+			name:                  "Only deprecated identity_namespace",
+			fixture:               "testdata/TestApplyWorkloadIdentityDuplicateRule_OnlyIdentityNamespace.tf",
+			expectedModifications: 0,
+		},
+		{
+			name:                  "Only workload_pool",
+			fixture:               "testdata/TestApplyWorkloadIdentityDuplicateRule_OnlyWorkloadPool.tf",
+			expectedModifications: 0,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			modifier, modifications := applyRulesToFixture(t, tc.fixture, []types.Rule{rules.WorkloadIdentityDuplicateNamespaceRuleDefinition})
+			assert.Equal(t, tc.expectedModifications, modifications)
+			assertMatchesGolden(t, modifier, tc.fixture+".golden")
+		})
+	}
+}
+
+func TestRenameAttributeByPath(t *testing.T) {
+	t.Run("Expression tokens are preserved", func(t *testing.T) {
+		modifier := newTestModifier(t, `resource "google_container_cluster" "primary" {
+  workload_identity_config {
+    identity_namespace = "${var.project_id}.svc.id.goog"
+  }
+}`)
+		body := modifier.File().Body().Blocks()[0].Body()
+		modifications, err := modifier.RenameAttributeByPath(body, []string{"workload_identity_config", "identity_namespace"}, "workload_pool")
+		assert.NoError(t, err)
+		assert.Equal(t, 1, modifications)
+		assert.Contains(t, string(modifier.File().Bytes()), `workload_pool = "${var.project_id}.svc.id.goog"`)
+	})
+
+	t.Run("Position and comments are preserved", func(t *testing.T) {
+		modifier := newTestModifier(t, `resource "google_container_cluster" "primary" {
+  # The namespace of the workload identity pool.
+  old_name = "a" # Set on import.
+  name     = "b"
+}`)
+		body := modifier.File().Body().Blocks()[0].Body()
+		modifications, err := modifier.RenameAttributeByPath(body, []string{"old_name"}, "new_name")
+		assert.NoError(t, err)
+		assert.Equal(t, 1, modifications)
+		assert.Nil(t, body.GetAttribute("old_name"))
+		assert.NotNil(t, body.GetAttribute("new_name"))
+		assert.Equal(t, `resource "google_container_cluster" "primary" {
+  # The namespace of the workload identity pool.
+  new_name = "a" # Set on import.
+  name     = "b"
+}`, string(hclwrite.Format(modifier.File().Bytes())))
+	})
+
+	t.Run("Existing target name is an error", func(t *testing.T) {
+		modifier := newTestModifier(t, `resource "google_container_cluster" "primary" {
+  old_name = "a"
+  new_name = "b"
+}`)
+		body := modifier.File().Body().Blocks()[0].Body()
+		_, err := modifier.RenameAttributeByPath(body, []string{"old_name"}, "new_name")
+		assert.Error(t, err)
+	})
+
+	t.Run("Missing attribute is a no-op", func(t *testing.T) {
+		modifier := newTestModifier(t, `resource "google_container_cluster" "primary" {
+  name = "a"
+}`)
+		body := modifier.File().Body().Blocks()[0].Body()
+		modifications, err := modifier.RenameAttributeByPath(body, []string{"workload_identity_config", "identity_namespace"}, "workload_pool")
+		assert.NoError(t, err)
+		assert.Equal(t, 0, modifications)
+	})
+}
+
+func TestCommentOutAttributeByPath(t *testing.T) {
+	tests := []struct {
+		name                  string
+		hclContent            string
+		path                  []string
+		expectedModifications int
+		expectedHCLContent    string
+	}{
+		{
+			name: "Top-level attribute",
+			hclContent: `resource "google_container_cluster" "test" {
+  name            = "test"
+  logging_service = "logging.googleapis.com/kubernetes"
+}`,
+			path:                  []string{"logging_service"},
+			expectedModifications: 1,
+			expectedHCLContent: `resource "google_container_cluster" "test" {
+  name = "test"
+  # logging_service = "logging.googleapis.com/kubernetes"
+}`,
+		},
+		{
+			name: "Nested multi-line attribute",
+			hclContent: `resource "google_container_cluster" "test" {
+  node_config {
+    machine_type = "e2-medium"
+    labels = {
+      env = "dev"
+    }
+  }
+}`,
+			path:                  []string{"node_config", "labels"},
+			expectedModifications: 1,
+			expectedHCLContent: `resource "google_container_cluster" "test" {
+  node_config {
+    machine_type = "e2-medium"
+    # labels = {
+    #   env = "dev"
+    # }
+  }
+}`,
+		},
+		{
+			name: "Inline comment is kept",
+			hclContent: `resource "google_container_cluster" "test" {
+  name            = "test"
+  logging_service = "logging.googleapis.com/kubernetes" # Set on import.
+}`,
+			path:                  []string{"logging_service"},
+			expectedModifications: 1,
+			expectedHCLContent: `resource "google_container_cluster" "test" {
+  name = "test"
+  # logging_service = "logging.googleapis.com/kubernetes" # Set on import.
+}`,
+		},
+		{
+			name: "Inline comment of a multi-line attribute is kept",
+			hclContent: `resource "google_container_cluster" "test" {
+  node_config {
+    labels = {
+      env = "dev" # Inner comment.
+    } // Set on import.
+  }
+}`,
+			path:                  []string{"node_config", "labels"},
+			expectedModifications: 1,
+			expectedHCLContent: `resource "google_container_cluster" "test" {
+  node_config {
+    # labels = {
+    #   env = "dev" # Inner comment.
+    # } // Set on import.
+  }
+}`,
+		},
+		{
+			name: "Missing parent block is a no-op",
+			hclContent: `resource "google_container_cluster" "test" {
+  name = "test"
+}`,
+			path:                  []string{"node_config", "labels"},
+			expectedModifications: 0,
+			expectedHCLContent: `resource "google_container_cluster" "test" {
+  name = "test"
+}`,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			modifier := newTestModifier(t, tc.hclContent)
+			modifications, errs := modifier.ApplyRules([]types.Rule{
+				{
+					Name:               "Comment out attribute",
+					TargetResourceType: "google_container_cluster",
+					Actions:            []types.RuleAction{{Type: types.CommentOutAttribute, Path: tc.path}},
+				},
+			})
+			assert.Empty(t, errs)
+			assert.Equal(t, tc.expectedModifications, modifications)
+			assert.Equal(t, tc.expectedHCLContent, string(modifier.File().Bytes()))
+		})
+	}
+}
+
+func TestApplyTimeoutsRule(t *testing.T) {
+	tests := []struct {
+		name                  string
+		fixture               string
+		expectedModifications int
+	}{
+		{
+			name:                  "No timeouts block",
+			fixture:               "testdata/TestApplyTimeoutsRule_None.tf",
+			expectedModifications: 0,
+		},
+		{
+			name:                  "One empty timeouts block",
+			fixture:               "testdata/TestApplyTimeoutsRule_One.tf",
+			expectedModifications: 1,
+		},
+		{
+			name:                  "Multiple timeouts blocks",
+			fixture:               "testdata/TestApplyTimeoutsRule_Multiple.tf",
+			expectedModifications: 2, // Only the resource's own blocks; node_pool's timeouts is nested deeper.
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			modifier, modifications := applyRulesToFixture(t, tc.fixture, []types.Rule{rules.TimeoutsRuleDefinition})
+			assert.Equal(t, tc.expectedModifications, modifications)
+			assertMatchesGolden(t, modifier, tc.fixture+".golden")
+		})
+	}
+}
+
+func TestApplyLifecycleRule(t *testing.T) {
+	tests := []struct {
+		name                  string
+		fixture               string
+		expectedModifications int
+	}{
+		{
+			name:                  "lifecycle block",
+			fixture:               "testdata/TestApplyLifecycleRule_Present.tf",
+			expectedModifications: 1,
+		},
+		{
+			name:                  "No lifecycle block",
+			fixture:               "testdata/TestApplyLifecycleRule_None.tf",
+			expectedModifications: 0,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			modifier, modifications := applyRulesToFixture(t, tc.fixture, []types.Rule{rules.LifecycleRuleDefinition})
+			assert.Equal(t, tc.expectedModifications, modifications)
+			assertMatchesGolden(t, modifier, tc.fixture+".golden")
+		})
+	}
+}
+
+func TestApplyPodSecurityPolicyConfigRule(t *testing.T) {
+	tests := []struct {
+		name                  string
+		fixture               string
+		expectedModifications int
+	}{
+		{
+			name:                  "Block present",
+			fixture:               "testdata/TestApplyPodSecurityPolicyConfigRule_Present.tf",
+			expectedModifications: 1,
+		},
+		{
+			name:                  "Block absent",
+			fixture:               "testdata/TestApplyPodSecurityPolicyConfigRule_Absent.tf",
+			expectedModifications: 0,
+		},
+		{
+			name:                  "Block on another resource type",
+			fixture:               "testdata/TestApplyPodSecurityPolicyConfigRule_OtherResourceType.tf",
+			expectedModifications: 0,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			modifier, modifications := applyRulesToFixture(t, tc.fixture, []types.Rule{rules.PodSecurityPolicyConfigRuleDefinition})
+			assert.Equal(t, tc.expectedModifications, modifications)
+			assertMatchesGolden(t, modifier, tc.fixture+".golden")
+		})
+	}
+}
+
+func TestApplyDuplicateAddonsConfigRule(t *testing.T) {
+	tests := []struct {
+		name                  string
+		fixture               string
+		expectedModifications int
+	}{
+		{
+			name:                  "Duplicate sub-blocks",
+			fixture:               "testdata/TestApplyDuplicateAddonsConfigRule_Duplicates.tf",
+			expectedModifications: 2, // the second http_load_balancing and horizontal_pod_autoscaling blocks
+		},
+		{
+			name:                  "No duplicates",
+			fixture:               "testdata/TestApplyDuplicateAddonsConfigRule_NoDuplicates.tf",
+			expectedModifications: 0,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			modifier, modifications := applyRulesToFixture(t, tc.fixture, []types.Rule{rules.DuplicateAddonsConfigRuleDefinition})
+			assert.Equal(t, tc.expectedModifications, modifications)
+			assertMatchesGolden(t, modifier, tc.fixture+".golden")
+		})
+	}
+}
+
+func TestApplyRemovedFeatureFlagsRules(t *testing.T) {
+	tests := []struct {
+		name                  string
+		fixture               string
+		expectedModifications int
+	}{
+		{
+			name:                  "enable_tpu",
+			fixture:               "testdata/TestApplyRemovedFeatureFlagsRules_EnableTpu.tf",
+			expectedModifications: 1, // only on google_container_cluster, not google_container_node_pool
+		},
+		{
+			name:                  "enable_binary_authorization",
+			fixture:               "testdata/TestApplyRemovedFeatureFlagsRules_EnableBinaryAuthorization.tf",
+			expectedModifications: 1,
+		},
+		{
+			name:                  "enable_kubernetes_alpha = false",
+			fixture:               "testdata/TestApplyRemovedFeatureFlagsRules_KubernetesAlphaFalse.tf",
+			expectedModifications: 1,
+		},
+		{
+			name:                  "Alpha cluster",
+			fixture:               "testdata/TestApplyRemovedFeatureFlagsRules_AlphaCluster.tf",
+			expectedModifications: 0,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			modifier, modifications := applyRulesToFixture(t, tc.fixture, rules.RemovedFeatureFlagsRules)
+			assert.Equal(t, tc.expectedModifications, modifications)
+			assertMatchesGolden(t, modifier, tc.fixture+".golden")
+		})
+	}
+}
+
+func TestApplyVerticalPodAutoscalingRule(t *testing.T) {
+	tests := []struct {
+		name                  string
+		fixture               string
+		expectedModifications int
+	}{
+		{
+			name:                  "Disabled is removed",
+			fixture:               "testdata/TestApplyVerticalPodAutoscalingRule_Disabled.tf",
+			expectedModifications: 1,
+		},
+		{
+			name:                  "Enabled is kept",
+			fixture:               "testdata/TestApplyVerticalPodAutoscalingRule_Enabled.tf",
+			expectedModifications: 0,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			modifier, modifications := applyRulesToFixture(t, tc.fixture, []types.Rule{rules.VerticalPodAutoscalingRuleDefinition})
+			assert.Equal(t, tc.expectedModifications, modifications)
+			assertMatchesGolden(t, modifier, tc.fixture+".golden")
+		})
+	}
+}
@@ -6,6 +6,7 @@ import (
 
 	"github.com/hashicorp/hcl/v2"
 	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/stretchr/testify/assert"
 	"go.uber.org/zap"
 
 	"github.com/kotatut/cluster_import_cleaner/hclmodifier/rules"
@@ -241,3 +242,126 @@ func TestApplyBinaryAuthorizationRule(t *testing.T) {
 		})
 	}
 }
+
+func TestApplyMasterAuthClientCertificateRules(t *testing.T) {
+	tests := []struct {
+		name                  string
+		fixture               string
+		expectedModifications int
+	}{
+		{
+			name:                  "Only the default client certificate config",
+			fixture:               "testdata/TestApplyMasterAuthRule_DefaultOnly.tf",
+			expectedModifications: 3,
+		},
+		{
+			name:                  "master_auth with other data",
+			fixture:               "testdata/TestApplyMasterAuthRule_HasData.tf",
+			expectedModifications: 2, // issue_client_certificate and client_certificate_config in primary
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			modifier, modifications := applyRulesToFixture(t, tc.fixture, rules.MasterAuthClientCertificateRules)
+			assert.Equal(t, tc.expectedModifications, modifications)
+			assertMatchesGolden(t, modifier, tc.fixture+".golden")
+		})
+	}
+}
+
+func TestApplyEmptyMasterAuthorizedNetworksConfigRule(t *testing.T) {
+	tests := []struct {
+		name                  string
+		fixture               string
+		expectedModifications int
+	}{
+		{
+			name:                  "Empty block",
+			fixture:               "testdata/TestApplyEmptyMasterAuthorizedNetworksConfigRule_Empty.tf",
+			expectedModifications: 1,
+		},
+		{
+			name:                  "Block with cidr_blocks",
+			fixture:               "testdata/TestApplyEmptyMasterAuthorizedNetworksConfigRule_HasCidr.tf",
+			expectedModifications: 0,
+		},
+		{
+			name:                  "Block with gcp_public_cidrs_access_enabled",
+			fixture:               "testdata/TestApplyEmptyMasterAuthorizedNetworksConfigRule_HasFlag.tf",
+			expectedModifications: 0,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			modifier, modifications := applyRulesToFixture(t, tc.fixture, []types.Rule{rules.EmptyMasterAuthorizedNetworksConfigRuleDefinition})
+			assert.Equal(t, tc.expectedModifications, modifications)
+			assertMatchesGolden(t, modifier, tc.fixture+".golden")
+		})
+	}
+}
+
+func TestApplyIdentityServiceConfigRule(t *testing.T) {
+	tests := []struct {
+		name                  string
+		fixture               string
+		expectedModifications int
+	}{
+		{
+			name:                  "Disabled is removed",
+			fixture:               "testdata/TestApplyIdentityServiceConfigRule_Disabled.tf",
+			expectedModifications: 1,
+		},
+		{
+			name:                  "Enabled is kept",
+			fixture:               "testdata/TestApplyIdentityServiceConfigRule_Enabled.tf",
+			expectedModifications: 0,
+		},
+		{
+			name:                  "Block absent",
+			fixture:               "testdata/TestApplyIdentityServiceConfigRule_BlockAbsent.tf",
+			expectedModifications: 0,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			modifier, modifications := applyRulesToFixture(t, tc.fixture, []types.Rule{rules.IdentityServiceConfigRuleDefinition})
+			assert.Equal(t, tc.expectedModifications, modifications)
+			assertMatchesGolden(t, modifier, tc.fixture+".golden")
+		})
+	}
+}
+
+func TestApplyLegacyAbacRule(t *testing.T) {
+	tests := []struct {
+		name                  string
+		fixture               string
+		expectedModifications int
+	}{
+		{
+			name:                  "enable_legacy_abac is false",
+			fixture:               "testdata/TestApplyLegacyAbacRule_False.tf",
+			expectedModifications: 1,
+		},
+		{
+			name:                  "enable_legacy_abac is true",
+			fixture:               "testdata/TestApplyLegacyAbacRule_True.tf",
+			expectedModifications: 0,
+		},
+		{
+			name:                  "enable_legacy_abac is absent",
+			fixture:               "testdata/TestApplyLegacyAbacRule_Absent.tf",
+			expectedModifications: 0,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			modifier, modifications := applyRulesToFixture(t, tc.fixture, []types.Rule{rules.RuleHandleLegacyAbacFalse})
+			assert.Equal(t, tc.expectedModifications, modifications)
+			assertMatchesGolden(t, modifier, tc.fixture+".golden")
+		})
+	}
+}
@@ -196,3 +196,35 @@ func TestAutopilotRules_NoGKEResource(t *testing.T) {
 	assert.Equal(t, expectedModifications, modifications, "Expected 0 modifications for HCL with no GKE resource")
 	assert.Equal(t, string(originalContent), string(modifier.File().Bytes()), "HCL content should remain unchanged")
 }
+
+func TestAutopilotEnabled_EmptyIPAllocationPolicy(t *testing.T) {
+	tests := []struct {
+		name                  string
+		fixture               string
+		expectedModifications int
+	}{
+		{
+			name:                  "Autopilot cluster with empty ip_allocation_policy",
+			fixture:               "testdata/TestApplyAutopilotRule_EmptyIPAllocationPolicy.tf",
+			expectedModifications: 1,
+		},
+		{
+			name:                  "Autopilot cluster with configured ip_allocation_policy",
+			fixture:               "testdata/TestApplyAutopilotRule_NonEmptyIPAllocationPolicy.tf",
+			expectedModifications: 0,
+		},
+		{
+			name:                  "Standard cluster with empty ip_allocation_policy",
+			fixture:               "testdata/TestApplyAutopilotRule_StandardEmptyIPAllocationPolicy.tf",
+			expectedModifications: 0,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			modifier, modifications := applyRulesToFixture(t, tc.fixture, rules.AutopilotRules)
+			assert.Equal(t, tc.expectedModifications, modifications)
+			assertMatchesGolden(t, modifier, tc.fixture+".golden")
+		})
+	}
+}
@@ -0,0 +1,166 @@
+package hclmodifier
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/kotatut/cluster_import_cleaner/hclmodifier/types"
+)
+
+// DiffStat compares before and after line by line and returns the number of lines added and removed,
+// as a unified diff of the two would report them. Lines are matched using their longest common subsequence,
+// so unchanged lines that merely moved relative to removed ones are not counted.
+func DiffStat(before, after []byte) (added int, removed int) {
+	beforeLines := splitLines(before)
+	afterLines := splitLines(after)
+	common := longestCommonSubsequenceLength(beforeLines, afterLines)
+	return len(afterLines) - common, len(beforeLines) - common
+}
+
+// splitLines splits content into lines, ignoring the trailing newline of the last line.
+func splitLines(content []byte) []string {
+	if len(content) == 0 {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(string(content), "\n"), "\n")
+}
+
+// longestCommonSubsequenceLength returns the length of the longest common subsequence of a and b.
+func longestCommonSubsequenceLength(a, b []string) int {
+	previous := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+	for i := 1; i <= len(a); i++ {
+		for j := 1; j <= len(b); j++ {
+			if a[i-1] == b[j-1] {
+				current[j] = previous[j-1] + 1
+			} else {
+				current[j] = max(previous[j], current[j-1])
+			}
+		}
+		previous, current = current, previous
+	}
+	return previous[len(b)]
+}
+
+// Diff compares the attributes of m's file with those of other's, block by block, and returns a ChangeEntry for
+// every attribute removed (RemoveAttribute), added or changed (SetAttributeValue) in other, with its Before and
+// After values. Unlike DiffStat, formatting and attribute order don't count as changes.
+// Top-level blocks are matched by type and labels. Nested blocks are matched by type, labels and position among the
+// blocks with the same type and labels, and their attributes get paths prefixed with the block types, as rules
+// report them. A block that only exists on one side has all of its attributes reported as removed or added.
+// The entries have no RuleName; ResourceLabels are the labels of the top-level block.
+func (m *Modifier) Diff(other *Modifier) []types.ChangeEntry {
+	var changes []types.ChangeEntry
+	var before, after []*hclwrite.Block
+	if m != nil && m.file != nil {
+		before = m.file.Body().Blocks()
+	}
+	if other != nil && other.file != nil {
+		after = other.file.Body().Blocks()
+	}
+	for _, pair := range matchBlocks(before, after) {
+		changes = append(changes, diffBodies(pair.before, pair.after, pair.either().Labels(), nil)...)
+	}
+	return changes
+}
+
+// blockPair is a block of the first file of a Diff and its counterpart in the second; either may be nil.
+type blockPair struct {
+	before, after *hclwrite.Block
+}
+
+// either returns whichever block of the pair exists, preferring before.
+func (p blockPair) either() *hclwrite.Block {
+	if p.before != nil {
+		return p.before
+	}
+	return p.after
+}
+
+// matchBlocks pairs up the blocks of before and after that have the same type and labels, the first with the
+// first and so on, in the order of before followed by the blocks that only exist in after.
+func matchBlocks(before, after []*hclwrite.Block) []blockPair {
+	key := func(block *hclwrite.Block) string {
+		return strings.Join(append([]string{block.Type()}, block.Labels()...), "\x00")
+	}
+	unmatched := make(map[string][]*hclwrite.Block)
+	for _, block := range after {
+		unmatched[key(block)] = append(unmatched[key(block)], block)
+	}
+
+	var pairs []blockPair
+	matched := make(map[*hclwrite.Block]bool)
+	for _, block := range before {
+		pair := blockPair{before: block}
+		if candidates := unmatched[key(block)]; len(candidates) > 0 {
+			pair.after = candidates[0]
+			matched[candidates[0]] = true
+			unmatched[key(block)] = candidates[1:]
+		}
+		pairs = append(pairs, pair)
+	}
+	for _, block := range after {
+		if !matched[block] {
+			pairs = append(pairs, blockPair{after: block})
+		}
+	}
+	return pairs
+}
+
+// diffBodies returns the attribute changes between the bodies of before and after, either of which may be nil,
+// and recursively between their nested blocks. path holds the types of the enclosing nested blocks.
+func diffBodies(before, after *hclwrite.Block, resourceLabels, path []string) []types.ChangeEntry {
+	beforeAttributes := map[string]*hclwrite.Attribute{}
+	afterAttributes := map[string]*hclwrite.Attribute{}
+	var beforeBlocks, afterBlocks []*hclwrite.Block
+	if before != nil {
+		beforeAttributes = before.Body().Attributes()
+		beforeBlocks = before.Body().Blocks()
+	}
+	if after != nil {
+		afterAttributes = after.Body().Attributes()
+		afterBlocks = after.Body().Blocks()
+	}
+
+	var names []string
+	for name := range beforeAttributes {
+		names = append(names, name)
+	}
+	for name := range afterAttributes {
+		if _, ok := beforeAttributes[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	slices.Sort(names)
+
+	var changes []types.ChangeEntry
+	for _, name := range names {
+		change := types.ChangeEntry{ResourceLabels: resourceLabels, Path: append(slices.Clone(path), name)}
+		if attr, ok := beforeAttributes[name]; ok {
+			change.Before = attributeValueText(attr)
+		}
+		if attr, ok := afterAttributes[name]; ok {
+			change.After = attributeValueText(attr)
+		}
+		switch {
+		case change.Before == change.After:
+			continue
+		case afterAttributes[name] == nil:
+			change.ActionType = types.RemoveAttribute
+		default:
+			change.ActionType = types.SetAttributeValue
+		}
+		changes = append(changes, change)
+	}
+
+	for _, pair := range matchBlocks(beforeBlocks, afterBlocks) {
+		changes = append(changes, diffBodies(pair.before, pair.after, resourceLabels, append(slices.Clone(path), pair.either().Type()))...)
+	}
+	return changes
+}
+
+// attributeValueText returns the formatted source text of attr's value expression.
+func attributeValueText(attr *hclwrite.Attribute) string {
+	return strings.TrimSpace(string(hclwrite.Format(attr.Expr().BuildTokens(nil).Bytes())))
+}
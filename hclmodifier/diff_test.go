@@ -0,0 +1,117 @@
+package hclmodifier
+
+import (
+	"testing"
+
+	"github.com/kotatut/cluster_import_cleaner/hclmodifier/rules"
+	"github.com/kotatut/cluster_import_cleaner/hclmodifier/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/zclconf/go-cty/cty"
+)
+
+func TestDiffStat(t *testing.T) {
+	tests := []struct {
+		name            string
+		before          string
+		after           string
+		expectedAdded   int
+		expectedRemoved int
+	}{
+		{
+			name:   "Identical content",
+			before: "a\nb\nc\n",
+			after:  "a\nb\nc\n",
+		},
+		{
+			name:            "Removed lines",
+			before:          "a\nb\nc\nd\n",
+			after:           "a\nd\n",
+			expectedRemoved: 2,
+		},
+		{
+			name:            "Changed line counts as one removed and one added",
+			before:          "a\nb\nc\n",
+			after:           "a\nB\nc\n",
+			expectedAdded:   1,
+			expectedRemoved: 1,
+		},
+		{
+			name:          "From empty",
+			before:        "",
+			after:         "a\nb\n",
+			expectedAdded: 2,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			added, removed := DiffStat([]byte(tc.before), []byte(tc.after))
+			assert.Equal(t, tc.expectedAdded, added)
+			assert.Equal(t, tc.expectedRemoved, removed)
+		})
+	}
+}
+
+func TestModifierDiff(t *testing.T) {
+	before := newTestModifier(t, `resource "google_container_cluster" "primary" {
+  name     = "primary"
+  location = "us-central1"
+  id       = "projects/p/locations/us-central1/clusters/primary"
+
+  node_pool {
+    name               = "default-pool"
+    initial_node_count = 1
+  }
+
+  node_pool {
+    name = "other-pool"
+    node_config {
+      disk_size_gb = 100
+    }
+  }
+}
+
+resource "google_container_node_pool" "pool" {
+  name = "pool"
+}`)
+	after := before.Clone()
+	rulesToApply := []types.Rule{rules.TopLevelComputedAttributesRules[2], rules.InitialNodeCountRuleDefinition, rules.NodeConfigDiskDefaultsRules[0]}
+	_, errs := after.ApplyRules(rulesToApply)
+	assert.Empty(t, errs)
+	_, err := after.SetAttributeValueByPath(after.File().Body().Blocks()[0].Body(), []string{"location"}, cty.StringVal("us-east1"))
+	assert.NoError(t, err)
+
+	clusterLabels := []string{"google_container_cluster", "primary"}
+	expected := []types.ChangeEntry{
+		{ResourceLabels: clusterLabels, ActionType: types.RemoveAttribute, Path: []string{"id"}, Before: `"projects/p/locations/us-central1/clusters/primary"`},
+		{ResourceLabels: clusterLabels, ActionType: types.SetAttributeValue, Path: []string{"location"}, Before: `"us-central1"`, After: `"us-east1"`},
+		{ResourceLabels: clusterLabels, ActionType: types.RemoveAttribute, Path: []string{"node_pool", "initial_node_count"}, Before: "1"},
+		{ResourceLabels: clusterLabels, ActionType: types.RemoveAttribute, Path: []string{"node_pool", "node_config", "disk_size_gb"}, Before: "100"},
+	}
+	assert.Equal(t, expected, before.Diff(after))
+
+	// The other way around, removed attributes become added ones.
+	added := after.Diff(before)
+	if assert.Len(t, added, 4) {
+		assert.Equal(t, types.SetAttributeValue, added[0].ActionType)
+		assert.Equal(t, "", added[0].Before)
+		assert.Equal(t, `"projects/p/locations/us-central1/clusters/primary"`, added[0].After)
+	}
+
+	assert.Empty(t, before.Diff(before.Clone()), "Identical files have no differences")
+}
+
+func TestModifierDiffUnmatchedBlocks(t *testing.T) {
+	before := newTestModifier(t, `resource "google_container_cluster" "old" {
+  name = "old"
+}`)
+	after := newTestModifier(t, `resource "google_container_cluster" "new" {
+  name = "new"
+}`)
+
+	expected := []types.ChangeEntry{
+		{ResourceLabels: []string{"google_container_cluster", "old"}, ActionType: types.RemoveAttribute, Path: []string{"name"}, Before: `"old"`},
+		{ResourceLabels: []string{"google_container_cluster", "new"}, ActionType: types.SetAttributeValue, Path: []string{"name"}, After: `"new"`},
+	}
+	assert.Equal(t, expected, before.Diff(after))
+}
@@ -0,0 +1,86 @@
+package hclmodifier
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2/hclwrite"
+
+	"github.com/kotatut/cluster_import_cleaner/hclmodifier/types"
+)
+
+// lintRuleName is the rule name recorded in warnings returned by Lint.
+const lintRuleName = "Pre-flight Lint"
+
+// Lint scans every resource block in the file for HCL features the rules can't safely handle and
+// returns one warning per finding. It never modifies the file.
+//
+// The following are reported:
+//   - `count` and `for_each` meta-arguments, since rules treat the block as a single resource.
+//   - `dynamic` blocks, whose content is generated and invisible to block-based conditions and actions.
+//   - Attributes whose value is not a literal (references, function calls, interpolations), since
+//     value-based conditions can't evaluate them and won't be met.
+func (m *Modifier) Lint() []types.Warning {
+	var warnings []types.Warning
+	if m.file == nil || m.file.Body() == nil {
+		return warnings
+	}
+
+	for _, resourceBlock := range m.file.Body().Blocks() {
+		if resourceBlock.Type() != "resource" {
+			continue
+		}
+		newWarning := func(message string) types.Warning {
+			return types.Warning{RuleName: lintRuleName, ResourceLabels: resourceBlock.Labels(), Message: message}
+		}
+
+		for _, metaArgument := range []string{"count", "for_each"} {
+			if resourceBlock.Body().GetAttribute(metaArgument) != nil {
+				warnings = append(warnings, newWarning(fmt.Sprintf("resource uses the '%s' meta-argument; rules treat it as a single resource and may not apply as expected", metaArgument)))
+			}
+		}
+
+		var dynamicBlocks, nonLiteralAttributes []string
+		m.lintBody(resourceBlock.Body(), nil, &dynamicBlocks, &nonLiteralAttributes)
+		if len(dynamicBlocks) > 0 {
+			warnings = append(warnings, newWarning(fmt.Sprintf("resource contains dynamic blocks (%s); rules can't see the blocks they generate", strings.Join(dynamicBlocks, ", "))))
+		}
+		if len(nonLiteralAttributes) > 0 {
+			warnings = append(warnings, newWarning(fmt.Sprintf("attributes %s are not literal values; rules comparing their values won't apply", strings.Join(nonLiteralAttributes, ", "))))
+		}
+	}
+	return warnings
+}
+
+// lintBody collects the paths of dynamic blocks and of attributes with non-literal values within body, recursively.
+// Meta-arguments are skipped, since Lint reports them separately.
+func (m *Modifier) lintBody(body *hclwrite.Body, path []string, dynamicBlocks, nonLiteralAttributes *[]string) {
+	attributes := body.Attributes()
+	names := make([]string, 0, len(attributes))
+	for name := range attributes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if len(path) == 0 && (name == "count" || name == "for_each") {
+			continue
+		}
+		if _, err := m.GetAttributeValue(attributes[name]); err != nil {
+			*nonLiteralAttributes = append(*nonLiteralAttributes, strings.Join(append(append([]string{}, path...), name), "."))
+		}
+	}
+
+	for _, block := range body.Blocks() {
+		blockPath := append(append([]string{}, path...), block.Type())
+		if block.Type() == "dynamic" {
+			dynamicPath := blockPath
+			if len(block.Labels()) > 0 {
+				dynamicPath = append(dynamicPath, block.Labels()[0])
+			}
+			*dynamicBlocks = append(*dynamicBlocks, strings.Join(dynamicPath, "."))
+			continue
+		}
+		m.lintBody(block.Body(), blockPath, dynamicBlocks, nonLiteralAttributes)
+	}
+}
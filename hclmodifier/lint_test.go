@@ -0,0 +1,45 @@
+package hclmodifier
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kotatut/cluster_import_cleaner/hclmodifier/types"
+)
+
+func TestLint(t *testing.T) {
+	t.Run("Templated resource", func(t *testing.T) {
+		modifier := newTestModifier(t, `resource "google_container_cluster" "primary" {
+  count    = 2
+  name     = "cluster-${count.index}"
+  location = "us-central1"
+  dynamic "node_pool" {
+    for_each = var.node_pools
+    content {
+      name = node_pool.value.name
+    }
+  }
+  node_config {
+    tags = var.tags
+  }
+}`)
+		labels := []string{"google_container_cluster", "primary"}
+		assert.Equal(t, []types.Warning{
+			{RuleName: lintRuleName, ResourceLabels: labels, Message: "resource uses the 'count' meta-argument; rules treat it as a single resource and may not apply as expected"},
+			{RuleName: lintRuleName, ResourceLabels: labels, Message: "resource contains dynamic blocks (dynamic.node_pool); rules can't see the blocks they generate"},
+			{RuleName: lintRuleName, ResourceLabels: labels, Message: "attributes name, node_config.tags are not literal values; rules comparing their values won't apply"},
+		}, modifier.Lint())
+	})
+
+	t.Run("Plain resource", func(t *testing.T) {
+		modifier := newTestModifier(t, `resource "google_container_cluster" "primary" {
+  name     = "primary"
+  location = "us-central1"
+  node_pool {
+    name = "pool-1"
+  }
+}`)
+		assert.Empty(t, modifier.Lint())
+	})
+}
@@ -1,8 +1,11 @@
 package hclmodifier
 
 import (
+	"bytes"
+	"errors"
 	"fmt"
 	"os"
+	"regexp"
 	"slices"
 	"strconv"
 	"strings"
@@ -11,6 +14,7 @@ import (
 	"github.com/hashicorp/hcl/v2/hclsyntax"
 	"github.com/hashicorp/hcl/v2/hclwrite"
 	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/convert"
 	"go.uber.org/zap"
 
 	"github.com/kotatut/cluster_import_cleaner/hclmodifier/types"
@@ -21,6 +25,14 @@ import (
 type Modifier struct {
 	file   *hclwrite.File
 	Logger *zap.Logger
+	// ResolveReferences makes GetAttributeValue (and so every condition) resolve references to literal
+	// attributes of other resources, data sources and locals in the same file, e.g.
+	// `network = google_compute_network.main.self_link`. See evalContext. Actions never resolve references,
+	// so they don't write resolved values back in place of the references.
+	ResolveReferences bool
+	// referenceCtx caches evalContext for the duration of an applyRules pass, so that conditions don't rebuild it
+	// for every attribute they read; it reflects the file as it was when the pass started. It is nil outside a pass.
+	referenceCtx *hcl.EvalContext
 }
 
 // Returns a pointer to the created Modifier and an error if file reading or parsing fails.
@@ -37,34 +49,107 @@ func NewFromFile(filePath string, logger *zap.Logger) (*Modifier, error) {
 		return nil, err
 	}
 
-	logger.Debug("Parsing HCL file", zap.String("filePath", filePath))
-	hclFile, diags := hclwrite.ParseConfig(contentBytes, filePath, hcl.Pos{Line: 1, Column: 1})
+	return NewFromBytes(contentBytes, filePath, logger)
+}
+
+// NewFromBytes parses content as HCL, e.g. read from stdin, and returns a Modifier for it.
+// filename is only used in diagnostics. Returns an error if parsing fails.
+func NewFromBytes(content []byte, filename string, logger *zap.Logger) (*Modifier, error) {
+	if logger == nil {
+		logger, _ = zap.NewDevelopment()
+		logger.Warn("NewFromBytes called with nil logger, using default development logger.")
+	}
+
+	logger.Debug("Parsing HCL file", zap.String("filePath", filename))
+	hclFile, diags := hclwrite.ParseConfig(content, filename, hcl.Pos{Line: 1, Column: 1})
 	if diags.HasErrors() {
-		logger.Error("Error parsing HCL file", zap.String("filePath", filePath), zap.Error(diags))
+		logger.Error("Error parsing HCL file", zap.String("filePath", filename), zap.Error(diags))
 		return nil, fmt.Errorf("HCL parsing failed: %w", diags)
 	}
 
 	return &Modifier{file: hclFile, Logger: logger}, nil
 }
 
+// Clean parses content as HCL, applies rules to it and returns the cleaned HCL along with the number of
+// modifications made, all in memory. If content can't be parsed, it returns nil and the parsing error; otherwise
+// the errors are those returned by ApplyRules, and the cleaned HCL is returned even if some rules failed.
+func Clean(content []byte, rules []types.Rule, logger *zap.Logger) ([]byte, int, []error) {
+	m, err := NewFromBytes(content, "", logger)
+	if err != nil {
+		return nil, 0, []error{err}
+	}
+	modifications, errs := m.ApplyRules(rules)
+	return m.File().Bytes(), modifications, errs
+}
+
 // File provides access to the underlying *hclwrite.File object that the Modifier is working with.
 func (m *Modifier) File() *hclwrite.File {
 	return m.file
 }
 
-// WriteToFile serializes the current state of the Modifier's hclwrite.File object.
-func (m *Modifier) WriteToFile(filePath string) error {
+// defaultFileMode is the permission mode of files created by WriteToFile.
+const defaultFileMode os.FileMode = 0644
+
+// writeOptions holds the settings WriteToFile accepts as WriteOptions.
+type writeOptions struct {
+	mode    os.FileMode
+	modeSet bool
+}
+
+// WriteOption customizes how WriteToFile writes the file.
+type WriteOption func(*writeOptions)
+
+// WithFileMode makes WriteToFile set the file's permission bits to mode, whether the file exists or not.
+func WithFileMode(mode os.FileMode) WriteOption {
+	return func(o *writeOptions) {
+		o.mode = mode.Perm()
+		o.modeSet = true
+	}
+}
+
+// WriteToFile serializes the current state of the Modifier's hclwrite.File object to filePath.
+// An existing file keeps its permission bits, and a new file is created with mode 0644, unless
+// WithFileMode says otherwise.
+func (m *Modifier) WriteToFile(filePath string, opts ...WriteOption) error {
+	options := writeOptions{mode: defaultFileMode}
+	if info, err := os.Stat(filePath); err == nil {
+		options.mode = info.Mode().Perm()
+	}
+	for _, opt := range opts {
+		opt(&options)
+	}
+
 	modifiedBytes := m.file.Bytes()
-	m.Logger.Debug("Writing modified HCL to file", zap.String("filePath", filePath))
-	err := os.WriteFile(filePath, modifiedBytes, 0644)
+	m.Logger.Debug("Writing modified HCL to file", zap.String("filePath", filePath), zap.Stringer("fileMode", options.mode))
+	err := os.WriteFile(filePath, modifiedBytes, options.mode)
 	if err != nil {
 		m.Logger.Error("Error writing modified HCL to file", zap.String("filePath", filePath), zap.Error(err))
 		return fmt.Errorf("failed to write HCL content to %s: %w", filePath, err)
 	}
+	// os.WriteFile only applies the mode to new files, and then only after the umask.
+	if options.modeSet {
+		if err := os.Chmod(filePath, options.mode); err != nil {
+			m.Logger.Error("Error setting file mode", zap.String("filePath", filePath), zap.Error(err))
+			return fmt.Errorf("failed to set mode of %s: %w", filePath, err)
+		}
+	}
 	m.Logger.Info("Successfully wrote modified HCL to file", zap.String("filePath", filePath))
 	return nil
 }
 
+// Clone returns a new Modifier holding a deep copy of the current file, obtained by re-parsing m.file.Bytes().
+// The copy shares the logger. It is a snapshot of the file's current state, not a live view: changes made
+// through either Modifier afterwards are not visible in the other.
+// Returns nil if the current content can't be parsed again, which shouldn't happen for a file built by hclwrite.
+func (m *Modifier) Clone() *Modifier {
+	hclFile, diags := hclwrite.ParseConfig(m.file.Bytes(), "", hcl.Pos{Line: 1, Column: 1})
+	if diags.HasErrors() {
+		m.Logger.Error("Clone: Error re-parsing HCL content", zap.Error(diags))
+		return nil
+	}
+	return &Modifier{file: hclFile, Logger: m.Logger, ResolveReferences: m.ResolveReferences}
+}
+
 // GetBlock searches for and returns a specific HCL block within the Modifier's file.
 func (m *Modifier) GetBlock(blockType string, blockLabels []string) (*hclwrite.Block, error) {
 	m.Logger.Debug("Searching for block", zap.String("blockType", blockType), zap.Strings("blockLabels", blockLabels))
@@ -78,6 +163,21 @@ func (m *Modifier) GetBlock(blockType string, blockLabels []string) (*hclwrite.B
 	return nil, fmt.Errorf("block %s %v not found", blockType, blockLabels)
 }
 
+// ListResources returns the names (second labels) of the resource blocks of resourceType in the file,
+// in the order they appear, e.g. `["primary", "secondary"]` for two `google_container_cluster` resources.
+func (m *Modifier) ListResources(resourceType string) []string {
+	var names []string
+	if m.file == nil || m.file.Body() == nil {
+		return names
+	}
+	for _, block := range m.file.Body().Blocks() {
+		if block.Type() == "resource" && len(block.Labels()) == 2 && block.Labels()[0] == resourceType {
+			names = append(names, block.Labels()[1])
+		}
+	}
+	return names
+}
+
 // GetAttribute retrieves a specific attribute by its name from the provided HCL block.
 func (m *Modifier) GetAttribute(block *hclwrite.Block, attributeName string) (*hclwrite.Attribute, error) {
 	attribute := block.Body().GetAttribute(attributeName)
@@ -92,7 +192,21 @@ func (m *Modifier) GetAttribute(block *hclwrite.Block, attributeName string) (*h
 }
 
 // GetAttributeValue evaluates the expression of an HCL attribute and returns its corresponding cty.Value.
+// Only literal values can be evaluated, unless m.ResolveReferences is set, in which case references to
+// literal attributes elsewhere in the file resolve too. Any other expression returns an error.
 func (m *Modifier) GetAttributeValue(attr *hclwrite.Attribute) (cty.Value, error) {
+	var evalCtx *hcl.EvalContext
+	if m != nil && m.ResolveReferences {
+		evalCtx = m.referenceCtx
+		if evalCtx == nil {
+			evalCtx = m.evalContext()
+		}
+	}
+	return m.evaluateAttribute(attr, evalCtx)
+}
+
+// evaluateAttribute evaluates the expression of attr with evalCtx, which may be nil to only resolve literals.
+func (m *Modifier) evaluateAttribute(attr *hclwrite.Attribute, evalCtx *hcl.EvalContext) (cty.Value, error) {
 	exprBytes := attr.Expr().BuildTokens(nil).Bytes()
 
 	expr, diags := hclsyntax.ParseExpression(exprBytes, "attribute_expr", hcl.Pos{Line: 1, Column: 1})
@@ -101,8 +215,8 @@ func (m *Modifier) GetAttributeValue(attr *hclwrite.Attribute) (cty.Value, error
 		return cty.NilVal, fmt.Errorf("failed to parse expression: %w", diags)
 	}
 
-	// We pass a nil EvalContext because we only want to resolve simple literals.
-	val, diags := expr.Value(nil)
+	// A nil EvalContext only resolves simple literals.
+	val, diags := expr.Value(evalCtx)
 	if diags.HasErrors() {
 		m.Logger.Debug("Attribute expression is not a simple literal", zap.String("expression", string(exprBytes)), zap.Error(diags))
 		return cty.NilVal, fmt.Errorf("attribute is not a simple literal: %w", diags)
@@ -156,13 +270,49 @@ func (m *Modifier) RemoveBlock(blockType string, blockLabels []string) error {
 	return nil
 }
 
+// RemoveEmptyResources removes every resource block of resourceType that has no meaningful content.
+// A resource is considered empty when it has no nested blocks and all of its attributes are listed
+// in ignoredAttributes (e.g. `name` and `location`), which typically happens once computed
+// attributes have been stripped from an accidental import.
+// Returns the number of resource blocks removed.
+func (m *Modifier) RemoveEmptyResources(resourceType string, ignoredAttributes []string) int {
+	var emptyBlocks []*hclwrite.Block
+	for _, block := range m.file.Body().Blocks() {
+		if block.Type() != "resource" || len(block.Labels()) == 0 || block.Labels()[0] != resourceType {
+			continue
+		}
+		if len(block.Body().Blocks()) > 0 {
+			continue
+		}
+		hasMeaningfulAttribute := false
+		for name := range block.Body().Attributes() {
+			if !slices.Contains(ignoredAttributes, name) {
+				hasMeaningfulAttribute = true
+				break
+			}
+		}
+		if !hasMeaningfulAttribute {
+			emptyBlocks = append(emptyBlocks, block)
+		}
+	}
+
+	for _, block := range emptyBlocks {
+		m.file.Body().RemoveBlock(block)
+		m.Logger.Info("Removed resource without meaningful content", zap.Strings("blockLabels", block.Labels()))
+	}
+	return len(emptyBlocks)
+}
+
 // GetNestedBlock navigates through a sequence of HCL block names (path) starting from currentBlockBody
 // to find and return a specific nested block.
 // currentBlockBody: The *hclwrite.Body of the block from which to start the search.
 // path: A slice of strings where each string is a block type/name in the nesting hierarchy.
 // For example, to find block "c" in `a { b { c {} } }`, path would be `["a", "b", "c"]` if starting from root,
 // or `["b", "c"]` if `currentBlockBody` is the body of block `a`.
-// Returns the found *hclwrite.Block and nil error, or nil and an error if any block in the path is not found.
+// If several blocks of the same type exist at a level, the first one is used, as RemoveNestedBlockByPath does, so
+// conditions and actions addressing the same path always see the same block.
+// Returns the found *hclwrite.Block and nil error, or nil and an error wrapping types.ErrBlockNotFound if any block
+// in the path is not found.
 func (m *Modifier) GetNestedBlock(currentBlockBody *hclwrite.Body, path []string) (*hclwrite.Block, error) {
 	if currentBlockBody == nil {
 		return nil, fmt.Errorf("GetNestedBlock: currentBlockBody cannot be nil")
@@ -183,25 +333,95 @@ func (m *Modifier) GetNestedBlock(currentBlockBody *hclwrite.Body, path []string
 			if block.Type() == blockName {
 				currentLevelBody = block.Body()
 				foundBlock = block
+				break
 			}
 		}
 		if foundBlock == nil {
 			logger.Debug("GetNestedBlock: Block not found at current level.", zap.String("blockName", blockName), zap.Int("level", i))
-			return nil, fmt.Errorf("block '%s' not found at path level %d", blockName, i)
+			return nil, fmt.Errorf("block '%s' not found at path level %d: %w", blockName, i, types.ErrBlockNotFound)
 		}
 	}
 
 	if foundBlock == nil {
 		logger.Debug("GetNestedBlock: Target block not found at the end of path.")
-		return nil, fmt.Errorf("target block not found at path '%s'", path)
+		return nil, fmt.Errorf("target block not found at path '%s': %w", path, types.ErrBlockNotFound)
 	}
 
 	logger.Debug("GetNestedBlock: Successfully found nested block.")
 	return foundBlock, nil
 }
 
+// GetAllBlocksOfType returns the blocks of blockType directly within body, in the order they appear.
+// Blocks nested deeper are not included.
+func (m *Modifier) GetAllBlocksOfType(body *hclwrite.Body, blockType string) []*hclwrite.Block {
+	var blocks []*hclwrite.Block
+	if body == nil {
+		return blocks
+	}
+	for _, block := range body.Blocks() {
+		if block.Type() == blockType {
+			blocks = append(blocks, block)
+		}
+	}
+	return blocks
+}
+
+// DeduplicateBlocks keeps the first block of blockType directly within body and removes any later ones.
+// It returns the number of blocks removed.
+func (m *Modifier) DeduplicateBlocks(body *hclwrite.Body, blockType string) int {
+	blocks := m.GetAllBlocksOfType(body, blockType)
+	if len(blocks) < 2 {
+		return 0
+	}
+	for _, duplicate := range blocks[1:] {
+		body.RemoveBlock(duplicate)
+	}
+	m.Logger.Debug("DeduplicateBlocks: Removed duplicate blocks.", zap.String("blockType", blockType), zap.Int("blocksRemoved", len(blocks)-1))
+	return len(blocks) - 1
+}
+
+// GetAttributeByPath returns the attribute at path, resolved like GetAttributeValueByPath (including "*" segments),
+// without evaluating its value. Attributes whose values aren't literals (e.g. `name = var.cluster_name`) are found
+// too. Returns nil if the path is invalid or the attribute or any parent block doesn't exist.
+func (m *Modifier) GetAttributeByPath(initialBlockBody *hclwrite.Body, path []string) *hclwrite.Attribute {
+	if initialBlockBody == nil || len(path) == 0 {
+		return nil
+	}
+
+	if blocks, rest, ok, err := m.expandWildcardPath(initialBlockBody, path); ok {
+		if err != nil {
+			return nil
+		}
+		for _, block := range blocks {
+			if attr := m.GetAttributeByPath(block.Body(), rest); attr != nil {
+				return attr
+			}
+		}
+		return nil
+	}
+
+	targetBody, attributeName, err := m.findAttributeParentBody(initialBlockBody, path)
+	if err != nil || targetBody == nil {
+		return nil
+	}
+	return targetBody.GetAttribute(attributeName)
+}
+
+// literalValueByPath returns the value of the attribute at path, found like GetAttributeByPath, without resolving
+// references even if m.ResolveReferences is set. Actions use it for values they write back or compare against.
+// Returns an error if the attribute doesn't exist or isn't a literal.
+func (m *Modifier) literalValueByPath(initialBlockBody *hclwrite.Body, path []string) (cty.Value, error) {
+	attr := m.GetAttributeByPath(initialBlockBody, path)
+	if attr == nil {
+		return cty.NilVal, fmt.Errorf("attribute not found at path '%s'", path)
+	}
+	return m.evaluateAttribute(attr, nil)
+}
+
 // GetAttributeValueByPath retrieves the cty.Value and the *hclwrite.Attribute for an attribute.
 // path: A slice of strings representing the path. The last element is the attribute name.
+// A "*" segment stands for every block of the preceding type (e.g. `["node_pool", "*", "name"]`);
+// the value of the first of those blocks that has the attribute is returned.
 // Returns the cty.Value of the attribute, the *hclwrite.Attribute itself, and an error if the
 // path is invalid, any intermediate block is not found, the attribute is not found, or its value cannot be determined.
 func (m *Modifier) GetAttributeValueByPath(initialBlockBody *hclwrite.Body, path []string) (cty.Value, *hclwrite.Attribute, error) {
@@ -215,6 +435,19 @@ func (m *Modifier) GetAttributeValueByPath(initialBlockBody *hclwrite.Body, path
 	logger := m.Logger.With(zap.Strings("path", path))
 	logger.Debug("GetAttributeValueByPath: Attempting to get attribute value.")
 
+	if blocks, rest, ok, err := m.expandWildcardPath(initialBlockBody, path); ok {
+		if err != nil {
+			return cty.NilVal, nil, err
+		}
+		for _, block := range blocks {
+			if val, attr, err := m.GetAttributeValueByPath(block.Body(), rest); err == nil {
+				return val, attr, nil
+			}
+		}
+		logger.Debug("GetAttributeValueByPath: Attribute not found in any block matching the wildcard.")
+		return cty.NilVal, nil, fmt.Errorf("attribute '%s' not found in any block matching path '%s'", path[len(path)-1], path)
+	}
+
 	attributeName := path[len(path)-1]
 	blockPath := path[:len(path)-1]
 
@@ -252,7 +485,10 @@ func (m *Modifier) GetAttributeValueByPath(initialBlockBody *hclwrite.Body, path
 
 // RemoveAttributeByPath removes an attribute specified by a path, starting from an initialBlockBody.
 // The path can point to an attribute directly within initialBlockBody or within a deeply nested block.
-// Returns the number of modifications (0 or 1) and an error if the path is invalid or any intermediate block is not found.
+// A "*" segment stands for every block of the preceding type, so `["node_pool", "*", "initial_node_count"]`
+// removes the attribute from all node pools.
+// Returns the number of modifications (0 or 1, or the total across all blocks matched by "*") and an error
+// if the path is invalid or any intermediate block is not found.
 // If the attribute to be removed does not exist at the specified path, it's a no-op and returns (0, nil).
 func (m *Modifier) RemoveAttributeByPath(initialBlockBody *hclwrite.Body, path []string) (int, error) {
 	if initialBlockBody == nil {
@@ -265,6 +501,12 @@ func (m *Modifier) RemoveAttributeByPath(initialBlockBody *hclwrite.Body, path [
 	logger := m.Logger.With(zap.Strings("path", path))
 	logger.Debug("RemoveAttributeByPath: Attempting to remove attribute.")
 
+	if total, ok, err := m.applyToWildcardPath(initialBlockBody, path, func(body *hclwrite.Body, rest []string) (int, error) {
+		return m.RemoveAttributeByPath(body, rest)
+	}); ok {
+		return total, err
+	}
+
 	attributeName := path[len(path)-1]
 	blockPath := path[:len(path)-1]
 
@@ -275,7 +517,7 @@ func (m *Modifier) RemoveAttributeByPath(initialBlockBody *hclwrite.Body, path [
 		parentBlock, err := m.GetNestedBlock(initialBlockBody, blockPath)
 		if err != nil {
 			// Check if the error is because the block was not found.
-			if _, ok := err.(interface{ IsNotFound() bool }); ok || (err.Error() != "" && (strings.Contains(err.Error(), "not found at path level") || strings.Contains(err.Error(), "target block not found at path"))) {
+			if errors.Is(err, types.ErrBlockNotFound) {
 				logger.Debug("RemoveAttributeByPath: Parent block not found, attribute cannot be removed (no-op).", zap.Error(err))
 				return 0, nil // 0 modifications, No error
 			}
@@ -290,16 +532,28 @@ func (m *Modifier) RemoveAttributeByPath(initialBlockBody *hclwrite.Body, path [
 		targetBody = parentBlock.Body()
 	}
 
-	if targetBody.GetAttribute(attributeName) == nil {
+	attr := targetBody.GetAttribute(attributeName)
+	if attr == nil {
 		logger.Debug("RemoveAttributeByPath: Attribute to remove not found, no action needed.", zap.String("attributeName", attributeName))
 		return 0, nil
 	}
 
+	removedValue := m.attributeValueLogField("removedValue", attr)
+	removeDetachedLeadComments(targetBody, attr.BuildTokens(nil))
 	targetBody.RemoveAttribute(attributeName)
-	logger.Info("RemoveAttributeByPath: Successfully removed attribute.", zap.String("attributeName", attributeName))
+	logger.Info("RemoveAttributeByPath: Successfully removed attribute.", zap.String("attributeName", attributeName), removedValue)
 	return 1, nil
 }
 
+// attributeValueLogField returns a log field named key describing the value of attr: the evaluated value if it
+// is a literal, otherwise the source text of its expression (e.g. `var.node_version`).
+func (m *Modifier) attributeValueLogField(key string, attr *hclwrite.Attribute) zap.Field {
+	if val, err := m.evaluateAttribute(attr, nil); err == nil {
+		return zap.String(key, val.GoString())
+	}
+	return zap.String(key, strings.TrimSpace(string(attr.Expr().BuildTokens(nil).Bytes())))
+}
+
 // RemoveNestedBlockByPath removes a nested block specified by a path, starting from an initialBlockBody.
 // Returns the number of modifications (0 or 1) and an error if the path is invalid or any intermediate parent block is not found.
 // If the block to be removed does not exist at the specified path, it's a no-op and returns (0, nil).
@@ -324,7 +578,7 @@ func (m *Modifier) RemoveNestedBlockByPath(initialBlockBody *hclwrite.Body, path
 		parentBlock, err := m.GetNestedBlock(initialBlockBody, parentBlockPath)
 		if err != nil {
 			// Check if the error is because the block was not found.
-			if _, ok := err.(interface{ IsNotFound() bool }); ok || (err.Error() != "" && (strings.Contains(err.Error(), "not found at path level") || strings.Contains(err.Error(), "target block not found at path"))) {
+			if errors.Is(err, types.ErrBlockNotFound) {
 				logger.Debug("RemoveNestedBlockByPath: Parent block of the block to remove not found, removal is a no-op.", zap.Error(err))
 				return 0, nil
 			}
@@ -352,15 +606,25 @@ func (m *Modifier) RemoveNestedBlockByPath(initialBlockBody *hclwrite.Body, path
 		return 0, nil
 	}
 
-	if removed := bodyToRemoveFrom.RemoveBlock(blockToRemove); !removed {
+	if err := removeNestedBlock(bodyToRemoveFrom, blockToRemove); err != nil {
 		logger.Error("RemoveNestedBlockByPath: Failed to remove block using RemoveBlock method.", zap.String("blockToRemoveName", blockToRemoveName))
-		return 0, fmt.Errorf("failed to remove block '%s'", blockToRemoveName)
+		return 0, err
 	}
 
 	logger.Info("RemoveNestedBlockByPath: Successfully removed nested block.", zap.String("blockToRemoveName", blockToRemoveName))
 	return 1, nil
 }
 
+// removeNestedBlock removes block, a direct child of body, along with the block comments above it
+// (see removeDetachedLeadComments). Returns an error if block isn't part of body.
+func removeNestedBlock(body *hclwrite.Body, block *hclwrite.Block) error {
+	removeDetachedLeadComments(body, block.BuildTokens(nil))
+	if removed := body.RemoveBlock(block); !removed {
+		return fmt.Errorf("failed to remove block '%s'", block.Type())
+	}
+	return nil
+}
+
 // ApplyRules processes a slice of Rule definitions and applies them to the Modifier's HCL file.
 // It iterates through each rule and, for each rule, through the resource blocks in the HCL file.
 //
@@ -374,141 +638,273 @@ func (m *Modifier) RemoveNestedBlockByPath(initialBlockBody *hclwrite.Body, path
 //     are applied to that sub-block. Paths in conditions/actions are then relative to this
 //     nested sub-block's body.
 //
+// If a rule sets TargetResourceLabelPattern, only resources whose name matches it are processed.
+//
+// Rules are applied in the given order, except that a rule listing other rules in RunAfter is moved after
+// them; a dependency cycle is an error and no rule is applied then.
+//
+// Rules are identified by Name: if the same name appears more than once in inputRules, only the
+// first occurrence is applied and a warning is logged for the others.
+//
+// A resource can opt out of rules with a `# gke-tf-cleaner:disable` comment directly above it
+// (optionally `=<rule name prefix>[,...]` to only skip some rules); see disableAnnotation.
+//
 // The function accumulates the total number of successful modifications and a list of any errors
 // encountered. Processing continues even if some rules or actions result in errors.
 func (m *Modifier) ApplyRules(inputRules []types.Rule) (modifications int, errors []error) {
-	m.Logger.Info("Starting ApplyRules processing.", zap.Int("numberOfRules", len(inputRules)))
-	totalModifications := 0
-	var collectedErrors []error
+	report, errs := m.ApplyRulesWithReport(inputRules)
+	return len(report.Changes), errs
+}
 
+// ApplyRulesWithReport applies rules exactly like ApplyRules, but instead of a modification count it
+// returns a ChangeReport listing every individual change with its rule name, resource labels,
+// action type and affected path.
+func (m *Modifier) ApplyRulesWithReport(inputRules []types.Rule) (types.ChangeReport, []error) {
 	if m.file == nil || m.file.Body() == nil {
 		m.Logger.Error("ApplyRules: Modifier's file or file body is nil.")
-		collectedErrors = append(collectedErrors, fmt.Errorf("modifier's file or file body cannot be nil"))
-		return 0, collectedErrors
+		return types.ChangeReport{}, []error{fmt.Errorf("modifier's file or file body cannot be nil")}
+	}
+	return m.applyRules(inputRules, m.file.Body().Blocks())
+}
+
+// ApplyRulesToBlock applies rules exactly like ApplyRules, but only to block instead of every block of the file,
+// e.g. to clean up a single cluster. Rules that don't target block (by block type, resource type or
+// TargetResourceLabelPattern) are skipped. block doesn't have to belong to the Modifier's file.
+// Returns the number of modifications made and the errors encountered.
+func (m *Modifier) ApplyRulesToBlock(block *hclwrite.Block, rules []types.Rule) (int, []error) {
+	if block == nil {
+		return 0, []error{fmt.Errorf("ApplyRulesToBlock: block cannot be nil")}
+	}
+	report, errs := m.applyRules(rules, []*hclwrite.Block{block})
+	return len(report.Changes), errs
+}
+
+// applyRules applies inputRules to the targeted blocks among blocks, which are top-level blocks such as resources;
+// see ApplyRules.
+func (m *Modifier) applyRules(inputRules []types.Rule, blocks []*hclwrite.Block) (types.ChangeReport, []error) {
+	m.Logger.Info("Starting ApplyRules processing.", zap.Int("numberOfRules", len(inputRules)), zap.Int("numberOfBlocks", len(blocks)))
+	var report types.ChangeReport
+	var collectedErrors []error
+
+	inputRules, err := orderRules(inputRules)
+	if err != nil {
+		m.Logger.Error("ApplyRules: Failed to order rules by their dependencies.", zap.Error(err))
+		collectedErrors = append(collectedErrors, err)
+		return report, collectedErrors
+	}
+
+	if m.ResolveReferences && m.referenceCtx == nil {
+		m.referenceCtx = m.evalContext()
+		defer func() { m.referenceCtx = nil }()
 	}
 
+	appliedRuleNames := make(map[string]bool, len(inputRules))
 	for _, currentRule := range inputRules {
 		ruleLogger := m.Logger.With(zap.String("ruleName", currentRule.Name), zap.String("targetResourceType", currentRule.TargetResourceType))
+		if appliedRuleNames[currentRule.Name] {
+			ruleLogger.Warn("Skipping duplicate rule; a rule with the same name was already applied.")
+			continue
+		}
+		appliedRuleNames[currentRule.Name] = true
 		ruleLogger.Debug("Processing rule.")
 
 		// Initialize ExecutionType if it's empty
 		if currentRule.ExecutionType == "" {
 			currentRule.ExecutionType = types.RuleExecutionStandard
 		}
-		ruleLogger = ruleLogger.With(zap.String("executionType", string(currentRule.ExecutionType)))
+		// Rules target resource blocks unless TargetBlockType says otherwise.
+		if currentRule.TargetBlockType == "" {
+			currentRule.TargetBlockType = "resource"
+		}
+		ruleLogger = ruleLogger.With(zap.String("executionType", string(currentRule.ExecutionType)), zap.String("targetBlockType", currentRule.TargetBlockType))
 
-		for _, resourceBlock := range m.file.Body().Blocks() {
-			if resourceBlock.Type() != "resource" || len(resourceBlock.Labels()) == 0 || resourceBlock.Labels()[0] != currentRule.TargetResourceType {
+		var labelPattern *regexp.Regexp
+		if currentRule.TargetResourceLabelPattern != "" {
+			var err error
+			labelPattern, err = regexp.Compile(currentRule.TargetResourceLabelPattern)
+			if err != nil {
+				ruleLogger.Error("Invalid TargetResourceLabelPattern, skipping rule.", zap.Error(err))
+				collectedErrors = append(collectedErrors, fmt.Errorf("rule '%s' has an invalid TargetResourceLabelPattern: %w", currentRule.Name, err))
 				continue
 			}
+		}
 
-			resourceLogger := ruleLogger.With(zap.Strings("resourceLabels", resourceBlock.Labels()))
-			resourceLogger.Debug("Target resource matched.")
+		stats := types.RuleStats{RuleName: currentRule.Name}
+		for _, resourceBlock := range blocks {
+			collectedErrors = append(collectedErrors, m.applyRuleToBlock(currentRule, labelPattern, resourceBlock, ruleLogger, &report, &stats)...)
+		}
+		ruleLogger.Debug("Rule processed.", zap.Int("resourcesMatched", stats.Matched), zap.Int("resourcesMeetingConditions", stats.ConditionsMet), zap.Int("resourcesActedOn", stats.Acted))
+		report.RuleStats = append(report.RuleStats, stats)
+	}
 
-			// Standard execution: conditions and actions apply to the resourceBlock itself.
-			// Paths for conditions/actions are relative to the resourceBlock's body.
-			if currentRule.ExecutionType == types.RuleExecutionStandard {
-				resourceLogger.Debug("Executing as Standard Rule. Checking conditions for the resource block itself.")
-				conditionsMet := true
-				for _, condition := range currentRule.Conditions {
-					condLogger := resourceLogger.With(zap.String("conditionType", string(condition.Type)), zap.Strings("conditionPath", condition.Path))
-					if !m.checkCondition(resourceBlock.Body(), condition, condLogger) {
-						conditionsMet = false
-						break
-					}
+	m.Logger.Info("ApplyRules processing finished.", zap.Int("totalModifications", len(report.Changes)), zap.Int("numberOfErrors", len(collectedErrors)))
+	if len(collectedErrors) > 0 {
+		for _, e := range collectedErrors {
+			m.Logger.Debug("ApplyRules encountered an error during processing.", zap.Error(e))
+		}
+		return report, collectedErrors
+	}
+	return report, nil
+}
+
+// applyRuleToBlock applies currentRule to resourceBlock if the block is one of the rule's targets, appending the
+// changes made to report and counting the block in stats. currentRule must have its defaults filled in, and
+// labelPattern is its compiled TargetResourceLabelPattern (nil if it has none). Returns the errors encountered.
+func (m *Modifier) applyRuleToBlock(currentRule types.Rule, labelPattern *regexp.Regexp, resourceBlock *hclwrite.Block, ruleLogger *zap.Logger, report *types.ChangeReport, stats *types.RuleStats) []error {
+	if resourceBlock.Type() != currentRule.TargetBlockType || len(resourceBlock.Labels()) == 0 || resourceBlock.Labels()[0] != currentRule.TargetResourceType {
+		return nil
+	}
+	if labelPattern != nil && (len(resourceBlock.Labels()) < 2 || !labelPattern.MatchString(resourceBlock.Labels()[1])) {
+		ruleLogger.Debug("Resource name doesn't match TargetResourceLabelPattern, skipping.", zap.Strings("resourceLabels", resourceBlock.Labels()))
+		return nil
+	}
+
+	resourceLogger := ruleLogger.With(zap.Strings("resourceLabels", resourceBlock.Labels()))
+	resourceLogger.Debug("Target resource matched.")
+	stats.Matched++
+
+	if isRuleDisabledForBlock(resourceBlock, currentRule.Name) {
+		resourceLogger.Info("Rule disabled for resource by annotation, skipping.")
+		return nil
+	}
+
+	if !m.checkConditions(resourceBlock.Body(), resourceBlock.Body(), currentRule.ResourceConditions, resourceLogger) {
+		resourceLogger.Debug("Not all resource conditions met for resource block.")
+		return nil
+	}
+
+	var collectedErrors []error
+	reportedBefore := len(report.Changes) + len(report.Warnings)
+	// Standard execution: conditions and actions apply to the resourceBlock itself.
+	// Paths for conditions/actions are relative to the resourceBlock's body.
+	if currentRule.ExecutionType == types.RuleExecutionStandard {
+		resourceLogger.Debug("Executing as Standard Rule. Checking conditions for the resource block itself.")
+		if m.checkConditions(resourceBlock.Body(), resourceBlock.Body(), currentRule.Conditions, resourceLogger) {
+			resourceLogger.Info("All conditions met for resource. Performing actions on the resource block.")
+			stats.ConditionsMet++
+			for _, action := range currentRule.Actions {
+				actLogger := resourceLogger.With(zap.String("actionType", string(action.Type)), zap.Strings("actionPath", action.Path))
+				errAction := m.performAction(resourceBlock.Body(), resourceBlock.Body(), action, actLogger, currentRule.Name, resourceBlock.Labels(), nil, report)
+				if errAction != nil {
+					collectedErrors = append(collectedErrors, errAction)
 				}
+			}
+		} else {
+			resourceLogger.Debug("Not all conditions met for resource block.")
+		}
+		// ForEachNestedBlock execution: conditions and actions apply to each matching nested block
+		// within the resourceBlock. Paths are relative to the nested block's body.
+	} else if currentRule.ExecutionType == types.RuleExecutionForEachNestedBlock {
+		resourceLogger.Debug("Executing as ForEachNestedBlock Rule.", zap.String("nestedBlockTargetType", currentRule.NestedBlockTargetType))
+		if currentRule.NestedBlockTargetType == "" {
+			resourceLogger.Warn("NestedBlockTargetType is not defined for ForEachNestedBlock rule. Skipping this rule for this resource.", zap.String("ruleName", currentRule.Name))
+			return []error{fmt.Errorf("rule '%s' is ForEachNestedBlock but NestedBlockTargetType is empty", currentRule.Name)}
+		}
 
-				if conditionsMet {
-					resourceLogger.Info("All conditions met for resource. Performing actions on the resource block.")
+		// Iterate over direct sub-blocks of the matched resource block.
+		conditionsMet := false
+		for _, nestedBlock := range m.GetAllBlocksOfType(resourceBlock.Body(), currentRule.NestedBlockTargetType) {
+			if len(currentRule.NestedBlockTargetLabels) == 0 || slices.Equal(nestedBlock.Labels(), currentRule.NestedBlockTargetLabels) {
+				nestedBlockLogger := resourceLogger.With(zap.String("nestedBlockType", nestedBlock.Type()), zap.Strings("nestedBlockLabels", nestedBlock.Labels()))
+				nestedBlockLogger.Debug("Matching nested block found. Checking conditions for this nested block.")
+
+				// Paths in 'condition.Path' are relative to this 'nestedBlock.Body()'.
+				if m.checkConditions(nestedBlock.Body(), resourceBlock.Body(), currentRule.Conditions, nestedBlockLogger) {
+					nestedBlockLogger.Info("All conditions met for nested block. Performing actions on this nested block.")
+					conditionsMet = true
 					for _, action := range currentRule.Actions {
-						actLogger := resourceLogger.With(zap.String("actionType", string(action.Type)), zap.Strings("actionPath", action.Path))
-						mods, errAction := m.performAction(resourceBlock.Body(), action, actLogger, currentRule.Name, resourceBlock.Labels())
-						totalModifications += mods
+						actLogger := nestedBlockLogger.With(zap.String("actionType", string(action.Type)), zap.Strings("actionPath", action.Path))
+						// Paths in 'action.Path' are relative to this 'nestedBlock.Body()'.
+						errAction := m.performAction(nestedBlock.Body(), resourceBlock.Body(), action, actLogger, currentRule.Name, resourceBlock.Labels(), []string{nestedBlock.Type()}, report)
 						if errAction != nil {
 							collectedErrors = append(collectedErrors, errAction)
 						}
 					}
 				} else {
-					resourceLogger.Debug("Not all conditions met for resource block.")
-				}
-				// ForEachNestedBlock execution: conditions and actions apply to each matching nested block
-				// within the resourceBlock. Paths are relative to the nested block's body.
-			} else if currentRule.ExecutionType == types.RuleExecutionForEachNestedBlock {
-				resourceLogger.Debug("Executing as ForEachNestedBlock Rule.", zap.String("nestedBlockTargetType", currentRule.NestedBlockTargetType))
-				if currentRule.NestedBlockTargetType == "" {
-					resourceLogger.Warn("NestedBlockTargetType is not defined for ForEachNestedBlock rule. Skipping this rule for this resource.", zap.String("ruleName", currentRule.Name))
-					collectedErrors = append(collectedErrors, fmt.Errorf("rule '%s' is ForEachNestedBlock but NestedBlockTargetType is empty", currentRule.Name))
-					continue
-				}
-
-				// Iterate over direct sub-blocks of the matched resource block.
-				for _, nestedBlock := range resourceBlock.Body().Blocks() {
-					if nestedBlock.Type() == currentRule.NestedBlockTargetType {
-						nestedBlockLogger := resourceLogger.With(zap.String("nestedBlockType", nestedBlock.Type()), zap.Strings("nestedBlockLabels", nestedBlock.Labels()))
-						nestedBlockLogger.Debug("Matching nested block found. Checking conditions for this nested block.")
-
-						conditionsMet := true
-						for _, condition := range currentRule.Conditions {
-							condLogger := nestedBlockLogger.With(zap.String("conditionType", string(condition.Type)), zap.Strings("conditionPath", condition.Path))
-							// Paths in 'condition.Path' are relative to this 'nestedBlock.Body()'.
-							if !m.checkCondition(nestedBlock.Body(), condition, condLogger) {
-								conditionsMet = false
-								break
-							}
-						}
-
-						if conditionsMet {
-							nestedBlockLogger.Info("All conditions met for nested block. Performing actions on this nested block.")
-							for _, action := range currentRule.Actions {
-								actLogger := nestedBlockLogger.With(zap.String("actionType", string(action.Type)), zap.Strings("actionPath", action.Path))
-								// Paths in 'action.Path' are relative to this 'nestedBlock.Body()'.
-								mods, errAction := m.performAction(nestedBlock.Body(), action, actLogger, currentRule.Name, resourceBlock.Labels())
-								totalModifications += mods
-								if errAction != nil {
-									collectedErrors = append(collectedErrors, errAction)
-								}
-							}
-						} else {
-							nestedBlockLogger.Debug("Not all conditions met for this nested block.")
-						}
-					}
+					nestedBlockLogger.Debug("Not all conditions met for this nested block.")
 				}
 			}
 		}
+		if conditionsMet {
+			stats.ConditionsMet++
+		}
+	}
+	if len(report.Changes)+len(report.Warnings) > reportedBefore {
+		stats.Acted++
 	}
+	return collectedErrors
+}
 
-	m.Logger.Info("ApplyRules processing finished.", zap.Int("totalModifications", totalModifications), zap.Int("numberOfErrors", len(collectedErrors)))
-	if len(collectedErrors) > 0 {
-		for _, e := range collectedErrors {
-			m.Logger.Error("ApplyRules encountered an error during processing.", zap.Error(e))
+// VerifyIdempotent applies rules to a copy of the current file and reports whether that second pass
+// left it unchanged. The Modifier itself is never modified.
+// Returns true and 0 if no rule made a change, otherwise false and the number of modifications the
+// second pass made, and an error if copying the file or applying the rules failed.
+func (m *Modifier) VerifyIdempotent(rules []types.Rule) (bool, int, error) {
+	if m.file == nil {
+		return false, 0, fmt.Errorf("VerifyIdempotent: modifier's file cannot be nil")
+	}
+	copied := m.Clone()
+	if copied == nil {
+		return false, 0, fmt.Errorf("VerifyIdempotent: failed to copy file")
+	}
+	modifications, errs := copied.ApplyRules(rules)
+	if len(errs) > 0 {
+		return false, modifications, fmt.Errorf("VerifyIdempotent: second pass encountered %d error(s): %w", len(errs), errors.Join(errs...))
+	}
+	return modifications == 0, modifications, nil
+}
+
+// checkConditions reports whether all conditions are met for initialBlockBody, stopping at the first one that isn't.
+// resourceBody is the body of the resource block initialBlockBody belongs to (see checkCondition).
+// An empty list of conditions is always met.
+func (m *Modifier) checkConditions(initialBlockBody, resourceBody *hclwrite.Body, conditions []types.RuleCondition, logger *zap.Logger) bool {
+	for _, condition := range conditions {
+		condLogger := logger.With(zap.String("conditionType", string(condition.Type)), zap.Strings("conditionPath", condition.Path))
+		if !m.checkCondition(initialBlockBody, resourceBody, condition, condLogger) {
+			return false
 		}
-		return totalModifications, collectedErrors
 	}
-	return totalModifications, nil
+	return true
 }
 
 // checkCondition evaluates a single RuleCondition against a given hclwrite.Body.
 // This function is a helper for ApplyRules, used for both standard and nested block execution types.
 //
 // condition: The RuleCondition to check. Paths within the condition are relative to initialBlockBody.
+// resourceBody: The body of the resource block being processed, which ComparePath is relative to when
+// condition.CompareInResource is set. It is initialBlockBody itself for standard execution.
 // condLogger: A zap.Logger instance pre-configured with context for this condition check.
 // Returns true if the condition is met, false otherwise.
-func (m *Modifier) checkCondition(initialBlockBody *hclwrite.Body, condition types.RuleCondition, condLogger *zap.Logger) bool {
+func (m *Modifier) checkCondition(initialBlockBody, resourceBody *hclwrite.Body, condition types.RuleCondition, condLogger *zap.Logger) bool {
 	switch condition.Type {
 	case types.AttributeExists:
-		// Checks if an attribute at condition.Path exists within initialBlockBody.
-		_, _, err := m.GetAttributeValueByPath(initialBlockBody, condition.Path)
-		if err != nil {
-			condLogger.Debug("Condition AttributeExists not met (attribute not found or error accessing).", zap.Error(err))
+		// Checks if an attribute at condition.Path exists within initialBlockBody, whatever its value expression.
+		if m.GetAttributeByPath(initialBlockBody, condition.Path) == nil {
+			condLogger.Debug("Condition AttributeExists not met (attribute not found).")
 			return false
 		}
-	case types.AttributeDoesntExist:
-		// Checks if an attribute at condition.Path does NOT exist within initialBlockBody.
-		val, _, err := m.GetAttributeValueByPath(initialBlockBody, condition.Path)
-		if err == nil && !val.IsNull() {
-			condLogger.Debug("Condition AttributeDoesntExist not met (attribute was found or null).")
+	case types.AttributeExistsAnyOf:
+		// Checks if at least one of the attributes at condition.Paths exists, the same way AttributeExists does.
+		found := false
+		for _, path := range condition.Paths {
+			if m.checkCondition(initialBlockBody, resourceBody, types.RuleCondition{Type: types.AttributeExists, Path: path}, condLogger.With(zap.Strings("candidatePath", path))) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			condLogger.Debug("Condition AttributeExistsAnyOf not met (none of the attributes found).", zap.Int("candidates", len(condition.Paths)))
 			return false
 		}
+	case types.AttributeDoesntExist:
+		// Checks if an attribute at condition.Path does NOT exist within initialBlockBody. An attribute set to null
+		// counts as absent; one whose value can't be evaluated (e.g. `var.version`) still exists.
+		if attr := m.GetAttributeByPath(initialBlockBody, condition.Path); attr != nil {
+			if val, err := m.GetAttributeValue(attr); err != nil || !val.IsNull() {
+				condLogger.Debug("Condition AttributeDoesntExist not met (attribute was found and isn't null).")
+				return false
+			}
+		}
 	case types.BlockExists:
 		// Checks if a nested block at condition.Path exists within initialBlockBody.
 		_, err := m.GetNestedBlock(initialBlockBody, condition.Path)
@@ -516,6 +912,95 @@ func (m *Modifier) checkCondition(initialBlockBody *hclwrite.Body, condition typ
 			condLogger.Debug("Condition BlockExists not met (block not found or error accessing).", zap.Error(err))
 			return false
 		}
+	case types.BlockDoesntExist:
+		// Checks that no nested block at condition.Path exists within initialBlockBody.
+		_, err := m.GetNestedBlock(initialBlockBody, condition.Path)
+		if !errors.Is(err, types.ErrBlockNotFound) {
+			condLogger.Debug("Condition BlockDoesntExist not met (block found or error accessing).", zap.Error(err))
+			return false
+		}
+	case types.AttributeIsEmpty:
+		// Checks if an attribute at condition.Path exists and is an empty map.
+		val, _, err := m.GetAttributeValueByPath(initialBlockBody, condition.Path)
+		if err != nil {
+			condLogger.Debug("AttributeIsEmpty: Attribute not found.", zap.Error(err))
+			return false
+		}
+		if val.IsNull() || !(val.Type().IsMapType() || val.Type().IsObjectType()) {
+			condLogger.Debug("AttributeIsEmpty: Attribute is not a map, condition not met.", zap.Any("actualType", val.Type()))
+			return false
+		}
+		if val.LengthInt() > 0 {
+			condLogger.Debug("AttributeIsEmpty not met.", zap.Int("actualLength", val.LengthInt()))
+			return false
+		}
+	case types.AttributeIsEmptyList:
+		// Checks if an attribute at condition.Path exists and is an empty list, tuple or set, e.g. `node_locations = []`.
+		val, _, err := m.GetAttributeValueByPath(initialBlockBody, condition.Path)
+		if err != nil {
+			condLogger.Debug("AttributeIsEmptyList: Attribute not found.", zap.Error(err))
+			return false
+		}
+		if val.IsNull() || !(val.Type().IsListType() || val.Type().IsTupleType() || val.Type().IsSetType()) {
+			condLogger.Debug("AttributeIsEmptyList: Attribute is not a list, condition not met.", zap.Any("actualType", val.Type()))
+			return false
+		}
+		if val.LengthInt() > 0 {
+			condLogger.Debug("AttributeIsEmptyList not met.", zap.Int("actualLength", val.LengthInt()))
+			return false
+		}
+	case types.BlockCountEquals:
+		// Checks if the number of blocks of type path[len-1] within the parent at path[:len-1] equals condition.ExpectedValue.
+		if len(condition.Path) == 0 {
+			condLogger.Warn("BlockCountEquals: Path cannot be empty, condition not met.")
+			return false
+		}
+		expectedCount, err := strconv.Atoi(condition.ExpectedValue)
+		if err != nil {
+			condLogger.Warn("BlockCountEquals: ExpectedValue is not an integer, condition not met.", zap.String("expectedStr", condition.ExpectedValue), zap.Error(err))
+			return false
+		}
+		parentBody := initialBlockBody
+		if len(condition.Path) > 1 {
+			parentBlock, err := m.GetNestedBlock(initialBlockBody, condition.Path[:len(condition.Path)-1])
+			if err != nil {
+				// A missing parent contains zero blocks.
+				condLogger.Debug("BlockCountEquals: Parent block not found, counting zero blocks.", zap.Error(err))
+				parentBody = nil
+			} else {
+				parentBody = parentBlock.Body()
+			}
+		}
+		count := 0
+		if parentBody != nil {
+			count = len(m.GetAllBlocksOfType(parentBody, condition.Path[len(condition.Path)-1]))
+		}
+		if count != expectedCount {
+			condLogger.Debug("BlockCountEquals not met.", zap.Int("actualCount", count), zap.Int("expectedCount", expectedCount))
+			return false
+		}
+	case types.BlockIsEmpty:
+		// Checks if a nested block at condition.Path exists and is effectively empty.
+		block, err := m.GetNestedBlock(initialBlockBody, condition.Path)
+		if err != nil {
+			condLogger.Debug("Condition BlockIsEmpty not met (block not found or error accessing).", zap.Error(err))
+			return false
+		}
+		if !isBlockEffectivelyEmpty(block.Body()) {
+			condLogger.Debug("Condition BlockIsEmpty not met (block has content).")
+			return false
+		}
+	case types.BlockMatchesDefaults:
+		// Checks if a nested block at condition.Path exists and only holds the attributes of condition.Defaults,
+		// each set to its default value. Each default is parsed the same way as for AttributeValueEquals.
+		block, err := m.GetNestedBlock(initialBlockBody, condition.Path)
+		if err != nil {
+			condLogger.Debug("Condition BlockMatchesDefaults not met (block not found or error accessing).", zap.Error(err))
+			return false
+		}
+		if !m.blockMatchesDefaults(block.Body(), condition.Defaults, condLogger) {
+			return false
+		}
 	case types.AttributeValueEquals:
 		// Checks if an attribute at condition.Path exists and its value equals condition.ExpectedValue.
 		// Comparison logic attempts to parse ExpectedValue based on the actual attribute's type.
@@ -525,33 +1010,11 @@ func (m *Modifier) checkCondition(initialBlockBody *hclwrite.Body, condition typ
 			return false
 		}
 
-		var expectedCtyValue cty.Value
-		var parseErr error
-
-		// parse ExpectedValue based on the actual value's type
-		switch val.Type() {
-		case cty.String:
-			expectedCtyValue = cty.StringVal(condition.ExpectedValue)
-		case cty.Bool:
-			boolVal, errConv := strconv.ParseBool(condition.ExpectedValue)
-			if errConv != nil {
-				parseErr = fmt.Errorf("failed to parse ExpectedValue '%s' as bool: %w", condition.ExpectedValue, errConv)
-			} else {
-				expectedCtyValue = cty.BoolVal(boolVal)
-			}
-		case cty.Number:
-			if intVal, errConv := strconv.ParseInt(condition.ExpectedValue, 10, 64); errConv == nil {
-				expectedCtyValue = cty.NumberIntVal(intVal)
-			} else if floatVal, errConv := strconv.ParseFloat(condition.ExpectedValue, 64); errConv == nil {
-				expectedCtyValue = cty.NumberFloatVal(floatVal)
-			} else {
-				parseErr = fmt.Errorf("failed to parse ExpectedValue '%s' as number: %v or %v", condition.ExpectedValue, errConv, errConv)
-			}
-		default:
+		if !isPrimitiveType(val.Type()) {
 			condLogger.Warn("AttributeValueEquals: Actual value type is not a primitive type supported for robust ExpectedValue parsing. Condition will likely not be met.", zap.Any("actualValueType", val.Type()))
 			return false
 		}
-
+		expectedCtyValue, parseErr := parseExpectedValue(condition.ExpectedValue, val.Type())
 		if parseErr != nil {
 			condLogger.Warn("AttributeValueEquals: Error parsing ExpectedValue, condition not met.", zap.Error(parseErr), zap.String("expectedStr", condition.ExpectedValue), zap.Any("actualType", val.Type()))
 			return false
@@ -560,58 +1023,357 @@ func (m *Modifier) checkCondition(initialBlockBody *hclwrite.Body, condition typ
 			condLogger.Debug("AttributeValueEquals not met.", zap.Any("actualValue", val.GoString()), zap.Any("parsedExpectedValue", expectedCtyValue.GoString()))
 			return false
 		}
-	default:
-		condLogger.Warn("Unknown condition type.")
-		return false
-	}
-	return true
-}
-
-// performAction executes a single RuleAction on a given hclwrite.Body.
-// This function is a helper for ApplyRules, used for both standard and nested block execution types.
-//
-// action: The RuleAction to perform. Paths within the action are relative to initialBlockBody.
-// actLogger: A zap.Logger instance pre-configured with context for this action.
-// ruleName: The name of the rule whose action is being performed (for error reporting).
-// resourceLabels: The labels of the main resource block being processed (for error reporting).
-// Returns the number of modifications made and an error if the action failed.
-func (m *Modifier) performAction(initialBlockBody *hclwrite.Body, action types.RuleAction, actLogger *zap.Logger, ruleName string, resourceLabels []string) (int, error) {
-	var errAction error
-	switch action.Type {
-	case types.RemoveAttribute:
-		mods, err := m.RemoveAttributeByPath(initialBlockBody, action.Path)
-		// errAction will be set here for the final error handler
-		errAction = err
-		if errAction == nil {
-			if mods > 0 {
-				actLogger.Info("Action RemoveAttribute successful.", zap.Int("attributesRemoved", mods))
-			} else {
-				actLogger.Debug("Action RemoveAttribute resulted in no actual changes (attribute likely not found or parent path missing).")
-			}
-			return mods, nil
+	case types.NullValue:
+		// Checks if an attribute at condition.Path exists and evaluates to null, e.g. `min_master_version = null`.
+		val, _, err := m.GetAttributeValueByPath(initialBlockBody, condition.Path)
+		if err != nil {
+			condLogger.Debug("NullValue: Attribute not found or not evaluable.", zap.Error(err))
+			return false
 		}
-		// If errAction is not nil, it will be handled by the block at the end of performAction
-		// We must return 0 modifications in case of an error from the helper.
-		return 0, errAction
-	case types.RemoveBlock:
-		mods, err := m.RemoveNestedBlockByPath(initialBlockBody, action.Path)
-		errAction = err
-		if errAction == nil {
-			if mods > 0 {
-				actLogger.Info("Action RemoveBlock successful.", zap.Int("blocksRemoved", mods))
+		if !val.IsNull() {
+			condLogger.Debug("NullValue not met.", zap.Any("actualValue", val.GoString()))
+			return false
+		}
+	case types.AttributeValueIn:
+		// Checks if an attribute at condition.Path exists and its value equals one of condition.ExpectedValues.
+		// Each expected value is parsed the same way as for AttributeValueEquals.
+		if len(condition.ExpectedValues) == 0 {
+			condLogger.Warn("AttributeValueIn: ExpectedValues is empty, condition can never be met.")
+			return false
+		}
+		val, _, err := m.GetAttributeValueByPath(initialBlockBody, condition.Path)
+		if err != nil {
+			condLogger.Debug("AttributeValueIn: Attribute not found for comparison.", zap.Error(err))
+			return false
+		}
+		if !isPrimitiveType(val.Type()) {
+			condLogger.Warn("AttributeValueIn: Actual value type is not a primitive type supported for robust ExpectedValues parsing. Condition will likely not be met.", zap.Any("actualValueType", val.Type()))
+			return false
+		}
+		found := false
+		for _, expectedStr := range condition.ExpectedValues {
+			expectedCtyValue, parseErr := parseExpectedValue(expectedStr, val.Type())
+			if parseErr != nil {
+				condLogger.Debug("AttributeValueIn: Skipping expected value that can't be parsed as the actual type.", zap.Error(parseErr), zap.String("expectedStr", expectedStr))
+				continue
+			}
+			if val.Equals(expectedCtyValue).True() {
+				found = true
+				break
+			}
+		}
+		if !found {
+			condLogger.Debug("AttributeValueIn not met.", zap.Any("actualValue", val.GoString()), zap.Strings("expectedValues", condition.ExpectedValues))
+			return false
+		}
+	case types.AttributeValueNotIn:
+		// Checks that the attribute at condition.Path is absent, or that its value equals none of condition.ExpectedValues.
+		// An attribute whose value can't be evaluated might hold any of them, so it doesn't meet the condition.
+		_, attr, err := m.GetAttributeValueByPath(initialBlockBody, condition.Path)
+		if err != nil {
+			if attr != nil {
+				condLogger.Debug("AttributeValueNotIn: Attribute value can't be evaluated, condition not met.", zap.Error(err))
+				return false
+			}
+			condLogger.Debug("AttributeValueNotIn: Attribute not found, condition met.")
+			break
+		}
+		if m.checkCondition(initialBlockBody, resourceBody, types.RuleCondition{Type: types.AttributeValueIn, Path: condition.Path, ExpectedValues: condition.ExpectedValues}, condLogger) {
+			condLogger.Debug("AttributeValueNotIn not met.", zap.Strings("expectedValues", condition.ExpectedValues))
+			return false
+		}
+	case types.AttributeValueGreaterThan, types.AttributeValueLessThan:
+		// Checks if an attribute at condition.Path is a number strictly greater (or less) than condition.ExpectedValue.
+		val, _, err := m.GetAttributeValueByPath(initialBlockBody, condition.Path)
+		if err != nil {
+			condLogger.Debug("Numeric comparison: Attribute not found for comparison.", zap.Error(err))
+			return false
+		}
+		expectedCtyValue, err := parseExpectedValue(condition.ExpectedValue, cty.Number)
+		if err != nil {
+			condLogger.Warn("Numeric comparison: Error parsing ExpectedValue, condition not met.", zap.Error(err), zap.String("expectedStr", condition.ExpectedValue))
+			return false
+		}
+		if val.IsNull() || !val.IsKnown() {
+			condLogger.Debug("Numeric comparison: Attribute value is null or unknown, condition not met.")
+			return false
+		}
+		numberVal, err := convert.Convert(val, cty.Number)
+		if err != nil {
+			condLogger.Debug("Numeric comparison: Attribute value is not numeric, condition not met.", zap.Any("actualValue", val.GoString()), zap.Error(err))
+			return false
+		}
+		var met cty.Value
+		if condition.Type == types.AttributeValueGreaterThan {
+			met = numberVal.GreaterThan(expectedCtyValue)
+		} else {
+			met = numberVal.LessThan(expectedCtyValue)
+		}
+		if !met.True() {
+			condLogger.Debug("Numeric comparison not met.", zap.Any("actualValue", numberVal.GoString()), zap.String("expectedStr", condition.ExpectedValue))
+			return false
+		}
+	case types.AttributeValueMatches:
+		// Checks if an attribute at condition.Path is a string matching the regular expression in condition.ExpectedValue.
+		val, _, err := m.GetAttributeValueByPath(initialBlockBody, condition.Path)
+		if err != nil {
+			condLogger.Debug("AttributeValueMatches: Attribute not found for matching.", zap.Error(err))
+			return false
+		}
+		re, err := regexp.Compile(condition.ExpectedValue)
+		if err != nil {
+			condLogger.Warn("AttributeValueMatches: Invalid regular expression in ExpectedValue, condition not met.", zap.String("expectedStr", condition.ExpectedValue), zap.Error(err))
+			return false
+		}
+		if val.IsNull() || val.Type() != cty.String || !re.MatchString(val.AsString()) {
+			condLogger.Debug("AttributeValueMatches not met.", zap.Any("actualValue", val.GoString()))
+			return false
+		}
+	case types.ListLengthGreaterThan:
+		// Checks if an attribute at condition.Path is a list with more elements than condition.ExpectedValue.
+		val, _, err := m.GetAttributeValueByPath(initialBlockBody, condition.Path)
+		if err != nil {
+			condLogger.Debug("ListLengthGreaterThan: Attribute not found.", zap.Error(err))
+			return false
+		}
+		threshold, err := strconv.Atoi(condition.ExpectedValue)
+		if err != nil {
+			condLogger.Warn("ListLengthGreaterThan: ExpectedValue is not an integer, condition not met.", zap.String("expectedStr", condition.ExpectedValue), zap.Error(err))
+			return false
+		}
+		if val.IsNull() || !(val.Type().IsListType() || val.Type().IsTupleType() || val.Type().IsSetType()) {
+			condLogger.Debug("ListLengthGreaterThan: Attribute is not a list, condition not met.", zap.Any("actualType", val.Type()))
+			return false
+		}
+		if val.LengthInt() <= threshold {
+			condLogger.Debug("ListLengthGreaterThan not met.", zap.Int("actualLength", val.LengthInt()), zap.Int("threshold", threshold))
+			return false
+		}
+	case types.ListValuesEqual:
+		// Checks if an attribute at condition.Path is a list with exactly the elements of condition.ExpectedValues.
+		val, _, err := m.GetAttributeValueByPath(initialBlockBody, condition.Path)
+		if err != nil {
+			condLogger.Debug("ListValuesEqual: Attribute not found.", zap.Error(err))
+			return false
+		}
+		if val.IsNull() || !(val.Type().IsListType() || val.Type().IsTupleType() || val.Type().IsSetType()) {
+			condLogger.Debug("ListValuesEqual: Attribute is not a list, condition not met.", zap.Any("actualType", val.Type()))
+			return false
+		}
+		var elements []string
+		for it := val.ElementIterator(); it.Next(); {
+			_, elem := it.Element()
+			elemStr, errConv := convert.Convert(elem, cty.String)
+			if errConv != nil || elemStr.IsNull() || !elemStr.IsKnown() {
+				condLogger.Debug("ListValuesEqual: List element is not a known primitive value, condition not met.")
+				return false
+			}
+			elements = append(elements, elemStr.AsString())
+		}
+		expected := slices.Clone(condition.ExpectedValues)
+		slices.Sort(elements)
+		slices.Sort(expected)
+		if !slices.Equal(elements, expected) {
+			condLogger.Debug("ListValuesEqual not met.", zap.Strings("actualValues", elements), zap.Strings("expectedValues", condition.ExpectedValues))
+			return false
+		}
+	case types.MapKeysEqual:
+		// Checks if an attribute at condition.Path is a map with exactly the keys of condition.ExpectedValues.
+		val, _, err := m.GetAttributeValueByPath(initialBlockBody, condition.Path)
+		if err != nil {
+			condLogger.Debug("MapKeysEqual: Attribute not found.", zap.Error(err))
+			return false
+		}
+		if val.IsNull() || !(val.Type().IsMapType() || val.Type().IsObjectType()) {
+			condLogger.Debug("MapKeysEqual: Attribute is not a map, condition not met.", zap.Any("actualType", val.Type()))
+			return false
+		}
+		var keys []string
+		for it := val.ElementIterator(); it.Next(); {
+			key, _ := it.Element()
+			keys = append(keys, key.AsString())
+		}
+		expected := slices.Clone(condition.ExpectedValues)
+		slices.Sort(keys)
+		slices.Sort(expected)
+		if !slices.Equal(keys, expected) {
+			condLogger.Debug("MapKeysEqual not met.", zap.Strings("actualKeys", keys), zap.Strings("expectedKeys", condition.ExpectedValues))
+			return false
+		}
+	case types.AttributeValueEqualsPath:
+		// Checks if the attributes at condition.Path and condition.ComparePath both exist and have equal values.
+		val, _, err := m.GetAttributeValueByPath(initialBlockBody, condition.Path)
+		if err != nil {
+			condLogger.Debug("AttributeValueEqualsPath: Attribute not found.", zap.Error(err))
+			return false
+		}
+		compareVal, _, err := m.GetAttributeValueByPath(compareBody(initialBlockBody, resourceBody, condition), condition.ComparePath)
+		if err != nil {
+			condLogger.Debug("AttributeValueEqualsPath: Attribute to compare against not found.", zap.Strings("comparePath", condition.ComparePath), zap.Error(err))
+			return false
+		}
+		if val.IsNull() || compareVal.IsNull() || !val.Equals(compareVal).True() {
+			condLogger.Debug("AttributeValueEqualsPath not met.", zap.Any("actualValue", val.GoString()), zap.Any("compareValue", compareVal.GoString()))
+			return false
+		}
+	case types.VersionLessThan:
+		// Checks if the version at condition.Path is strictly older than the version at condition.ComparePath.
+		val, _, err := m.GetAttributeValueByPath(initialBlockBody, condition.Path)
+		if err != nil {
+			condLogger.Debug("VersionLessThan: Attribute not found.", zap.Error(err))
+			return false
+		}
+		compareVal, _, err := m.GetAttributeValueByPath(compareBody(initialBlockBody, resourceBody, condition), condition.ComparePath)
+		if err != nil {
+			condLogger.Debug("VersionLessThan: Attribute to compare against not found.", zap.Strings("comparePath", condition.ComparePath), zap.Error(err))
+			return false
+		}
+		if val.IsNull() || compareVal.IsNull() || val.Type() != cty.String || compareVal.Type() != cty.String {
+			condLogger.Debug("VersionLessThan: Both attributes must be non-null strings, condition not met.")
+			return false
+		}
+		cmp, ok := compareGKEVersions(val.AsString(), compareVal.AsString())
+		if !ok {
+			condLogger.Debug("VersionLessThan: Versions are not comparable, condition not met.", zap.String("version", val.AsString()), zap.String("compareVersion", compareVal.AsString()))
+			return false
+		}
+		if cmp >= 0 {
+			condLogger.Debug("VersionLessThan not met.", zap.String("version", val.AsString()), zap.String("compareVersion", compareVal.AsString()))
+			return false
+		}
+	default:
+		condLogger.Warn("Unknown condition type.")
+		return false
+	}
+	return true
+}
+
+// compareBody returns the body condition.ComparePath is relative to: resourceBody if condition.CompareInResource
+// is set (and resourceBody is known), initialBlockBody otherwise.
+func compareBody(initialBlockBody, resourceBody *hclwrite.Body, condition types.RuleCondition) *hclwrite.Body {
+	if condition.CompareInResource && resourceBody != nil {
+		return resourceBody
+	}
+	return initialBlockBody
+}
+
+// isPrimitiveType reports whether t is one of the primitive types parseExpectedValue can parse into.
+func isPrimitiveType(t cty.Type) bool {
+	return t == cty.String || t == cty.Bool || t == cty.Number
+}
+
+// parseExpectedValue parses the string form of an expected value into a cty.Value of targetType,
+// so it can be compared with an attribute's actual value. targetType must be a primitive type.
+func parseExpectedValue(expectedStr string, targetType cty.Type) (cty.Value, error) {
+	switch targetType {
+	case cty.String:
+		return cty.StringVal(expectedStr), nil
+	case cty.Bool:
+		boolVal, errConv := strconv.ParseBool(expectedStr)
+		if errConv != nil {
+			return cty.NilVal, fmt.Errorf("failed to parse ExpectedValue '%s' as bool: %w", expectedStr, errConv)
+		}
+		return cty.BoolVal(boolVal), nil
+	case cty.Number:
+		if intVal, errConv := strconv.ParseInt(expectedStr, 10, 64); errConv == nil {
+			return cty.NumberIntVal(intVal), nil
+		}
+		floatVal, errConv := strconv.ParseFloat(expectedStr, 64)
+		if errConv != nil {
+			return cty.NilVal, fmt.Errorf("failed to parse ExpectedValue '%s' as number: %w", expectedStr, errConv)
+		}
+		return cty.NumberFloatVal(floatVal), nil
+	default:
+		return cty.NilVal, fmt.Errorf("unsupported type %s for ExpectedValue '%s'", targetType.FriendlyName(), expectedStr)
+	}
+}
+
+// performAction executes a single RuleAction on a given hclwrite.Body and records the outcome in report.
+// This function is a helper for ApplyRules, used for both standard and nested block execution types.
+//
+// resourceBody: The body of the resource block being processed; see checkCondition.
+// pathPrefix: The path of initialBlockBody relative to the resource block (nil for standard execution),
+// used to build the paths of the recorded change entries.
+// report: One ChangeEntry per modification made (or a Warning, for ReportWarning actions) is appended to it.
+// Returns an error if the action failed.
+func (m *Modifier) performAction(initialBlockBody, resourceBody *hclwrite.Body, action types.RuleAction, actLogger *zap.Logger, ruleName string, resourceLabels []string, pathPrefix []string, report *types.ChangeReport) error {
+	if action.Type == types.ReportWarning {
+		actLogger.Warn("Rule reported a warning.", zap.String("message", action.Message))
+		report.Warnings = append(report.Warnings, types.Warning{
+			RuleName:       ruleName,
+			ResourceLabels: resourceLabels,
+			Message:        action.Message,
+		})
+		return nil
+	}
+
+	mods, err := m.executeAction(initialBlockBody, resourceBody, action, actLogger, ruleName, resourceLabels)
+	if mods == 0 {
+		return err
+	}
+
+	affectedPath := action.Path
+	if action.Type == types.RemoveAllBlocksOfType {
+		affectedPath = []string{action.BlockTypeToRemove}
+	}
+	fullPath := append(slices.Clone(pathPrefix), affectedPath...)
+
+	for i := 0; i < mods; i++ {
+		report.Changes = append(report.Changes, types.ChangeEntry{
+			RuleName:       ruleName,
+			ResourceLabels: resourceLabels,
+			ActionType:     action.Type,
+			Path:           fullPath,
+		})
+	}
+	return err
+}
+
+// executeAction executes a single RuleAction on a given hclwrite.Body.
+//
+// action: The RuleAction to perform. Paths within the action are relative to initialBlockBody.
+// resourceBody: The body of the resource block being processed, for BlockConditions; see checkCondition.
+// actLogger: A zap.Logger instance pre-configured with context for this action.
+// ruleName: The name of the rule whose action is being performed (for error reporting).
+// resourceLabels: The labels of the main resource block being processed (for error reporting).
+// Returns the number of modifications made and an error if the action failed.
+func (m *Modifier) executeAction(initialBlockBody, resourceBody *hclwrite.Body, action types.RuleAction, actLogger *zap.Logger, ruleName string, resourceLabels []string) (int, error) {
+	var errAction error
+	switch action.Type {
+	case types.RemoveAttribute:
+		mods, err := m.RemoveAttributeByPath(initialBlockBody, action.Path)
+		// errAction will be set here for the final error handler
+		errAction = err
+		if errAction == nil {
+			if mods > 0 {
+				actLogger.Info("Action RemoveAttribute successful.", zap.Int("attributesRemoved", mods))
+			} else {
+				actLogger.Debug("Action RemoveAttribute resulted in no actual changes (attribute likely not found or parent path missing).")
+			}
+			return mods, nil
+		}
+		// If errAction is not nil, it will be handled by the block at the end of executeAction
+		// We must return 0 modifications in case of an error from the helper.
+		return 0, errAction
+	case types.RemoveBlock:
+		mods, err := m.RemoveNestedBlockByPath(initialBlockBody, action.Path)
+		errAction = err
+		if errAction == nil {
+			if mods > 0 {
+				actLogger.Info("Action RemoveBlock successful.", zap.Int("blocksRemoved", mods))
 			} else {
 				actLogger.Debug("Action RemoveBlock resulted in no actual changes (block likely not found or parent path missing).")
 			}
 			return mods, nil
 		}
-		// If errAction is not nil, it will be handled by the block at the end of performAction
+		// If errAction is not nil, it will be handled by the block at the end of executeAction
 		// We must return 0 modifications in case of an error from the helper.
 		return 0, errAction
 	case types.RemoveAllBlocksOfType:
 		actLogger.Debug("Performing RemoveAllBlocksOfType", zap.String("blockTypeToRemove", action.BlockTypeToRemove))
 		blocksToRemove := []*hclwrite.Block{}
-		for _, b := range initialBlockBody.Blocks() {
-			if b.Type() == action.BlockTypeToRemove {
+		for _, b := range m.GetAllBlocksOfType(initialBlockBody, action.BlockTypeToRemove) {
+			if m.checkConditions(b.Body(), resourceBody, action.BlockConditions, actLogger) {
 				blocksToRemove = append(blocksToRemove, b)
 			}
 		}
@@ -620,7 +1382,9 @@ func (m *Modifier) performAction(initialBlockBody *hclwrite.Body, action types.R
 			return 0, nil
 		}
 		for _, b := range blocksToRemove {
-			initialBlockBody.RemoveBlock(b)
+			if err := removeNestedBlock(initialBlockBody, b); err != nil {
+				return 0, err
+			}
 			actLogger.Info("Removed block of type", zap.String("removedBlockType", b.Type()), zap.Strings("blockLabels", b.Labels()))
 		}
 		return len(blocksToRemove), nil
@@ -650,8 +1414,8 @@ func (m *Modifier) performAction(initialBlockBody *hclwrite.Body, action types.R
 		}
 
 		blocksToRemoveInNested := []*hclwrite.Block{}
-		for _, b := range parentBlockBody.Blocks() {
-			if b.Type() == nestedBlockTypeToRemove {
+		for _, b := range m.GetAllBlocksOfType(parentBlockBody, nestedBlockTypeToRemove) {
+			if m.checkConditions(b.Body(), resourceBody, action.BlockConditions, actLogger) {
 				blocksToRemoveInNested = append(blocksToRemoveInNested, b)
 			}
 		}
@@ -662,7 +1426,9 @@ func (m *Modifier) performAction(initialBlockBody *hclwrite.Body, action types.R
 		}
 
 		for _, b := range blocksToRemoveInNested {
-			parentBlockBody.RemoveBlock(b)
+			if err := removeNestedBlock(parentBlockBody, b); err != nil {
+				return 0, err
+			}
 			actLogger.Info("Removed nested block of type", zap.String("removedNestedBlockType", b.Type()), zap.Strings("parentPath", parentBlockPath))
 		}
 		return len(blocksToRemoveInNested), nil // Return number of blocks removed
@@ -674,7 +1440,7 @@ func (m *Modifier) performAction(initialBlockBody *hclwrite.Body, action types.R
 
 		if len(action.PathToSet) != 0 {
 			// Get value from another attribute (PathToSet) within the same scope (initialBlockBody)
-			valueByPath, _, err := m.GetAttributeValueByPath(initialBlockBody, action.PathToSet)
+			valueByPath, err := m.literalValueByPath(initialBlockBody, action.PathToSet)
 			if err != nil {
 				// Capture the error from PathToSet to be handled before calling SetAttributeValueByPath
 				errFromPathToSet = fmt.Errorf("error getting value from PathToSet '%v': %w", action.PathToSet, err)
@@ -701,7 +1467,15 @@ func (m *Modifier) performAction(initialBlockBody *hclwrite.Body, action types.R
 			return 0, errAction
 		}
 
-		mods, err := m.SetAttributeValueByPath(initialBlockBody, action.Path, valueToSet)
+		// With action.OnlyIfAbsent, an existing attribute is never overwritten. This check runs before the
+		// idempotency check in SetAttributeValueByPath, so an existing attribute is left alone even if its value differs.
+		var mods int
+		var err error
+		if action.OnlyIfAbsent {
+			mods, err = m.setAttributeValueIfAbsentByPath(initialBlockBody, action.Path, valueToSet)
+		} else {
+			mods, err = m.SetAttributeValueByPath(initialBlockBody, action.Path, valueToSet)
+		}
 		errAction = err
 		if errAction == nil {
 			if mods > 0 {
@@ -714,13 +1488,168 @@ func (m *Modifier) performAction(initialBlockBody *hclwrite.Body, action types.R
 		// If errAction is not nil (error from SetAttributeValueByPath), it will be handled by the block at the end.
 		// We must return 0 modifications in case of an error from the helper.
 		return 0, errAction
+	case types.CommentOutAttribute:
+		mods, err := m.CommentOutAttributeByPath(initialBlockBody, action.Path)
+		errAction = err
+		if errAction == nil {
+			if mods > 0 {
+				actLogger.Info("Action CommentOutAttribute successful.")
+			} else {
+				actLogger.Debug("Action CommentOutAttribute resulted in no actual changes (attribute likely not found or parent path missing).")
+			}
+			return mods, nil
+		}
+		return 0, errAction
+	case types.RenameAttribute:
+		if action.NewName == "" {
+			errAction = fmt.Errorf("RenameAttribute: action.NewName cannot be empty")
+			break
+		}
+		mods, err := m.RenameAttributeByPath(initialBlockBody, action.Path, action.NewName)
+		errAction = err
+		if errAction == nil {
+			if mods > 0 {
+				actLogger.Info("Action RenameAttribute successful.", zap.String("newName", action.NewName))
+			} else {
+				actLogger.Debug("Action RenameAttribute resulted in no actual changes (attribute likely not found or parent path missing).")
+			}
+			return mods, nil
+		}
+		return 0, errAction
+	case types.RemoveAttributeFromAllMatchingBlocks:
+		if len(action.Path) < 2 {
+			errAction = fmt.Errorf("RemoveAttributeFromAllMatchingBlocks: action.Path must contain a block path and an attribute name")
+			break
+		}
+		attributeName := action.Path[len(action.Path)-1]
+		removed := 0
+		for _, block := range m.getAllBlocksByPath(initialBlockBody, action.Path[:len(action.Path)-1]) {
+			mods, err := m.RemoveAttributeByPath(block.Body(), []string{attributeName})
+			if err != nil {
+				return removed, err
+			}
+			removed += mods
+		}
+		if removed > 0 {
+			actLogger.Info("Action RemoveAttributeFromAllMatchingBlocks successful.", zap.Int("attributesRemoved", removed))
+		} else {
+			actLogger.Debug("Action RemoveAttributeFromAllMatchingBlocks resulted in no actual changes (attribute not found in any matching block).")
+		}
+		return removed, nil
+	case types.RemoveEmptyBlocksMatchingPath:
+		if len(action.Path) == 0 {
+			errAction = fmt.Errorf("RemoveEmptyBlocksMatchingPath: action.Path cannot be empty")
+			break
+		}
+		blockType := action.Path[len(action.Path)-1]
+		parentBodies := []*hclwrite.Body{initialBlockBody}
+		if len(action.Path) > 1 {
+			parentBodies = nil
+			for _, parent := range m.getAllBlocksByPath(initialBlockBody, action.Path[:len(action.Path)-1]) {
+				parentBodies = append(parentBodies, parent.Body())
+			}
+		}
+		removed := 0
+		for _, parentBody := range parentBodies {
+			for _, block := range m.GetAllBlocksOfType(parentBody, blockType) {
+				if !isBlockEffectivelyEmpty(block.Body()) {
+					continue
+				}
+				if err := removeNestedBlock(parentBody, block); err != nil {
+					return removed, err
+				}
+				removed++
+			}
+		}
+		if removed > 0 {
+			actLogger.Info("Action RemoveEmptyBlocksMatchingPath successful.", zap.Int("blocksRemoved", removed))
+		} else {
+			actLogger.Debug("Action RemoveEmptyBlocksMatchingPath resulted in no actual changes (no empty blocks found).")
+		}
+		return removed, nil
+	case types.RemoveDuplicateNestedBlocks:
+		if len(action.Path) == 0 {
+			errAction = fmt.Errorf("RemoveDuplicateNestedBlocks: action.Path cannot be empty")
+			break
+		}
+		removed := 0
+		for _, parent := range m.getAllBlocksByPath(initialBlockBody, action.Path) {
+			var seenTypes []string
+			for _, block := range parent.Body().Blocks() {
+				if !slices.Contains(seenTypes, block.Type()) {
+					seenTypes = append(seenTypes, block.Type())
+				}
+			}
+			for _, blockType := range seenTypes {
+				removed += m.DeduplicateBlocks(parent.Body(), blockType)
+			}
+		}
+		if removed > 0 {
+			actLogger.Info("Action RemoveDuplicateNestedBlocks successful.", zap.Int("blocksRemoved", removed))
+		} else {
+			actLogger.Debug("Action RemoveDuplicateNestedBlocks resulted in no actual changes (no duplicate blocks found).")
+		}
+		return removed, nil
+	case types.RemoveMapEntry:
+		mods, err := m.RemoveMapEntriesByPath(initialBlockBody, action.Path, action.MapKey, action.MatchKeyPrefix)
+		errAction = err
+		if errAction == nil {
+			if mods > 0 {
+				actLogger.Info("Action RemoveMapEntry successful.", zap.Int("entriesRemoved", mods))
+			} else {
+				actLogger.Debug("Action RemoveMapEntry resulted in no actual changes (no matching entries or attribute not found).")
+			}
+			return mods, nil
+		}
+	case types.RemoveListElement:
+		value := cty.StringVal(action.ElementValue)
+		if len(action.ElementValuePath) > 0 {
+			pathValue, err := m.literalValueByPath(initialBlockBody, action.ElementValuePath)
+			if err != nil {
+				errAction = fmt.Errorf("error getting value from ElementValuePath '%v': %w", action.ElementValuePath, err)
+				break
+			}
+			value = pathValue
+		}
+		mods, err := m.RemoveListElementByPath(initialBlockBody, action.Path, value)
+		errAction = err
+		if errAction == nil {
+			if mods > 0 {
+				actLogger.Info("Action RemoveListElement successful.", zap.Int("elementsRemoved", mods))
+			} else {
+				actLogger.Debug("Action RemoveListElement resulted in no actual changes (no equal elements or attribute not found).")
+			}
+			return mods, nil
+		}
+	case types.RewriteAttributeWithRegex:
+		mods, err := m.RewriteAttributeByPath(initialBlockBody, action.Path, action.Pattern, action.Replacement)
+		errAction = err
+		if errAction == nil {
+			if mods > 0 {
+				actLogger.Info("Action RewriteAttributeWithRegex successful.")
+			} else {
+				actLogger.Debug("Action RewriteAttributeWithRegex resulted in no actual changes (no match, attribute not found or not a string).")
+			}
+			return mods, nil
+		}
+	case types.RemoveListElementsMatching:
+		mods, err := m.RemoveListElementsMatchingByPath(initialBlockBody, action.Path, action.Pattern)
+		errAction = err
+		if errAction == nil {
+			if mods > 0 {
+				actLogger.Info("Action RemoveListElementsMatching successful.", zap.Int("elementsRemoved", mods))
+			} else {
+				actLogger.Debug("Action RemoveListElementsMatching resulted in no actual changes (no matching elements or attribute not found).")
+			}
+			return mods, nil
+		}
 	default:
 		actLogger.Warn("Unknown action type.")
 		errAction = fmt.Errorf("unknown action type: %s", action.Type)
 	}
 
 	if errAction != nil {
-		actLogger.Error("Error performing action.", zap.Error(errAction))
+		actLogger.Debug("Error performing action.", zap.Error(errAction))
 		return 0, fmt.Errorf("rule '%s' action '%s' on resource '%s' (or its sub-block) failed: %w", ruleName, action.Type, resourceLabels, errAction)
 	}
 	// This path should ideally not be reached if all cases correctly return (mods, error)
@@ -731,9 +1660,10 @@ func (m *Modifier) performAction(initialBlockBody *hclwrite.Body, action types.R
 // The path can point to an attribute directly within initialBlockBody or within a deeply nested block.
 // initialBlockBody: The *hclwrite.Body to begin the operation from.
 // path: A slice of strings representing the path. The last element is the attribute name to set,
+// A "*" segment stands for every block of the preceding type; the attribute is then set in each of them.
 // valueToSet: The cty.Value to set for the attribute.
-// Returns the number of modifications (0 or 1) and an error if the path is invalid,
-// any intermediate block is not found, or if the initialBlockBody itself is nil.
+// Returns the number of modifications (0 or 1, or the total across all blocks matched by "*") and an error
+// if the path is invalid, any intermediate block is not found, or if the initialBlockBody itself is nil.
 func (m *Modifier) SetAttributeValueByPath(initialBlockBody *hclwrite.Body, path []string, valueToSet cty.Value) (int, error) {
 	if initialBlockBody == nil {
 		return 0, fmt.Errorf("SetAttributeValueByPath: initialBlockBody cannot be nil")
@@ -745,6 +1675,12 @@ func (m *Modifier) SetAttributeValueByPath(initialBlockBody *hclwrite.Body, path
 	logger := m.Logger.With(zap.Strings("path", path), zap.Any("valueToSet", valueToSet.GoString()))
 	logger.Debug("SetAttributeValueByPath: Attempting to set attribute value.")
 
+	if total, ok, err := m.applyToWildcardPath(initialBlockBody, path, func(body *hclwrite.Body, rest []string) (int, error) {
+		return m.SetAttributeValueByPath(body, rest, valueToSet)
+	}); ok {
+		return total, err
+	}
+
 	attributeName := path[len(path)-1]
 	blockPath := path[:len(path)-1]
 
@@ -767,7 +1703,7 @@ func (m *Modifier) SetAttributeValueByPath(initialBlockBody *hclwrite.Body, path
 	// Check if attribute already exists and if its value is the same
 	currentAttr := targetBody.GetAttribute(attributeName)
 	if currentAttr != nil {
-		currentValue, err := m.GetAttributeValue(currentAttr)
+		currentValue, err := m.evaluateAttribute(currentAttr, nil)
 		if err == nil {
 			// For cty values, direct .Equals() is correct.
 			if currentValue.Equals(valueToSet).True() {
@@ -780,7 +1716,718 @@ func (m *Modifier) SetAttributeValueByPath(initialBlockBody *hclwrite.Body, path
 		}
 	}
 
+	previousValue := zap.Skip()
+	if currentAttr != nil {
+		previousValue = m.attributeValueLogField("previousValue", currentAttr)
+	}
 	targetBody.SetAttributeValue(attributeName, valueToSet)
-	logger.Info("SetAttributeValueByPath: Successfully set/updated attribute.", zap.String("attributeName", attributeName))
+	logger.Info("SetAttributeValueByPath: Successfully set/updated attribute.", zap.String("attributeName", attributeName), previousValue)
 	return 1, nil // 1 attribute set or updated
 }
+
+// setAttributeValueIfAbsentByPath sets the attribute at path to valueToSet like SetAttributeValueByPath, except
+// that an attribute that already exists is left as it is. With "*" segments, each matched block is checked on its own.
+func (m *Modifier) setAttributeValueIfAbsentByPath(initialBlockBody *hclwrite.Body, path []string, valueToSet cty.Value) (int, error) {
+	if len(path) == 0 {
+		return 0, fmt.Errorf("setAttributeValueIfAbsentByPath: path cannot be empty")
+	}
+	if total, ok, err := m.applyToWildcardPath(initialBlockBody, path, func(body *hclwrite.Body, rest []string) (int, error) {
+		return m.setAttributeValueIfAbsentByPath(body, rest, valueToSet)
+	}); ok {
+		return total, err
+	}
+
+	targetBody, attributeName, err := m.findAttributeParentBody(initialBlockBody, path)
+	if err == nil && targetBody != nil && targetBody.GetAttribute(attributeName) != nil {
+		m.Logger.Debug("Attribute already exists and OnlyIfAbsent is set, not setting it.", zap.Strings("path", path))
+		return 0, nil
+	}
+	return m.SetAttributeValueByPath(initialBlockBody, path, valueToSet)
+}
+
+// CommentOutAttributeByPath removes the attribute at path and appends a `# key = value` comment holding its
+// original text to the end of the same block. Multi-line values are commented out line by line, and the comment
+// following the attribute, if any, is kept at the end of the last line.
+// The path can point to an attribute directly within initialBlockBody or within a deeply nested block, and "*"
+// segments are expanded as in RemoveAttributeByPath.
+// Returns the number of modifications (0 or 1, or the total across all blocks matched by "*") and an error if the
+// path is invalid.
+// If the attribute or any parent block does not exist, it's a no-op and returns (0, nil).
+func (m *Modifier) CommentOutAttributeByPath(initialBlockBody *hclwrite.Body, path []string) (int, error) {
+	if initialBlockBody == nil {
+		return 0, fmt.Errorf("CommentOutAttributeByPath: initialBlockBody cannot be nil")
+	}
+	if len(path) == 0 {
+		return 0, fmt.Errorf("CommentOutAttributeByPath: path cannot be empty")
+	}
+
+	logger := m.Logger.With(zap.Strings("path", path))
+	logger.Debug("CommentOutAttributeByPath: Attempting to comment out attribute.")
+
+	if total, ok, err := m.applyToWildcardPath(initialBlockBody, path, func(body *hclwrite.Body, rest []string) (int, error) {
+		return m.CommentOutAttributeByPath(body, rest)
+	}); ok {
+		return total, err
+	}
+
+	targetBody, attributeName, err := m.findAttributeParentBody(initialBlockBody, path)
+	if err != nil {
+		return 0, err
+	}
+	if targetBody == nil {
+		logger.Debug("CommentOutAttributeByPath: Parent block not found, attribute cannot be commented out (no-op).")
+		return 0, nil
+	}
+
+	attr := targetBody.GetAttribute(attributeName)
+	if attr == nil {
+		logger.Debug("CommentOutAttributeByPath: Attribute not found, no action needed.", zap.String("attributeName", attributeName))
+		return 0, nil
+	}
+
+	// Format the attribute on its own, so nested lines are indented relative to the attribute, not the file.
+	exprText := strings.TrimSpace(string(attr.Expr().BuildTokens(nil).Bytes()))
+	attributeText := strings.TrimSpace(string(hclwrite.Format([]byte(attributeName + " = " + exprText))))
+	if lineComment := attributeLineComment(attr); lineComment != "" {
+		attributeText += " " + lineComment
+	}
+	var commentTokens hclwrite.Tokens
+	for _, line := range strings.Split(attributeText, "\n") {
+		commentTokens = append(commentTokens, &hclwrite.Token{
+			Type:  hclsyntax.TokenComment,
+			Bytes: []byte("# " + strings.TrimRight(line, " \t") + "\n"),
+		})
+	}
+
+	targetBody.RemoveAttribute(attributeName)
+	targetBody.AppendUnstructuredTokens(commentTokens)
+	logger.Info("CommentOutAttributeByPath: Successfully commented out attribute.", zap.String("attributeName", attributeName))
+	return 1, nil
+}
+
+// attributeLineComment returns the text of the comment following attr on its last line, e.g. `# Set on import.`,
+// or "" if it has none.
+func attributeLineComment(attr *hclwrite.Attribute) string {
+	tokens := attr.BuildTokens(nil)
+	exprTokens := attr.Expr().BuildTokens(nil)
+	if len(exprTokens) == 0 {
+		return ""
+	}
+	lastExprToken := exprTokens[len(exprTokens)-1]
+	var comments []string
+	afterExpr := false
+	for _, token := range tokens {
+		if afterExpr && token.Type == hclsyntax.TokenComment {
+			comments = append(comments, strings.TrimSpace(string(token.Bytes)))
+		}
+		if token == lastExprToken {
+			afterExpr = true
+		}
+	}
+	return strings.Join(comments, " ")
+}
+
+// RenameAttributeByPath renames the attribute at path to newName, keeping its value expression tokens as they are.
+// The path can point to an attribute directly within initialBlockBody or within a deeply nested block, and "*"
+// segments are expanded as in RemoveAttributeByPath.
+// The attribute keeps its position in the block and its comments.
+// Returns the number of modifications (0 or 1, or the total across all blocks matched by "*") and an error if an
+// attribute named newName already exists.
+// If the attribute or any parent block does not exist, it's a no-op and returns (0, nil).
+func (m *Modifier) RenameAttributeByPath(initialBlockBody *hclwrite.Body, path []string, newName string) (int, error) {
+	if initialBlockBody == nil {
+		return 0, fmt.Errorf("RenameAttributeByPath: initialBlockBody cannot be nil")
+	}
+	if len(path) == 0 {
+		return 0, fmt.Errorf("RenameAttributeByPath: path cannot be empty")
+	}
+
+	logger := m.Logger.With(zap.Strings("path", path), zap.String("newName", newName))
+	logger.Debug("RenameAttributeByPath: Attempting to rename attribute.")
+
+	if total, ok, err := m.applyToWildcardPath(initialBlockBody, path, func(body *hclwrite.Body, rest []string) (int, error) {
+		return m.RenameAttributeByPath(body, rest, newName)
+	}); ok {
+		return total, err
+	}
+
+	targetBody, attributeName, err := m.findAttributeParentBody(initialBlockBody, path)
+	if err != nil {
+		return 0, err
+	}
+	if targetBody == nil {
+		logger.Debug("RenameAttributeByPath: Parent block not found, attribute cannot be renamed (no-op).")
+		return 0, nil
+	}
+
+	attr := targetBody.GetAttribute(attributeName)
+	if attr == nil {
+		logger.Debug("RenameAttributeByPath: Attribute to rename not found, no action needed.", zap.String("attributeName", attributeName))
+		return 0, nil
+	}
+	if attributeName == newName {
+		return 0, nil
+	}
+	if targetBody.GetAttribute(newName) != nil {
+		logger.Debug("RenameAttributeByPath: An attribute with the new name already exists.", zap.String("attributeName", attributeName))
+		return 0, fmt.Errorf("cannot rename attribute '%s' to '%s': attribute '%s' already exists", attributeName, newName, newName)
+	}
+
+	// hclwrite has no way to rename an attribute, but the tokens built from an attribute are the ones held by the
+	// body, so rewriting the name token renames it in place, keeping its position and comments.
+	for _, token := range attr.BuildTokens(nil) {
+		if token.Type == hclsyntax.TokenIdent {
+			token.Bytes = []byte(newName)
+			break
+		}
+	}
+	logger.Info("RenameAttributeByPath: Successfully renamed attribute.", zap.String("attributeName", attributeName))
+	return 1, nil
+}
+
+// RemoveListElementsMatchingByPath removes every element of a list attribute whose string form matches pattern.
+// The path can point to an attribute directly within initialBlockBody or within a deeply nested block, and "*"
+// segments are expanded as in RemoveAttributeByPath.
+// The remaining elements are written back in their original order; if no elements remain, the attribute is removed.
+// Returns the number of elements removed and an error if the pattern is invalid or the attribute is not a list.
+// If the attribute or any parent block does not exist, or its value isn't a literal (e.g. `var.tags`), it's a no-op
+// and returns (0, nil).
+func (m *Modifier) RemoveListElementsMatchingByPath(initialBlockBody *hclwrite.Body, path []string, pattern string) (int, error) {
+	if initialBlockBody == nil {
+		return 0, fmt.Errorf("RemoveListElementsMatchingByPath: initialBlockBody cannot be nil")
+	}
+	if len(path) == 0 {
+		return 0, fmt.Errorf("RemoveListElementsMatchingByPath: path cannot be empty")
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return 0, fmt.Errorf("RemoveListElementsMatchingByPath: invalid pattern '%s': %w", pattern, err)
+	}
+
+	logger := m.Logger.With(zap.Strings("path", path), zap.String("pattern", pattern))
+	logger.Debug("RemoveListElementsMatchingByPath: Attempting to remove matching list elements.")
+
+	if total, ok, err := m.applyToWildcardPath(initialBlockBody, path, func(body *hclwrite.Body, rest []string) (int, error) {
+		return m.RemoveListElementsMatchingByPath(body, rest, pattern)
+	}); ok {
+		return total, err
+	}
+
+	targetBody, attributeName, err := m.findAttributeParentBody(initialBlockBody, path)
+	if err != nil {
+		return 0, err
+	}
+	if targetBody == nil {
+		logger.Debug("RemoveListElementsMatchingByPath: Parent block not found, no action needed.")
+		return 0, nil
+	}
+
+	attr := targetBody.GetAttribute(attributeName)
+	if attr == nil {
+		logger.Debug("RemoveListElementsMatchingByPath: Attribute not found, no action needed.", zap.String("attributeName", attributeName))
+		return 0, nil
+	}
+
+	val, err := m.evaluateAttribute(attr, nil)
+	if err != nil {
+		logger.Debug("RemoveListElementsMatchingByPath: Attribute is not a literal, no action needed.", zap.String("attributeName", attributeName))
+		return 0, nil
+	}
+	if !val.Type().IsListType() && !val.Type().IsTupleType() && !val.Type().IsSetType() {
+		return 0, fmt.Errorf("attribute '%s' is not a list", attributeName)
+	}
+
+	var kept []cty.Value
+	removed := 0
+	for it := val.ElementIterator(); it.Next(); {
+		_, elem := it.Element()
+		// Elements are compared by their string form, so numbers and bools can be matched too.
+		// Elements that have no string form (e.g. nested objects) are always kept.
+		if elem.IsKnown() && !elem.IsNull() {
+			if strVal, errConv := convert.Convert(elem, cty.String); errConv == nil && re.MatchString(strVal.AsString()) {
+				removed++
+				continue
+			}
+		}
+		kept = append(kept, elem)
+	}
+
+	if removed == 0 {
+		logger.Debug("RemoveListElementsMatchingByPath: No elements matched the pattern, no change needed.", zap.String("attributeName", attributeName))
+		return 0, nil
+	}
+
+	if len(kept) == 0 {
+		targetBody.RemoveAttribute(attributeName)
+		logger.Info("RemoveListElementsMatchingByPath: All elements matched, removed attribute.", zap.String("attributeName", attributeName), zap.Int("elementsRemoved", removed))
+		return removed, nil
+	}
+
+	targetBody.SetAttributeValue(attributeName, cty.TupleVal(kept))
+	logger.Info("RemoveListElementsMatchingByPath: Successfully removed matching list elements.", zap.String("attributeName", attributeName), zap.Int("elementsRemoved", removed))
+	return removed, nil
+}
+
+// RemoveListElementByPath removes the elements of a list attribute equal to value, keeping the others in order.
+// Elements are compared by their string form, so `1` also removes "1". If no elements remain, the attribute is
+// removed as well. The path can point to an attribute directly within initialBlockBody or within a deeply nested block,
+// and "*" segments are expanded as in RemoveAttributeByPath.
+// Returns the number of elements removed and an error if the attribute is a literal that isn't a list.
+// If the attribute or any parent block does not exist, or its value isn't a literal (e.g. `var.zones`), it's a no-op
+// and returns (0, nil).
+func (m *Modifier) RemoveListElementByPath(initialBlockBody *hclwrite.Body, path []string, value cty.Value) (int, error) {
+	if initialBlockBody == nil {
+		return 0, fmt.Errorf("RemoveListElementByPath: initialBlockBody cannot be nil")
+	}
+	if len(path) == 0 {
+		return 0, fmt.Errorf("RemoveListElementByPath: path cannot be empty")
+	}
+	valueStr, err := convert.Convert(value, cty.String)
+	if err != nil || valueStr.IsNull() || !valueStr.IsKnown() {
+		return 0, fmt.Errorf("RemoveListElementByPath: value to remove must be a known primitive value")
+	}
+
+	logger := m.Logger.With(zap.Strings("path", path), zap.String("value", valueStr.AsString()))
+	logger.Debug("RemoveListElementByPath: Attempting to remove list elements.")
+
+	if total, ok, err := m.applyToWildcardPath(initialBlockBody, path, func(body *hclwrite.Body, rest []string) (int, error) {
+		return m.RemoveListElementByPath(body, rest, value)
+	}); ok {
+		return total, err
+	}
+
+	targetBody, attributeName, err := m.findAttributeParentBody(initialBlockBody, path)
+	if err != nil {
+		return 0, err
+	}
+	if targetBody == nil {
+		logger.Debug("RemoveListElementByPath: Parent block not found, no action needed.")
+		return 0, nil
+	}
+
+	attr := targetBody.GetAttribute(attributeName)
+	if attr == nil {
+		logger.Debug("RemoveListElementByPath: Attribute not found, no action needed.", zap.String("attributeName", attributeName))
+		return 0, nil
+	}
+
+	val, err := m.evaluateAttribute(attr, nil)
+	if err != nil {
+		logger.Debug("RemoveListElementByPath: Attribute is not a literal, no action needed.", zap.String("attributeName", attributeName))
+		return 0, nil
+	}
+	if !val.Type().IsListType() && !val.Type().IsTupleType() && !val.Type().IsSetType() {
+		return 0, fmt.Errorf("attribute '%s' is not a list", attributeName)
+	}
+
+	var kept []cty.Value
+	removed := 0
+	for it := val.ElementIterator(); it.Next(); {
+		_, elem := it.Element()
+		if elem.IsKnown() && !elem.IsNull() {
+			if elemStr, errConv := convert.Convert(elem, cty.String); errConv == nil && elemStr.AsString() == valueStr.AsString() {
+				removed++
+				continue
+			}
+		}
+		kept = append(kept, elem)
+	}
+
+	if removed == 0 {
+		logger.Debug("RemoveListElementByPath: No elements equal the value, no change needed.", zap.String("attributeName", attributeName))
+		return 0, nil
+	}
+
+	if len(kept) == 0 {
+		targetBody.RemoveAttribute(attributeName)
+		logger.Info("RemoveListElementByPath: All elements removed, removed attribute.", zap.String("attributeName", attributeName), zap.Int("elementsRemoved", removed))
+		return removed, nil
+	}
+
+	targetBody.SetAttributeValue(attributeName, cty.TupleVal(kept))
+	logger.Info("RemoveListElementByPath: Successfully removed list elements.", zap.String("attributeName", attributeName), zap.Int("elementsRemoved", removed))
+	return removed, nil
+}
+
+// RewriteAttributeByPath replaces the matches of pattern in the value of a string attribute with replacement, which
+// can refer to submatches as in regexp.Regexp.ReplaceAllString (e.g. `$1`). The path can point to an attribute
+// directly within initialBlockBody or within a deeply nested block, and "*" segments are expanded as in
+// RemoveAttributeByPath.
+// Returns the number of values rewritten and an error if the path or pattern is invalid.
+// If the attribute or any parent block does not exist, or its value isn't a literal string (e.g. a reference, which
+// isn't resolved even if m.ResolveReferences is set), it's a no-op and returns (0, nil).
+func (m *Modifier) RewriteAttributeByPath(initialBlockBody *hclwrite.Body, path []string, pattern, replacement string) (int, error) {
+	if initialBlockBody == nil {
+		return 0, fmt.Errorf("RewriteAttributeByPath: initialBlockBody cannot be nil")
+	}
+	if len(path) == 0 {
+		return 0, fmt.Errorf("RewriteAttributeByPath: path cannot be empty")
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return 0, fmt.Errorf("RewriteAttributeByPath: invalid pattern '%s': %w", pattern, err)
+	}
+
+	logger := m.Logger.With(zap.Strings("path", path), zap.String("pattern", pattern))
+	logger.Debug("RewriteAttributeByPath: Attempting to rewrite attribute value.")
+
+	if total, ok, err := m.applyToWildcardPath(initialBlockBody, path, func(body *hclwrite.Body, rest []string) (int, error) {
+		return m.RewriteAttributeByPath(body, rest, pattern, replacement)
+	}); ok {
+		return total, err
+	}
+
+	targetBody, attributeName, err := m.findAttributeParentBody(initialBlockBody, path)
+	if err != nil {
+		return 0, err
+	}
+	if targetBody == nil {
+		logger.Debug("RewriteAttributeByPath: Parent block not found, no action needed.")
+		return 0, nil
+	}
+
+	attr := targetBody.GetAttribute(attributeName)
+	if attr == nil {
+		logger.Debug("RewriteAttributeByPath: Attribute not found, no action needed.", zap.String("attributeName", attributeName))
+		return 0, nil
+	}
+
+	val, err := m.evaluateAttribute(attr, nil)
+	if err != nil || val.IsNull() || !val.IsKnown() || val.Type() != cty.String {
+		logger.Debug("RewriteAttributeByPath: Attribute is not a literal string, no action needed.", zap.String("attributeName", attributeName))
+		return 0, nil
+	}
+
+	rewritten := re.ReplaceAllString(val.AsString(), replacement)
+	if rewritten == val.AsString() {
+		logger.Debug("RewriteAttributeByPath: Value unchanged, no action needed.", zap.String("attributeName", attributeName))
+		return 0, nil
+	}
+
+	targetBody.SetAttributeValue(attributeName, cty.StringVal(rewritten))
+	logger.Info("RewriteAttributeByPath: Successfully rewrote attribute value.", zap.String("attributeName", attributeName), zap.String("newValue", rewritten))
+	return 1, nil
+}
+
+// RemoveMapEntriesByPath removes the entries of a map attribute whose key equals key or, with matchPrefix, starts
+// with key. The path can point to an attribute directly within initialBlockBody or within a deeply nested block,
+// and "*" segments are expanded as in RemoveAttributeByPath. The map's tokens are rewritten without the removed entries, so the remaining ones keep their formatting and
+// comments; if no entries remain, the attribute is left as an empty map.
+// Returns the number of entries removed and an error if the attribute is a literal that isn't a map.
+// If the attribute or any parent block does not exist, or its value isn't a literal (e.g. `local.labels` or
+// `merge(...)`), it's a no-op and returns (0, nil).
+func (m *Modifier) RemoveMapEntriesByPath(initialBlockBody *hclwrite.Body, path []string, key string, matchPrefix bool) (int, error) {
+	if initialBlockBody == nil {
+		return 0, fmt.Errorf("RemoveMapEntriesByPath: initialBlockBody cannot be nil")
+	}
+	if len(path) == 0 {
+		return 0, fmt.Errorf("RemoveMapEntriesByPath: path cannot be empty")
+	}
+
+	logger := m.Logger.With(zap.Strings("path", path), zap.String("key", key), zap.Bool("matchPrefix", matchPrefix))
+	logger.Debug("RemoveMapEntriesByPath: Attempting to remove matching map entries.")
+
+	if total, ok, err := m.applyToWildcardPath(initialBlockBody, path, func(body *hclwrite.Body, rest []string) (int, error) {
+		return m.RemoveMapEntriesByPath(body, rest, key, matchPrefix)
+	}); ok {
+		return total, err
+	}
+
+	targetBody, attributeName, err := m.findAttributeParentBody(initialBlockBody, path)
+	if err != nil {
+		return 0, err
+	}
+	if targetBody == nil {
+		logger.Debug("RemoveMapEntriesByPath: Parent block not found, no action needed.")
+		return 0, nil
+	}
+	attr := targetBody.GetAttribute(attributeName)
+	if attr == nil {
+		logger.Debug("RemoveMapEntriesByPath: Attribute not found, no action needed.", zap.String("attributeName", attributeName))
+		return 0, nil
+	}
+
+	src := attr.Expr().BuildTokens(nil).Bytes()
+	expr, diags := hclsyntax.ParseExpression(src, attributeName, hcl.InitialPos)
+	if diags.HasErrors() {
+		return 0, fmt.Errorf("could not parse value of attribute '%s': %w", attributeName, diags)
+	}
+	objectExpr, ok := expr.(*hclsyntax.ObjectConsExpr)
+	if !ok {
+		// Only a literal of another type is an error; references and function calls (e.g. `local.labels`,
+		// `merge(...)`) may well evaluate to a map, but there are no entries to remove from here.
+		if _, err := m.evaluateAttribute(attr, nil); err == nil {
+			return 0, fmt.Errorf("attribute '%s' is not a map", attributeName)
+		}
+		logger.Debug("RemoveMapEntriesByPath: Attribute is not a literal, no action needed.", zap.String("attributeName", attributeName))
+		return 0, nil
+	}
+
+	// Cut out each matching entry, from the start of its line (if nothing else precedes it there) through its
+	// trailing comma, comment and newline. Items are visited in reverse so earlier offsets stay valid.
+	removed := 0
+	for i := len(objectExpr.Items) - 1; i >= 0; i-- {
+		item := objectExpr.Items[i]
+		keyVal, keyDiags := item.KeyExpr.Value(nil)
+		if keyDiags.HasErrors() || !keyVal.IsKnown() || keyVal.IsNull() || keyVal.Type() != cty.String {
+			continue
+		}
+		entryKey := keyVal.AsString()
+		if entryKey != key && !(matchPrefix && strings.HasPrefix(entryKey, key)) {
+			continue
+		}
+		start, end := mapEntrySpan(src, item.KeyExpr.Range().Start.Byte, item.ValueExpr.Range().End.Byte)
+		src = append(src[:start:start], src[end:]...)
+		removed++
+	}
+
+	if removed == 0 {
+		logger.Debug("RemoveMapEntriesByPath: No entries matched, no change needed.", zap.String("attributeName", attributeName))
+		return 0, nil
+	}
+
+	// hclwrite has no expression lexer, so the rewritten map is parsed back as the value of a stand-in attribute.
+	rewritten, diags := hclwrite.ParseConfig(append([]byte("value = "), append(src, '\n')...), attributeName, hcl.InitialPos)
+	if diags.HasErrors() {
+		return 0, fmt.Errorf("could not rewrite value of attribute '%s': %w", attributeName, diags)
+	}
+	targetBody.SetAttributeRaw(attributeName, rewritten.Body().GetAttribute("value").Expr().BuildTokens(nil))
+	logger.Info("RemoveMapEntriesByPath: Successfully removed matching map entries.", zap.String("attributeName", attributeName), zap.Int("entriesRemoved", removed))
+	return removed, nil
+}
+
+// mapEntrySpan widens the byte range [start, end) of a map entry in src to include its trailing comma, a comment
+// on the same line and, if the entry is alone on its line, the whole line.
+func mapEntrySpan(src []byte, start, end int) (int, int) {
+	skipBlanks := func(i int) int {
+		for i < len(src) && (src[i] == ' ' || src[i] == '\t') {
+			i++
+		}
+		return i
+	}
+	end = skipBlanks(end)
+	if end < len(src) && src[end] == ',' {
+		end = skipBlanks(end + 1)
+	}
+	if end < len(src) && (src[end] == '#' || bytes.HasPrefix(src[end:], []byte("//"))) {
+		for end < len(src) && src[end] != '\n' {
+			end++
+		}
+	}
+
+	lineStart := start
+	for lineStart > 0 && (src[lineStart-1] == ' ' || src[lineStart-1] == '\t') {
+		lineStart--
+	}
+	if end < len(src) && src[end] == '\n' && (lineStart == 0 || src[lineStart-1] == '\n') {
+		return lineStart, end + 1
+	}
+	return start, end
+}
+
+// findAttributeParentBody resolves the body that holds the attribute addressed by path, starting from initialBlockBody.
+// It returns the resolved body and the attribute name. If an intermediate block is missing, the returned body is nil
+// and the error is nil, so callers can treat the operation as a no-op.
+func (m *Modifier) findAttributeParentBody(initialBlockBody *hclwrite.Body, path []string) (*hclwrite.Body, string, error) {
+	attributeName := path[len(path)-1]
+	blockPath := path[:len(path)-1]
+	if len(blockPath) == 0 {
+		return initialBlockBody, attributeName, nil
+	}
+
+	parentBlock, err := m.GetNestedBlock(initialBlockBody, blockPath)
+	if err != nil {
+		if errors.Is(err, types.ErrBlockNotFound) {
+			return nil, attributeName, nil
+		}
+		return nil, attributeName, fmt.Errorf("error finding parent block for attribute '%s': %w", attributeName, err)
+	}
+	if parentBlock.Body() == nil {
+		return nil, attributeName, fmt.Errorf("parent block '%s' has no body", blockPath)
+	}
+	return parentBlock.Body(), attributeName, nil
+}
+
+// applyToWildcardPath calls apply with the body of every block matched by the first "*" segment of path (see
+// expandWildcardPath) and the rest of the path, and returns the sum of the results. It stops at the first error.
+// ok is false, and apply isn't called, if path has no "*" segment.
+func (m *Modifier) applyToWildcardPath(initialBlockBody *hclwrite.Body, path []string, apply func(body *hclwrite.Body, rest []string) (int, error)) (total int, ok bool, err error) {
+	blocks, rest, ok, err := m.expandWildcardPath(initialBlockBody, path)
+	if !ok || err != nil {
+		return 0, ok, err
+	}
+	for _, block := range blocks {
+		n, err := apply(block.Body(), rest)
+		if err != nil {
+			return total, true, err
+		}
+		total += n
+	}
+	return total, true, nil
+}
+
+// wildcardPathSegment is the path segment that stands for every block of the preceding block type.
+const wildcardPathSegment = "*"
+
+// expandWildcardPath splits path at its first "*" segment. The part before it is resolved to the
+// parent block (as GetNestedBlock would), and every child block whose type is the segment right before
+// the "*" is returned, together with the rest of the path after the "*".
+// ok is false if path has no wildcard segment. A missing parent block yields no blocks and no error.
+func (m *Modifier) expandWildcardPath(initialBlockBody *hclwrite.Body, path []string) (blocks []*hclwrite.Block, rest []string, ok bool, err error) {
+	index := slices.Index(path, wildcardPathSegment)
+	if index < 0 {
+		return nil, nil, false, nil
+	}
+	if index == 0 || index == len(path)-1 {
+		return nil, nil, true, fmt.Errorf("wildcard segment in path '%s' must follow a block type and be followed by an attribute or block name", path)
+	}
+
+	parentBody := initialBlockBody
+	if index > 1 {
+		parentBlock, err := m.GetNestedBlock(initialBlockBody, path[:index-1])
+		if err != nil {
+			return nil, path[index+1:], true, nil
+		}
+		parentBody = parentBlock.Body()
+	}
+	return m.GetAllBlocksOfType(parentBody, path[index-1]), path[index+1:], true, nil
+}
+
+// getAllBlocksByPath returns every block reachable from initialBlockBody by following path, where each
+// path element is a block type. Unlike GetNestedBlock, which resolves a single block, repeated blocks of
+// the same type are all followed, so `["node_config", "guest_accelerator"]` returns every accelerator block.
+func (m *Modifier) getAllBlocksByPath(initialBlockBody *hclwrite.Body, path []string) []*hclwrite.Block {
+	bodies := []*hclwrite.Body{initialBlockBody}
+	var matched []*hclwrite.Block
+	for _, blockType := range path {
+		matched = nil
+		for _, body := range bodies {
+			matched = append(matched, m.GetAllBlocksOfType(body, blockType)...)
+		}
+		bodies = bodies[:0]
+		for _, block := range matched {
+			bodies = append(bodies, block.Body())
+		}
+	}
+	return matched
+}
+
+// removeDetachedLeadComments blanks the `/* ... */` comments written on their own lines directly above the attribute
+// or block of body whose tokens are nodeTokens, so they are removed along with it.
+// hclwrite already treats `#` and `//` comments directly above an attribute or block as part of it, but leaves block
+// comments behind, where they would end up describing the next attribute. The comment tokens are blanked in place,
+// since hclwrite has no way to remove the unstructured tokens between the items of a body.
+func removeDetachedLeadComments(body *hclwrite.Body, nodeTokens hclwrite.Tokens) {
+	if len(nodeTokens) == 0 {
+		return
+	}
+	bodyTokens := body.BuildTokens(nil)
+	start := slices.Index(bodyTokens, nodeTokens[0])
+	// Each match is a comment followed by its newline, itself preceded by the start of the body or a newline.
+	for start >= 2 {
+		comment, newline := bodyTokens[start-2], bodyTokens[start-1]
+		if newline.Type != hclsyntax.TokenNewline || comment.Type != hclsyntax.TokenComment || !bytes.HasPrefix(comment.Bytes, []byte("/*")) {
+			return
+		}
+		if start > 2 && bodyTokens[start-3].Type != hclsyntax.TokenNewline {
+			return
+		}
+		// Empty newline tokens print nothing, whereas empty tokens of other types still get spaces around them.
+		for _, tok := range []*hclwrite.Token{comment, newline} {
+			tok.Type = hclsyntax.TokenNewline
+			tok.Bytes = nil
+			tok.SpacesBefore = 0
+		}
+		start -= 2
+	}
+}
+
+// isBlockEffectivelyEmpty reports whether body has no attributes and contains only nested blocks
+// that are themselves effectively empty.
+func isBlockEffectivelyEmpty(body *hclwrite.Body) bool {
+	if len(body.Attributes()) > 0 {
+		return false
+	}
+	for _, block := range body.Blocks() {
+		if !isBlockEffectivelyEmpty(block.Body()) {
+			return false
+		}
+	}
+	return true
+}
+
+// blockMatchesDefaults reports whether every attribute of body is listed in defaults and equals its default value,
+// and body has no non-empty nested blocks. Attributes of defaults that body doesn't set are fine.
+func (m *Modifier) blockMatchesDefaults(body *hclwrite.Body, defaults map[string]string, logger *zap.Logger) bool {
+	for name, attr := range body.Attributes() {
+		expected, ok := defaults[name]
+		if !ok {
+			logger.Debug("BlockMatchesDefaults not met (attribute has no default).", zap.String("attributeName", name))
+			return false
+		}
+		val, err := m.GetAttributeValue(attr)
+		if err != nil || !isPrimitiveType(val.Type()) {
+			logger.Debug("BlockMatchesDefaults not met (attribute is not a literal primitive value).", zap.String("attributeName", name), zap.Error(err))
+			return false
+		}
+		expectedCtyValue, err := parseExpectedValue(expected, val.Type())
+		if err != nil || !val.Equals(expectedCtyValue).True() {
+			logger.Debug("BlockMatchesDefaults not met (attribute doesn't have its default value).", zap.String("attributeName", name), zap.String("default", expected), zap.Error(err))
+			return false
+		}
+	}
+	for _, block := range body.Blocks() {
+		if !isBlockEffectivelyEmpty(block.Body()) {
+			logger.Debug("BlockMatchesDefaults not met (block has a non-empty nested block).", zap.String("nestedBlockType", block.Type()))
+			return false
+		}
+	}
+	return true
+}
+
+// parseGKEVersion splits a GKE version such as "1.27.3-gke.1286000" into its numeric components
+// ([1, 27, 3, 1286000]). Partial versions like "1.27" are allowed and yield fewer components.
+// It returns false for anything that isn't a numeric version (e.g. "latest").
+func parseGKEVersion(version string) ([]int, bool) {
+	version = strings.TrimSpace(version)
+	if version == "" {
+		return nil, false
+	}
+	main, build, hasBuild := strings.Cut(version, "-gke.")
+	parts := strings.Split(main, ".")
+	if hasBuild {
+		parts = append(parts, build)
+	}
+	components := make([]int, 0, len(parts))
+	for _, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return nil, false
+		}
+		components = append(components, n)
+	}
+	return components, true
+}
+
+// compareGKEVersions compares two GKE versions and returns -1, 0 or 1 as a is older than, equal to or newer than b.
+// Only the components present in both versions are compared, so a partial version like "1.27" is considered
+// equal to any "1.27.x" version. The second return value is false if either version can't be parsed.
+func compareGKEVersions(a, b string) (int, bool) {
+	aComponents, ok := parseGKEVersion(a)
+	if !ok {
+		return 0, false
+	}
+	bComponents, ok := parseGKEVersion(b)
+	if !ok {
+		return 0, false
+	}
+	for i := 0; i < len(aComponents) && i < len(bComponents); i++ {
+		if aComponents[i] < bComponents[i] {
+			return -1, true
+		}
+		if aComponents[i] > bComponents[i] {
+			return 1, true
+		}
+	}
+	return 0, true
+}
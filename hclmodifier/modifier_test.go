@@ -0,0 +1,1606 @@
+package hclmodifier
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/stretchr/testify/assert"
+	"github.com/zclconf/go-cty/cty"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+
+	"github.com/kotatut/cluster_import_cleaner/hclmodifier/rules"
+	"github.com/kotatut/cluster_import_cleaner/hclmodifier/types"
+)
+
+// newTestModifier parses hclContent into a Modifier with a no-op logger.
+func newTestModifier(t *testing.T, hclContent string) *Modifier {
+	t.Helper()
+	hclFile, diags := hclwrite.ParseConfig([]byte(hclContent), "test.hcl", hcl.InitialPos)
+	if diags.HasErrors() {
+		t.Fatalf("Failed to parse HCL content: %v", diags)
+	}
+	return &Modifier{file: hclFile, Logger: zap.NewNop()}
+}
+
+func TestRemoveEmptyResources(t *testing.T) {
+	fixture := "testdata/TestRemoveEmptyResources_Mixed.tf"
+
+	t.Run("Default meaningful set removes only the empty cluster", func(t *testing.T) {
+		modifier, err := NewFromFile(fixture, zap.NewNop())
+		if err != nil {
+			t.Fatalf("NewFromFile() error = %v", err)
+		}
+		removed := modifier.RemoveEmptyResources("google_container_cluster", []string{"name", "location"})
+		assert.Equal(t, 1, removed)
+		assertMatchesGolden(t, modifier, fixture+".golden")
+	})
+
+	t.Run("Extended ignore list also removes clusters with only ignored attributes", func(t *testing.T) {
+		modifier, err := NewFromFile(fixture, zap.NewNop())
+		if err != nil {
+			t.Fatalf("NewFromFile() error = %v", err)
+		}
+		removed := modifier.RemoveEmptyResources("google_container_cluster", []string{"name", "location", "min_master_version"})
+		assert.Equal(t, 2, removed)
+		assert.Equal(t, []string{"with_block"}, modifier.ListResources("google_container_cluster"), "Cluster with a nested block should be kept")
+		assert.Equal(t, []string{"vpc"}, modifier.ListResources("google_compute_network"), "Other resource types should be kept")
+	})
+}
+
+func TestClean(t *testing.T) {
+	content := []byte(`resource "google_container_cluster" "primary" {
+  name               = "primary"
+  enable_autopilot   = false
+  enable_legacy_abac = false
+}
+`)
+	cleaned, modifications, errs := Clean(content, []types.Rule{rules.RuleHandleAutopilotFalse, rules.RuleHandleLegacyAbacFalse}, zap.NewNop())
+	assert.Empty(t, errs)
+	assert.Equal(t, 2, modifications)
+	assert.Equal(t, `resource "google_container_cluster" "primary" {
+  name = "primary"
+}
+`, string(cleaned))
+
+	cleaned, modifications, errs = Clean([]byte(`resource "google_container_cluster" {`), nil, zap.NewNop())
+	assert.Nil(t, cleaned)
+	assert.Equal(t, 0, modifications)
+	assert.Len(t, errs, 1, "Unparsable content is reported as a single error")
+}
+
+func TestListResources(t *testing.T) {
+	modifier := newTestModifier(t, `resource "google_container_cluster" "primary" {
+  name = "primary"
+}
+
+resource "google_compute_instance" "bastion" {
+  name = "bastion"
+}
+
+data "google_container_cluster" "existing" {
+  name = "existing"
+}
+
+resource "google_container_cluster" "secondary" {
+  name = "secondary"
+}`)
+	assert.Equal(t, []string{"primary", "secondary"}, modifier.ListResources("google_container_cluster"))
+	assert.Empty(t, modifier.ListResources("google_container_node_pool"))
+}
+
+func TestApplyRulesWithReport(t *testing.T) {
+	modifier := newTestModifier(t, `resource "google_container_cluster" "primary" {
+  name              = "primary"
+  id                = "projects/p/locations/l/clusters/primary"
+  self_link         = "https://container.googleapis.com/v1/projects/p/locations/l/clusters/primary"
+  node_pool {
+    name               = "pool-one"
+    initial_node_count = 1
+  }
+  node_pool {
+    name = "pool-two"
+  }
+}
+
+resource "google_container_cluster" "secondary" {
+  name     = "secondary"
+  endpoint = "1.2.3.4"
+}`)
+
+	rulesToApply := append([]types.Rule{rules.InitialNodeCountRuleDefinition}, rules.TopLevelComputedAttributesRules...)
+	report, errs := modifier.ApplyRulesWithReport(rulesToApply)
+	assert.Empty(t, errs)
+
+	expected := []types.ChangeEntry{
+		{
+			RuleName:       rules.InitialNodeCountRuleDefinition.Name,
+			ResourceLabels: []string{"google_container_cluster", "primary"},
+			ActionType:     types.RemoveAttribute,
+			Path:           []string{"node_pool", "initial_node_count"},
+		},
+		{
+			RuleName:       "Remove attribute '[endpoint]' from 'google_container_cluster'",
+			ResourceLabels: []string{"google_container_cluster", "secondary"},
+			ActionType:     types.RemoveAttribute,
+			Path:           []string{"endpoint"},
+		},
+		{
+			RuleName:       "Remove attribute '[id]' from 'google_container_cluster'",
+			ResourceLabels: []string{"google_container_cluster", "primary"},
+			ActionType:     types.RemoveAttribute,
+			Path:           []string{"id"},
+		},
+		{
+			RuleName:       "Remove attribute '[self_link]' from 'google_container_cluster'",
+			ResourceLabels: []string{"google_container_cluster", "primary"},
+			ActionType:     types.RemoveAttribute,
+			Path:           []string{"self_link"},
+		},
+	}
+	assert.Equal(t, expected, report.Changes)
+
+	// A second pass changes nothing, and ApplyRules reports the same count as the report length.
+	modifications, errs := modifier.ApplyRules(rulesToApply)
+	assert.Empty(t, errs)
+	assert.Equal(t, 0, modifications)
+}
+
+func TestApplyRulesWithReportRuleStats(t *testing.T) {
+	modifier := newTestModifier(t, `resource "google_container_cluster" "primary" {
+  name                = "primary"
+  id                  = "projects/p/locations/l/clusters/primary"
+  deletion_protection = true
+}
+
+resource "google_container_cluster" "secondary" {
+  name                = "secondary"
+  deletion_protection = false
+}
+
+resource "google_container_node_pool" "pool" {
+  name = "pool"
+}`)
+
+	setDeletionProtectionRule := types.Rule{
+		Name:               "Set deletion_protection to false",
+		TargetResourceType: "google_container_cluster",
+		Conditions: []types.RuleCondition{
+			{Type: types.AttributeExists, Path: []string{"deletion_protection"}},
+		},
+		Actions: []types.RuleAction{
+			{Type: types.SetAttributeValue, Path: []string{"deletion_protection"}, ValueToSet: "false"},
+		},
+	}
+	removeIDRule := rules.TopLevelComputedAttributesRules[2]
+	report, errs := modifier.ApplyRulesWithReport([]types.Rule{setDeletionProtectionRule, removeIDRule, rules.InitialNodeCountRuleDefinition})
+	assert.Empty(t, errs)
+
+	expected := []types.RuleStats{
+		// Both clusters meet the condition, but the secondary one already has the value to set.
+		{RuleName: setDeletionProtectionRule.Name, Matched: 2, ConditionsMet: 2, Acted: 1},
+		{RuleName: removeIDRule.Name, Matched: 2, ConditionsMet: 1, Acted: 1},
+		{RuleName: rules.InitialNodeCountRuleDefinition.Name, Matched: 2, ConditionsMet: 0, Acted: 0},
+	}
+	assert.Equal(t, expected, report.RuleStats)
+}
+
+func TestConditionAttributeValueIn(t *testing.T) {
+	const hclContent = `resource "google_container_cluster" "primary" {
+  cluster_telemetry {
+    type = "%s"
+  }
+}`
+	newRule := func(expectedValues []string) types.Rule {
+		return types.Rule{
+			Name:               "Remove cluster_telemetry when enabled",
+			TargetResourceType: "google_container_cluster",
+			Conditions: []types.RuleCondition{
+				{
+					Type:           types.AttributeValueIn,
+					Path:           []string{"cluster_telemetry", "type"},
+					ExpectedValues: expectedValues,
+				},
+			},
+			Actions: []types.RuleAction{
+				{
+					Type: types.RemoveBlock,
+					Path: []string{"cluster_telemetry"},
+				},
+			},
+		}
+	}
+
+	tests := []struct {
+		name                  string
+		telemetryType         string
+		expectedValues        []string
+		expectedModifications int
+		expectWarning         bool
+	}{
+		{
+			name:                  "Value in set",
+			telemetryType:         "SYSTEM_ONLY",
+			expectedValues:        []string{"ENABLED", "SYSTEM_ONLY"},
+			expectedModifications: 1,
+		},
+		{
+			name:                  "Value not in set",
+			telemetryType:         "DISABLED",
+			expectedValues:        []string{"ENABLED", "SYSTEM_ONLY"},
+			expectedModifications: 0,
+		},
+		{
+			name:                  "Empty set never matches",
+			telemetryType:         "ENABLED",
+			expectedValues:        nil,
+			expectedModifications: 0,
+			expectWarning:         true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			core, logs := observer.New(zap.WarnLevel)
+			modifier := newTestModifier(t, fmt.Sprintf(hclContent, tc.telemetryType))
+			modifier.Logger = zap.New(core)
+
+			modifications, errs := modifier.ApplyRules([]types.Rule{newRule(tc.expectedValues)})
+			assert.Empty(t, errs)
+			assert.Equal(t, tc.expectedModifications, modifications)
+			assert.Equal(t, tc.expectWarning, logs.FilterMessageSnippet("ExpectedValues is empty").Len() > 0)
+		})
+	}
+}
+
+func TestConditionBlockMatchesDefaults(t *testing.T) {
+	rule := types.Rule{
+		Name:               "Remove default upgrade_settings",
+		TargetResourceType: "google_container_cluster",
+		Conditions: []types.RuleCondition{
+			{
+				Type:     types.BlockMatchesDefaults,
+				Path:     []string{"upgrade_settings"},
+				Defaults: map[string]string{"max_surge": "1", "max_unavailable": "0", "strategy": "SURGE"},
+			},
+		},
+		Actions: []types.RuleAction{
+			{Type: types.RemoveBlock, Path: []string{"upgrade_settings"}},
+		},
+	}
+
+	tests := []struct {
+		name                  string
+		upgradeSettings       string
+		expectedModifications int
+	}{
+		{
+			name:                  "Exact defaults",
+			upgradeSettings:       "max_surge = 1\n    max_unavailable = 0\n    strategy = \"SURGE\"",
+			expectedModifications: 1,
+		},
+		{
+			name:                  "Defaults left unset",
+			upgradeSettings:       "strategy = \"SURGE\"",
+			expectedModifications: 1,
+		},
+		{
+			name:                  "Extra attribute",
+			upgradeSettings:       "max_surge = 1\n    max_unavailable = 0\n    node_pool_soak_duration = \"60s\"",
+			expectedModifications: 0,
+		},
+		{
+			name:                  "Non-default value",
+			upgradeSettings:       "max_surge = 2\n    max_unavailable = 0",
+			expectedModifications: 0,
+		},
+		{
+			name:                  "Non-empty nested block",
+			upgradeSettings:       "max_surge = 1\n    blue_green_settings {\n      node_pool_soak_duration = \"60s\"\n    }",
+			expectedModifications: 0,
+		},
+		{
+			name:                  "Non-literal value",
+			upgradeSettings:       "max_surge = var.max_surge",
+			expectedModifications: 0,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			modifier := newTestModifier(t, fmt.Sprintf(`resource "google_container_cluster" "primary" {
+  upgrade_settings {
+    %s
+  }
+}`, tc.upgradeSettings))
+			modifications, errs := modifier.ApplyRules([]types.Rule{rule})
+			assert.Empty(t, errs)
+			assert.Equal(t, tc.expectedModifications, modifications)
+		})
+	}
+
+	t.Run("Missing block", func(t *testing.T) {
+		modifier := newTestModifier(t, `resource "google_container_cluster" "primary" {
+  name = "primary"
+}`)
+		modifications, errs := modifier.ApplyRules([]types.Rule{rule})
+		assert.Empty(t, errs)
+		assert.Equal(t, 0, modifications)
+	})
+}
+
+func TestApplyRulesSkipsDuplicateRules(t *testing.T) {
+	modifier := newTestModifier(t, `resource "google_container_cluster" "primary" {
+  name              = "primary"
+  label_fingerprint = "a1b2c3"
+  self_link         = "https://container.googleapis.com/v1/projects/p/locations/l/clusters/primary"
+}`)
+	core, logs := observer.New(zap.WarnLevel)
+	modifier.Logger = zap.New(core)
+
+	removeLabelFingerprint := types.Rule{
+		Name:               "Duplicated rule",
+		TargetResourceType: "google_container_cluster",
+		Actions:            []types.RuleAction{{Type: types.RemoveAttribute, Path: []string{"label_fingerprint"}}},
+	}
+	// Same name as removeLabelFingerprint, so it must be skipped even though its actions differ.
+	removeSelfLink := types.Rule{
+		Name:               "Duplicated rule",
+		TargetResourceType: "google_container_cluster",
+		Actions:            []types.RuleAction{{Type: types.RemoveAttribute, Path: []string{"self_link"}}},
+	}
+
+	report, errs := modifier.ApplyRulesWithReport([]types.Rule{removeLabelFingerprint, removeLabelFingerprint, removeSelfLink})
+	assert.Empty(t, errs)
+	assert.Equal(t, []types.ChangeEntry{
+		{
+			RuleName:       "Duplicated rule",
+			ResourceLabels: []string{"google_container_cluster", "primary"},
+			ActionType:     types.RemoveAttribute,
+			Path:           []string{"label_fingerprint"},
+		},
+	}, report.Changes)
+	assert.NotNil(t, modifier.File().Body().Blocks()[0].Body().GetAttribute("self_link"))
+	assert.Equal(t, 2, logs.FilterMessageSnippet("Skipping duplicate rule").Len())
+}
+
+func TestWildcardPathSegment(t *testing.T) {
+	const hclContent = `resource "google_container_cluster" "primary" {
+  node_pool {
+    name               = "pool-1"
+    initial_node_count = 1
+  }
+  node_pool {
+    name = "pool-2"
+  }
+  node_pool {
+    name               = "pool-3"
+    initial_node_count = 3
+  }
+  node_pool {
+    name = "pool-4"
+  }
+  node_pool {
+    name               = "pool-5"
+    initial_node_count = 5
+  }
+}`
+	wildcardPath := []string{"node_pool", "*", "initial_node_count"}
+
+	t.Run("RemoveAttribute action fans out over all node pools", func(t *testing.T) {
+		modifier := newTestModifier(t, hclContent)
+		modifications, errs := modifier.ApplyRules([]types.Rule{
+			{
+				Name:               "Remove initial_node_count from all pools",
+				TargetResourceType: "google_container_cluster",
+				Actions:            []types.RuleAction{{Type: types.RemoveAttribute, Path: wildcardPath}},
+			},
+		})
+		assert.Empty(t, errs)
+		assert.Equal(t, 3, modifications)
+		for _, pool := range modifier.File().Body().Blocks()[0].Body().Blocks() {
+			assert.Nil(t, pool.Body().GetAttribute("initial_node_count"))
+		}
+	})
+
+	t.Run("GetAttributeValueByPath returns the first match", func(t *testing.T) {
+		modifier := newTestModifier(t, hclContent)
+		body := modifier.File().Body().Blocks()[0].Body()
+		val, _, err := modifier.GetAttributeValueByPath(body, wildcardPath)
+		assert.NoError(t, err)
+		assert.True(t, val.Equals(cty.NumberIntVal(1)).True())
+
+		_, _, err = modifier.GetAttributeValueByPath(body, []string{"node_pool", "*", "node_count"})
+		assert.Error(t, err)
+	})
+
+	t.Run("SetAttributeValueByPath sets the attribute in every block", func(t *testing.T) {
+		modifier := newTestModifier(t, hclContent)
+		body := modifier.File().Body().Blocks()[0].Body()
+		modifications, err := modifier.SetAttributeValueByPath(body, wildcardPath, cty.NumberIntVal(3))
+		assert.NoError(t, err)
+		assert.Equal(t, 4, modifications) // pool-3 already has the value
+	})
+
+	t.Run("OnlyIfAbsent keeps the attribute in the blocks that have it", func(t *testing.T) {
+		modifier := newTestModifier(t, hclContent)
+		modifications, errs := modifier.ApplyRules([]types.Rule{
+			{
+				Name:               "Default initial_node_count in all pools",
+				TargetResourceType: "google_container_cluster",
+				Actions:            []types.RuleAction{{Type: types.SetAttributeValue, Path: wildcardPath, ValueToSet: "2", OnlyIfAbsent: true}},
+			},
+		})
+		assert.Empty(t, errs)
+		assert.Equal(t, 2, modifications)
+		for i, pool := range modifier.File().Body().Blocks()[0].Body().Blocks() {
+			expected := cty.NumberIntVal([]int64{1, 2, 3, 2, 5}[i])
+			val, err := modifier.GetAttributeValue(pool.Body().GetAttribute("initial_node_count"))
+			assert.NoError(t, err)
+			assert.True(t, val.Equals(expected).True(), "pool %d: got %#v", i+1, val)
+		}
+	})
+
+	t.Run("Attribute helpers fan out over all node pools", func(t *testing.T) {
+		modifier := newTestModifier(t, hclContent)
+		body := modifier.File().Body().Blocks()[0].Body()
+
+		renamed, err := modifier.RenameAttributeByPath(body, wildcardPath, "node_count")
+		assert.NoError(t, err)
+		assert.Equal(t, 3, renamed)
+
+		commentedOut, err := modifier.CommentOutAttributeByPath(body, []string{"node_pool", "*", "node_count"})
+		assert.NoError(t, err)
+		assert.Equal(t, 3, commentedOut)
+		for _, pool := range body.Blocks() {
+			assert.Nil(t, pool.Body().GetAttribute("node_count"))
+		}
+	})
+
+	t.Run("Wildcard without a preceding block type is an error", func(t *testing.T) {
+		modifier := newTestModifier(t, hclContent)
+		body := modifier.File().Body().Blocks()[0].Body()
+		_, err := modifier.RemoveAttributeByPath(body, []string{"*", "initial_node_count"})
+		assert.Error(t, err)
+	})
+}
+
+func TestVerifyIdempotent(t *testing.T) {
+	const hclContent = `resource "google_container_cluster" "primary" {
+  name            = "primary"
+  logging_service = "logging.googleapis.com/kubernetes"
+  release_channel {
+    channel = "REGULAR"
+  }
+}`
+
+	t.Run("Idempotent rules", func(t *testing.T) {
+		modifier := newTestModifier(t, hclContent)
+		idempotentRules := append([]types.Rule{}, rules.TopLevelComputedAttributesRules...)
+		_, errs := modifier.ApplyRules(idempotentRules)
+		assert.Empty(t, errs)
+
+		before := string(modifier.File().Bytes())
+		idempotent, modifications, err := modifier.VerifyIdempotent(idempotentRules)
+		assert.NoError(t, err)
+		assert.True(t, idempotent)
+		assert.Equal(t, 0, modifications)
+		assert.Equal(t, before, string(modifier.File().Bytes()))
+	})
+
+	t.Run("Oscillating rules are detected", func(t *testing.T) {
+		modifier := newTestModifier(t, hclContent)
+		// Each rule undoes the other, so every pass changes the channel twice.
+		setChannel := func(from, to string) types.Rule {
+			return types.Rule{
+				Name:               fmt.Sprintf("Switch channel from %s to %s", from, to),
+				TargetResourceType: "google_container_cluster",
+				Conditions: []types.RuleCondition{
+					{Type: types.AttributeValueEquals, Path: []string{"release_channel", "channel"}, ExpectedValue: from},
+				},
+				Actions: []types.RuleAction{
+					{Type: types.SetAttributeValue, Path: []string{"release_channel", "channel"}, ValueToSet: to},
+				},
+			}
+		}
+		oscillatingRules := []types.Rule{setChannel("REGULAR", "STABLE"), setChannel("STABLE", "REGULAR")}
+		_, errs := modifier.ApplyRules(oscillatingRules)
+		assert.Empty(t, errs)
+
+		before := string(modifier.File().Bytes())
+		idempotent, modifications, err := modifier.VerifyIdempotent(oscillatingRules)
+		assert.NoError(t, err)
+		assert.False(t, idempotent)
+		assert.Equal(t, 2, modifications)
+		assert.Equal(t, before, string(modifier.File().Bytes()), "VerifyIdempotent must not modify the original file")
+	})
+}
+
+func TestWriteToFilePreservesMode(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cluster.tf")
+	content := `resource "google_container_cluster" "primary" {
+  id = "projects/p/locations/us-central1/clusters/primary"
+}
+`
+	assert.NoError(t, os.WriteFile(path, []byte(content), 0600))
+	assert.NoError(t, os.Chmod(path, 0600))
+
+	modifier, err := NewFromFile(path, zap.NewNop())
+	assert.NoError(t, err)
+	modifications, errs := modifier.ApplyRules(rules.TopLevelComputedAttributesRules)
+	assert.Empty(t, errs)
+	assert.Equal(t, 1, modifications)
+
+	assert.NoError(t, modifier.WriteToFile(path))
+	info, err := os.Stat(path)
+	assert.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm(), "An existing file must keep its mode")
+
+	assert.NoError(t, modifier.WriteToFile(path, WithFileMode(0640)))
+	info, err = os.Stat(path)
+	assert.NoError(t, err)
+	assert.Equal(t, os.FileMode(0640), info.Mode().Perm(), "WithFileMode must override the existing mode")
+
+	newPath := filepath.Join(filepath.Dir(path), "new.tf")
+	assert.NoError(t, modifier.WriteToFile(newPath, WithFileMode(0600)))
+	info, err = os.Stat(newPath)
+	assert.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+}
+
+func TestClone(t *testing.T) {
+	modifier := newTestModifier(t, `resource "google_container_cluster" "primary" {
+  name = "primary"
+  node_pool {
+    name = "pool-1"
+  }
+}`)
+	original := string(modifier.File().Bytes())
+
+	clone := modifier.Clone()
+	if !assert.NotNil(t, clone) {
+		return
+	}
+	assert.Equal(t, original, string(clone.File().Bytes()))
+	assert.Same(t, modifier.Logger, clone.Logger)
+
+	cloneCluster := clone.File().Body().Blocks()[0]
+	cloneCluster.Body().SetAttributeValue("name", cty.StringVal("renamed"))
+	cloneCluster.Body().RemoveBlock(cloneCluster.Body().Blocks()[0])
+
+	assert.Equal(t, original, string(modifier.File().Bytes()), "Mutating the clone must not change the original")
+	assert.NotEqual(t, original, string(clone.File().Bytes()))
+}
+
+func TestConditionBlockCountEquals(t *testing.T) {
+	newRule := func(expectedCount string) types.Rule {
+		return types.Rule{
+			Name:               "Mark clusters by node pool count",
+			TargetResourceType: "google_container_cluster",
+			Conditions: []types.RuleCondition{
+				{
+					Type:          types.BlockCountEquals,
+					Path:          []string{"node_pool"},
+					ExpectedValue: expectedCount,
+				},
+			},
+			Actions: []types.RuleAction{
+				{
+					Type:       types.SetAttributeValue,
+					Path:       []string{"description"},
+					ValueToSet: "matched",
+				},
+			},
+		}
+	}
+
+	tests := []struct {
+		name          string
+		nodePoolCount int
+		expectedCount string
+		expectMatch   bool
+	}{
+		{name: "Zero node pools match 0", nodePoolCount: 0, expectedCount: "0", expectMatch: true},
+		{name: "Zero node pools don't match 1", nodePoolCount: 0, expectedCount: "1", expectMatch: false},
+		{name: "One node pool matches 1", nodePoolCount: 1, expectedCount: "1", expectMatch: true},
+		{name: "Several node pools match their count", nodePoolCount: 3, expectedCount: "3", expectMatch: true},
+		{name: "Several node pools don't match 1", nodePoolCount: 3, expectedCount: "1", expectMatch: false},
+		{name: "Invalid ExpectedValue never matches", nodePoolCount: 1, expectedCount: "one", expectMatch: false},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			hclContent := "resource \"google_container_cluster\" \"primary\" {\n"
+			for i := 0; i < tc.nodePoolCount; i++ {
+				hclContent += fmt.Sprintf("  node_pool {\n    name = \"pool-%d\"\n  }\n", i)
+			}
+			hclContent += "}\n"
+			modifier := newTestModifier(t, hclContent)
+
+			modifications, errs := modifier.ApplyRules([]types.Rule{newRule(tc.expectedCount)})
+			assert.Empty(t, errs)
+			assert.Equal(t, tc.expectMatch, modifications == 1)
+		})
+	}
+}
+
+func TestChangeReportAppliedRuleNames(t *testing.T) {
+	modifier := newTestModifier(t, `resource "google_container_cluster" "primary" {
+  name              = "primary"
+  label_fingerprint = "a1b2c3"
+  node_pool {
+    name               = "pool-1"
+    initial_node_count = 1
+  }
+  node_pool {
+    name               = "pool-2"
+    initial_node_count = 1
+  }
+}`)
+	rulesToApply := []types.Rule{
+		rules.InitialNodeCountRuleDefinition,
+		rules.SetMinVersionRule,               // no node_version, doesn't fire
+		rules.DefaultSnatStatusRuleDefinition, // no default_snat_status, doesn't fire
+	}
+	rulesToApply = append(rulesToApply, rules.TopLevelComputedAttributesRules...) // only label_fingerprint fires
+
+	report, errs := modifier.ApplyRulesWithReport(rulesToApply)
+	assert.Empty(t, errs)
+	assert.Equal(t, []string{
+		rules.InitialNodeCountRuleDefinition.Name,
+		"Remove attribute '[label_fingerprint]' from 'google_container_cluster'",
+	}, report.AppliedRuleNames())
+}
+
+func TestConditionNumericComparison(t *testing.T) {
+	tests := []struct {
+		name          string
+		value         string
+		conditionType types.ConditionType
+		expectedValue string
+		expectMatch   bool
+	}{
+		{name: "Greater than", value: "256", conditionType: types.AttributeValueGreaterThan, expectedValue: "110", expectMatch: true},
+		{name: "Equal is not greater", value: "110", conditionType: types.AttributeValueGreaterThan, expectedValue: "110", expectMatch: false},
+		{name: "Less is not greater", value: "64", conditionType: types.AttributeValueGreaterThan, expectedValue: "110", expectMatch: false},
+		{name: "Less than", value: "64", conditionType: types.AttributeValueLessThan, expectedValue: "110", expectMatch: true},
+		{name: "Equal is not less", value: "110", conditionType: types.AttributeValueLessThan, expectedValue: "110", expectMatch: false},
+		{name: "Float threshold", value: "110", conditionType: types.AttributeValueGreaterThan, expectedValue: "109.5", expectMatch: true},
+		{name: "Non-numeric value", value: `"many"`, conditionType: types.AttributeValueGreaterThan, expectedValue: "110", expectMatch: false},
+		{name: "Non-numeric ExpectedValue", value: "256", conditionType: types.AttributeValueGreaterThan, expectedValue: "lots", expectMatch: false},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			modifier := newTestModifier(t, fmt.Sprintf(`resource "google_container_cluster" "primary" {
+  default_max_pods_per_node = %s
+}`, tc.value))
+			modifications, errs := modifier.ApplyRules([]types.Rule{
+				{
+					Name:               "Remove default_max_pods_per_node by threshold",
+					TargetResourceType: "google_container_cluster",
+					Conditions: []types.RuleCondition{
+						{Type: tc.conditionType, Path: []string{"default_max_pods_per_node"}, ExpectedValue: tc.expectedValue},
+					},
+					Actions: []types.RuleAction{
+						{Type: types.RemoveAttribute, Path: []string{"default_max_pods_per_node"}},
+					},
+				},
+			})
+			assert.Empty(t, errs)
+			assert.Equal(t, tc.expectMatch, modifications == 1)
+		})
+	}
+}
+
+func TestApplyRulesDisableAnnotation(t *testing.T) {
+	const fixture = "testdata/TestApplyRulesDisableAnnotation.tf"
+	rulesToApply := append([]types.Rule{rules.InitialNodeCountRuleDefinition}, rules.TopLevelComputedAttributesRules...)
+	modifier, modifications := applyRulesToFixture(t, fixture, rulesToApply)
+	assert.Equal(t, 3, modifications) // partial: id; cleaned: id, initial_node_count
+	assertMatchesGolden(t, modifier, fixture+".golden")
+}
+
+func TestParseDisableAnnotation(t *testing.T) {
+	tests := []struct {
+		comment       string
+		expectedRules []string
+		expectedOK    bool
+	}{
+		{comment: "# gke-tf-cleaner:disable\n", expectedOK: true},
+		{comment: "// gke-tf-cleaner:disable\n", expectedOK: true},
+		{comment: "/* gke-tf-cleaner:disable */", expectedOK: true},
+		{comment: "# gke-tf-cleaner:disable=Autopilot Cleanup\n", expectedRules: []string{"Autopilot Cleanup"}, expectedOK: true},
+		{comment: "# gke-tf-cleaner:disable=Autopilot Cleanup, Initial Node Count Rule\n", expectedRules: []string{"Autopilot Cleanup", "Initial Node Count Rule"}, expectedOK: true},
+		{comment: "# gke-tf-cleaner:disabled\n", expectedOK: false},
+		{comment: "# just a comment\n", expectedOK: false},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.comment, func(t *testing.T) {
+			ruleNames, ok := parseDisableAnnotation(tc.comment)
+			assert.Equal(t, tc.expectedOK, ok)
+			assert.Equal(t, tc.expectedRules, ruleNames)
+		})
+	}
+}
+
+func TestApplyRulesNestedBlockTargetLabels(t *testing.T) {
+	const hclContent = `resource "google_container_cluster" "primary" {
+  addon "dns" {
+    enabled = false
+  }
+  addon "gateway" {
+    enabled = false
+  }
+  addon {
+    enabled = false
+  }
+}`
+	newRule := func(labels []string) types.Rule {
+		return types.Rule{
+			Name:                    "Remove enabled from addons",
+			TargetResourceType:      "google_container_cluster",
+			ExecutionType:           types.RuleExecutionForEachNestedBlock,
+			NestedBlockTargetType:   "addon",
+			NestedBlockTargetLabels: labels,
+			Actions:                 []types.RuleAction{{Type: types.RemoveAttribute, Path: []string{"enabled"}}},
+		}
+	}
+
+	t.Run("Only the block with matching labels is processed", func(t *testing.T) {
+		modifier := newTestModifier(t, hclContent)
+		modifications, errs := modifier.ApplyRules([]types.Rule{newRule([]string{"gateway"})})
+		assert.Empty(t, errs)
+		assert.Equal(t, 1, modifications)
+
+		addons := modifier.File().Body().Blocks()[0].Body().Blocks()
+		assert.NotNil(t, addons[0].Body().GetAttribute("enabled"))
+		assert.Nil(t, addons[1].Body().GetAttribute("enabled"))
+		assert.NotNil(t, addons[2].Body().GetAttribute("enabled"))
+	})
+
+	t.Run("No labels processes every block of the type", func(t *testing.T) {
+		modifier := newTestModifier(t, hclContent)
+		modifications, errs := modifier.ApplyRules([]types.Rule{newRule(nil)})
+		assert.Empty(t, errs)
+		assert.Equal(t, 3, modifications)
+	})
+}
+
+func TestMissingParentBlockIsNoOp(t *testing.T) {
+	const hclContent = `resource "google_container_cluster" "primary" {
+  node_config {
+    disk_size_gb = 100
+  }
+}`
+
+	t.Run("GetNestedBlock wraps ErrBlockNotFound", func(t *testing.T) {
+		modifier := newTestModifier(t, hclContent)
+		body := modifier.File().Body().Blocks()[0].Body()
+		_, err := modifier.GetNestedBlock(body, []string{"node_config", "workload_metadata_config"})
+		assert.ErrorIs(t, err, types.ErrBlockNotFound)
+
+		_, err = modifier.GetNestedBlock(nil, []string{"node_config"})
+		assert.Error(t, err)
+		assert.NotErrorIs(t, err, types.ErrBlockNotFound)
+	})
+
+	t.Run("RemoveAttributeByPath", func(t *testing.T) {
+		modifier := newTestModifier(t, hclContent)
+		body := modifier.File().Body().Blocks()[0].Body()
+		modifications, err := modifier.RemoveAttributeByPath(body, []string{"cluster_autoscaling", "auto_provisioning_defaults", "disk_size"})
+		assert.NoError(t, err)
+		assert.Equal(t, 0, modifications)
+
+		_, err = modifier.RemoveAttributeByPath(nil, []string{"node_config", "disk_size_gb"})
+		assert.Error(t, err)
+		_, err = modifier.RemoveAttributeByPath(body, []string{"*", "disk_size_gb"})
+		assert.Error(t, err)
+	})
+
+	t.Run("RemoveNestedBlockByPath", func(t *testing.T) {
+		modifier := newTestModifier(t, hclContent)
+		body := modifier.File().Body().Blocks()[0].Body()
+		modifications, err := modifier.RemoveNestedBlockByPath(body, []string{"cluster_autoscaling", "auto_provisioning_defaults"})
+		assert.NoError(t, err)
+		assert.Equal(t, 0, modifications)
+
+		_, err = modifier.RemoveNestedBlockByPath(body, nil)
+		assert.Error(t, err)
+	})
+}
+
+func TestRemoveNestedBlockByPathTwoLevelParent(t *testing.T) {
+	modifier := newTestModifier(t, `resource "google_container_cluster" "primary" {
+  managed_prometheus {}
+
+  monitoring_config {
+    advanced_datapath_observability_config {
+      managed_prometheus {}
+      relay_mode = "INTERNAL_VPC_LB"
+    }
+    managed_prometheus {}
+  }
+}`)
+	body := modifier.File().Body().Blocks()[0].Body()
+
+	modifications, err := modifier.RemoveNestedBlockByPath(body, []string{"monitoring_config", "advanced_datapath_observability_config", "managed_prometheus"})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, modifications)
+
+	// Only the block under the full parent path is removed, not the ones of the same type higher up.
+	assert.Len(t, modifier.GetAllBlocksOfType(body, "managed_prometheus"), 1)
+	monitoringConfig, err := modifier.GetNestedBlock(body, []string{"monitoring_config"})
+	assert.NoError(t, err)
+	assert.Len(t, modifier.GetAllBlocksOfType(monitoringConfig.Body(), "managed_prometheus"), 1)
+	observability, err := modifier.GetNestedBlock(body, []string{"monitoring_config", "advanced_datapath_observability_config"})
+	assert.NoError(t, err)
+	assert.Empty(t, modifier.GetAllBlocksOfType(observability.Body(), "managed_prometheus"))
+	assert.NotNil(t, observability.Body().GetAttribute("relay_mode"))
+}
+
+func TestRemoveByPathComments(t *testing.T) {
+	const fixture = "testdata/TestRemoveByPathComments.tf"
+	rule := types.Rule{
+		Name:               "Remove commented attributes and blocks",
+		TargetResourceType: "google_container_cluster",
+		Actions: []types.RuleAction{
+			{Type: types.RemoveAttribute, Path: []string{"id"}},
+			{Type: types.RemoveAttribute, Path: []string{"node_version"}},
+			{Type: types.RemoveAttribute, Path: []string{"node_config", "disk_size_gb"}},
+			{Type: types.RemoveBlock, Path: []string{"pod_security_policy_config"}},
+			{Type: types.RemoveBlock, Path: []string{"timeouts"}},
+			{Type: types.RemoveAllNestedBlocksMatchingPath, Path: []string{"node_config", "taint"}},
+			{Type: types.RemoveAllBlocksOfType, BlockTypeToRemove: "lifecycle"},
+		},
+	}
+	modifier, modifications := applyRulesToFixture(t, fixture, []types.Rule{rule})
+	assert.Equal(t, 7, modifications)
+	assertMatchesGolden(t, modifier, fixture+".golden")
+	assertCommentsRemoved(t, modifier, fixture,
+		"# Computed by GKE.",
+		"/* Set by the import. */",
+		"// Deprecated block.",
+		"/*\n   * Left empty by the import.\n   */",
+		"# Default disk size.",
+		"# GB",
+		"/* GPU taint added by GKE */",
+		"# Added by the import.",
+	)
+}
+
+func TestRemoveMetaArgumentBlocks(t *testing.T) {
+	const hclContent = `resource "google_container_cluster" "primary" {
+  name = "primary"
+
+  lifecycle {
+    ignore_changes = [node_config, initial_node_count]
+  }
+}`
+	const expected = `resource "google_container_cluster" "primary" {
+  name = "primary"
+
+}`
+
+	t.Run("RemoveNestedBlockByPath", func(t *testing.T) {
+		modifier := newTestModifier(t, hclContent)
+		body := modifier.File().Body().Blocks()[0].Body()
+		removed, err := modifier.RemoveNestedBlockByPath(body, []string{"lifecycle"})
+		assert.NoError(t, err)
+		assert.Equal(t, 1, removed)
+		assert.Equal(t, expected, string(modifier.File().Bytes()))
+	})
+
+	t.Run("RemoveAllBlocksOfType", func(t *testing.T) {
+		modifier := newTestModifier(t, hclContent)
+		rule := types.Rule{
+			Name:               "Remove lifecycle",
+			TargetResourceType: "google_container_cluster",
+			Actions:            []types.RuleAction{{Type: types.RemoveAllBlocksOfType, BlockTypeToRemove: "lifecycle"}},
+		}
+		modifications, errs := modifier.ApplyRules([]types.Rule{rule})
+		assert.Empty(t, errs)
+		assert.Equal(t, 1, modifications)
+		assert.Equal(t, expected, string(modifier.File().Bytes()))
+	})
+}
+
+func TestConditionalBlockRemovalResolvesSameBlock(t *testing.T) {
+	// Both the condition and the RemoveBlock action address node_pool.node_config; with several node_pool
+	// blocks, they must both resolve to the first one.
+	rule := types.Rule{
+		Name:               "Remove node_config of the first pool when preemptible",
+		TargetResourceType: "google_container_cluster",
+		Conditions: []types.RuleCondition{
+			{Type: types.AttributeValueEquals, Path: []string{"node_pool", "node_config", "preemptible"}, ExpectedValue: "true"},
+		},
+		Actions: []types.RuleAction{{Type: types.RemoveBlock, Path: []string{"node_pool", "node_config"}}},
+	}
+
+	t.Run("Condition met on the first block", func(t *testing.T) {
+		modifier := newTestModifier(t, `resource "google_container_cluster" "primary" {
+  node_pool {
+    name = "first"
+    node_config {
+      preemptible = true
+    }
+  }
+  node_pool {
+    name = "second"
+    node_config {
+      preemptible = false
+    }
+  }
+}`)
+		modifications, errs := modifier.ApplyRules([]types.Rule{rule})
+		assert.Empty(t, errs)
+		assert.Equal(t, 1, modifications)
+		pools := modifier.File().Body().Blocks()[0].Body().Blocks()
+		assert.Empty(t, pools[0].Body().Blocks(), "node_config of the first pool should be removed")
+		assert.Len(t, pools[1].Body().Blocks(), 1, "node_config of the second pool should be kept")
+	})
+
+	t.Run("Condition only met on a later block", func(t *testing.T) {
+		modifier := newTestModifier(t, `resource "google_container_cluster" "primary" {
+  node_pool {
+    name = "first"
+    node_config {
+      preemptible = false
+    }
+  }
+  node_pool {
+    name = "second"
+    node_config {
+      preemptible = true
+    }
+  }
+}`)
+		modifications, errs := modifier.ApplyRules([]types.Rule{rule})
+		assert.Empty(t, errs)
+		assert.Equal(t, 0, modifications, "The condition must not look at a different block than the action")
+	})
+}
+
+func TestConditionAttributeExistsNonLiteral(t *testing.T) {
+	const hclContent = `variable "cluster_name" {}
+
+resource "google_container_cluster" "primary" {
+  name     = var.cluster_name
+  location = "us-central1"
+
+  node_pool {
+    node_count = var.node_count
+  }
+}`
+	modifier := newTestModifier(t, hclContent)
+	body := modifier.File().Body().Blocks()[1].Body()
+	logger := zap.NewNop()
+
+	tests := []struct {
+		name      string
+		condition types.RuleCondition
+		expected  bool
+	}{
+		{"AttributeExists with a variable reference", types.RuleCondition{Type: types.AttributeExists, Path: []string{"name"}}, true},
+		{"AttributeExists through a wildcard", types.RuleCondition{Type: types.AttributeExists, Path: []string{"node_pool", "*", "node_count"}}, true},
+		{"AttributeExists for a missing attribute", types.RuleCondition{Type: types.AttributeExists, Path: []string{"description"}}, false},
+		{"AttributeExistsAnyOf with a variable reference", types.RuleCondition{Type: types.AttributeExistsAnyOf, Paths: [][]string{{"description"}, {"name"}}}, true},
+		{"AttributeDoesntExist with a variable reference", types.RuleCondition{Type: types.AttributeDoesntExist, Path: []string{"name"}}, false},
+		{"AttributeDoesntExist for a missing attribute", types.RuleCondition{Type: types.AttributeDoesntExist, Path: []string{"description"}}, true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, modifier.checkCondition(body, body, tc.condition, logger))
+		})
+	}
+
+	assert.Nil(t, modifier.GetAttributeByPath(body, []string{"node_pool", "node_count", "nested"}))
+	assert.Nil(t, modifier.GetAttributeByPath(nil, []string{"name"}))
+}
+
+func TestConditionNullValue(t *testing.T) {
+	rule := types.Rule{
+		Name:               "Remove null node_version",
+		TargetResourceType: "google_container_cluster",
+		Conditions:         []types.RuleCondition{{Type: types.NullValue, Path: []string{"node_version"}}},
+		Actions:            []types.RuleAction{{Type: types.RemoveAttribute, Path: []string{"node_version"}}},
+	}
+
+	tests := []struct {
+		name                  string
+		hclContent            string
+		expectedModifications int
+	}{
+		{
+			name: "Attribute set to null",
+			hclContent: `resource "google_container_cluster" "primary" {
+  node_version = null
+}`,
+			expectedModifications: 1,
+		},
+		{
+			name: "Attribute with a value",
+			hclContent: `resource "google_container_cluster" "primary" {
+  node_version = "1.29.4-gke.1043002"
+}`,
+			expectedModifications: 0,
+		},
+		{
+			name: "Missing attribute",
+			hclContent: `resource "google_container_cluster" "primary" {
+  name = "primary"
+}`,
+			expectedModifications: 0,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			modifier := newTestModifier(t, tc.hclContent)
+			modifications, errs := modifier.ApplyRules([]types.Rule{rule})
+			assert.Empty(t, errs)
+			assert.Equal(t, tc.expectedModifications, modifications)
+		})
+	}
+}
+
+func TestConditionMapKeysEqual(t *testing.T) {
+	condition := types.RuleCondition{Type: types.MapKeysEqual, Path: []string{"metadata"}, ExpectedValues: []string{"b", "a"}}
+	tests := []struct {
+		name     string
+		metadata string
+		expected bool
+	}{
+		{"Same keys", `{ a = "1", b = "2" }`, true},
+		{"Same keys in another order", `{ b = "2", a = "1" }`, true},
+		{"Values that aren't literals", `{ a = "1", b = var.b }`, false},
+		{"Extra key", `{ a = "1", b = "2", c = "3" }`, false},
+		{"Missing key", `{ a = "1" }`, false},
+		{"Not a map", `["a", "b"]`, false},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			modifier := newTestModifier(t, "resource \"google_container_cluster\" \"primary\" {\n  metadata = "+tc.metadata+"\n}")
+			body := modifier.File().Body().Blocks()[0].Body()
+			assert.Equal(t, tc.expected, modifier.checkCondition(body, body, condition, zap.NewNop()))
+		})
+	}
+}
+
+func TestConditionListValuesEqual(t *testing.T) {
+	rule := types.Rule{
+		Name:               "Remove default node_locations",
+		TargetResourceType: "google_container_cluster",
+		Conditions: []types.RuleCondition{
+			{Type: types.ListValuesEqual, Path: []string{"node_locations"}, ExpectedValues: []string{"us-central1-a", "us-central1-b"}},
+		},
+		Actions: []types.RuleAction{{Type: types.RemoveAttribute, Path: []string{"node_locations"}}},
+	}
+
+	tests := []struct {
+		name                  string
+		hclContent            string
+		expectedModifications int
+	}{
+		{
+			name: "Same elements in another order",
+			hclContent: `resource "google_container_cluster" "primary" {
+  node_locations = ["us-central1-b", "us-central1-a"]
+}`,
+			expectedModifications: 1,
+		},
+		{
+			name: "Extra element",
+			hclContent: `resource "google_container_cluster" "primary" {
+  node_locations = ["us-central1-a", "us-central1-b", "us-central1-c"]
+}`,
+			expectedModifications: 0,
+		},
+		{
+			name: "Not a list",
+			hclContent: `resource "google_container_cluster" "primary" {
+  node_locations = "us-central1-a"
+}`,
+			expectedModifications: 0,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			modifier := newTestModifier(t, tc.hclContent)
+			modifications, errs := modifier.ApplyRules([]types.Rule{rule})
+			assert.Empty(t, errs)
+			assert.Equal(t, tc.expectedModifications, modifications)
+		})
+	}
+}
+
+func TestGetAllBlocksOfType(t *testing.T) {
+	modifier := newTestModifier(t, `resource "google_container_cluster" "primary" {
+  node_pool {
+    name = "first"
+  }
+
+  addons_config {
+    node_pool {
+      name = "nested"
+    }
+  }
+
+  node_pool {
+    name = "second"
+  }
+}`)
+	body := modifier.File().Body().Blocks()[0].Body()
+
+	var names []string
+	for _, block := range modifier.GetAllBlocksOfType(body, "node_pool") {
+		value, err := modifier.GetAttributeValue(block.Body().GetAttribute("name"))
+		assert.NoError(t, err)
+		names = append(names, value.AsString())
+	}
+	assert.Equal(t, []string{"first", "second"}, names)
+	assert.Len(t, modifier.GetAllBlocksOfType(body, "addons_config"), 1)
+	assert.Empty(t, modifier.GetAllBlocksOfType(body, "timeouts"))
+	assert.Empty(t, modifier.GetAllBlocksOfType(nil, "node_pool"))
+}
+
+func TestDeduplicateBlocks(t *testing.T) {
+	modifier := newTestModifier(t, `resource "google_container_cluster" "primary" {
+  node_pool {
+    name = "first"
+  }
+
+  node_pool {
+    name = "second"
+  }
+
+  addons_config {}
+
+  node_pool {
+    name = "third"
+  }
+}`)
+	body := modifier.File().Body().Blocks()[0].Body()
+
+	assert.Equal(t, 2, modifier.DeduplicateBlocks(body, "node_pool"))
+	nodePools := modifier.GetAllBlocksOfType(body, "node_pool")
+	if assert.Len(t, nodePools, 1) {
+		value, err := modifier.GetAttributeValue(nodePools[0].Body().GetAttribute("name"))
+		assert.NoError(t, err)
+		assert.Equal(t, "first", value.AsString())
+	}
+	assert.Len(t, modifier.GetAllBlocksOfType(body, "addons_config"), 1, "Other block types are untouched")
+
+	assert.Equal(t, 0, modifier.DeduplicateBlocks(body, "node_pool"), "A single block is kept")
+	assert.Equal(t, 0, modifier.DeduplicateBlocks(body, "timeouts"))
+	assert.Equal(t, 0, modifier.DeduplicateBlocks(nil, "node_pool"))
+}
+
+func TestRemoveMapEntriesByPath(t *testing.T) {
+	modifier := newTestModifier(t, `resource "google_container_cluster" "primary" {
+  resource_labels = {
+    goog-managed = "true"
+    goog         = "exact"
+    user         = "kept"
+  }
+  node_locations = ["us-central1-a"]
+
+  node_config {
+    labels = {
+      goog-managed = "true"
+    }
+    resource_labels = merge(local.labels, { goog-managed = "true" })
+  }
+}`)
+	body := modifier.File().Body().Blocks()[0].Body()
+
+	removed, err := modifier.RemoveMapEntriesByPath(body, []string{"resource_labels"}, "goog", false)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, removed, "Without matchPrefix only the exact key is removed")
+
+	removed, err = modifier.RemoveMapEntriesByPath(body, []string{"node_config", "labels"}, "goog-", true)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, removed)
+
+	removed, err = modifier.RemoveMapEntriesByPath(body, []string{"resource_labels"}, "absent", true)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, removed)
+
+	removed, err = modifier.RemoveMapEntriesByPath(body, []string{"master_auth", "labels"}, "goog-", true)
+	assert.NoError(t, err, "A missing parent block is a no-op")
+	assert.Equal(t, 0, removed)
+
+	removed, err = modifier.RemoveMapEntriesByPath(body, []string{"node_config", "resource_labels"}, "goog-", true)
+	assert.NoError(t, err, "A value that isn't a literal is a no-op")
+	assert.Equal(t, 0, removed)
+
+	_, err = modifier.RemoveMapEntriesByPath(body, []string{"node_locations"}, "goog-", true)
+	assert.ErrorContains(t, err, "is not a map")
+
+	assert.Equal(t, `resource "google_container_cluster" "primary" {
+  resource_labels = {
+    goog-managed = "true"
+    user         = "kept"
+  }
+  node_locations = ["us-central1-a"]
+
+  node_config {
+    labels = {
+    }
+    resource_labels = merge(local.labels, { goog-managed = "true" })
+  }
+}`, string(modifier.File().Bytes()))
+}
+
+func TestRemoveListElementByPath(t *testing.T) {
+	modifier := newTestModifier(t, `resource "google_container_cluster" "primary" {
+  node_locations = ["us-central1-a", "us-central1-b", "us-central1-c"]
+  location       = "us-central1-a"
+
+  node_config {
+    oauth_scopes = ["https://www.googleapis.com/auth/cloud-platform"]
+    tags         = var.tags
+  }
+}`)
+	body := modifier.File().Body().Blocks()[0].Body()
+
+	removed, err := modifier.RemoveListElementByPath(body, []string{"node_locations"}, cty.StringVal("us-central1-b"))
+	assert.NoError(t, err)
+	assert.Equal(t, 1, removed)
+
+	removed, err = modifier.RemoveListElementByPath(body, []string{"node_locations"}, cty.StringVal("europe-west1-b"))
+	assert.NoError(t, err)
+	assert.Equal(t, 0, removed, "A value not in the list leaves it unchanged")
+
+	removed, err = modifier.RemoveListElementByPath(body, []string{"node_config", "oauth_scopes"}, cty.StringVal("https://www.googleapis.com/auth/cloud-platform"))
+	assert.NoError(t, err)
+	assert.Equal(t, 1, removed, "Removing the last element removes the attribute")
+
+	removed, err = modifier.RemoveListElementByPath(body, []string{"master_auth", "scopes"}, cty.StringVal("x"))
+	assert.NoError(t, err, "A missing parent block is a no-op")
+	assert.Equal(t, 0, removed)
+
+	removed, err = modifier.RemoveListElementByPath(body, []string{"node_config", "tags"}, cty.StringVal("x"))
+	assert.NoError(t, err, "A value that isn't a literal is a no-op")
+	assert.Equal(t, 0, removed)
+
+	_, err = modifier.RemoveListElementByPath(body, []string{"location"}, cty.StringVal("us-central1-a"))
+	assert.ErrorContains(t, err, "is not a list")
+
+	assert.Equal(t, `resource "google_container_cluster" "primary" {
+  node_locations = ["us-central1-a", "us-central1-c"]
+  location       = "us-central1-a"
+
+  node_config {
+    tags = var.tags
+  }
+}`, string(modifier.File().Bytes()))
+}
+
+func TestApplyRulesRemoveListElement(t *testing.T) {
+	modifier := newTestModifier(t, `resource "google_container_cluster" "primary" {
+  node_locations = ["us-central1-a", "us-central1-b"]
+}`)
+	rule := types.Rule{
+		Name:               "Remove us-central1-a from node_locations",
+		TargetResourceType: "google_container_cluster",
+		Actions: []types.RuleAction{
+			{Type: types.RemoveListElement, Path: []string{"node_locations"}, ElementValue: "us-central1-a"},
+		},
+	}
+
+	modifications, errs := modifier.ApplyRules([]types.Rule{rule})
+	assert.Empty(t, errs)
+	assert.Equal(t, 1, modifications)
+	assert.Equal(t, `resource "google_container_cluster" "primary" {
+  node_locations = ["us-central1-b"]
+}`, string(modifier.File().Bytes()))
+}
+
+func TestApplyRulesToBlock(t *testing.T) {
+	const hclContent = `resource "google_container_cluster" "first" {
+  id = "projects/p/locations/us-central1/clusters/first"
+
+  node_pool {
+    initial_node_count = 1
+  }
+}
+
+resource "google_container_cluster" "second" {
+  id = "projects/p/locations/us-central1/clusters/second"
+
+  node_pool {
+    initial_node_count = 1
+  }
+}
+
+resource "google_container_node_pool" "pool" {
+  id = "projects/p/locations/us-central1/clusters/second/nodePools/pool"
+}`
+	modifier := newTestModifier(t, hclContent)
+	blocks := modifier.File().Body().Blocks()
+	rulesToApply := []types.Rule{rules.TopLevelComputedAttributesRules[2], rules.InitialNodeCountRuleDefinition}
+
+	modifications, errs := modifier.ApplyRulesToBlock(blocks[1], rulesToApply)
+	assert.Empty(t, errs)
+	assert.Equal(t, 2, modifications)
+	assert.Nil(t, blocks[1].Body().GetAttribute("id"))
+	assert.Nil(t, blocks[1].Body().Blocks()[0].Body().GetAttribute("initial_node_count"))
+
+	assert.NotNil(t, blocks[0].Body().GetAttribute("id"), "The other cluster must be untouched")
+	assert.NotNil(t, blocks[0].Body().Blocks()[0].Body().GetAttribute("initial_node_count"), "The other cluster must be untouched")
+
+	modifications, errs = modifier.ApplyRulesToBlock(blocks[2], rulesToApply)
+	assert.Empty(t, errs)
+	assert.Equal(t, 0, modifications, "Rules targeting another resource type are skipped")
+
+	_, errs = modifier.ApplyRulesToBlock(nil, rulesToApply)
+	assert.Len(t, errs, 1)
+}
+
+func TestApplyRulesTargetResourceLabelPattern(t *testing.T) {
+	const hclContent = `resource "google_container_cluster" "primary" {
+  id = "projects/p/locations/us-central1/clusters/primary"
+}
+
+resource "google_container_cluster" "legacy_cluster" {
+  id = "projects/p/locations/us-central1/clusters/legacy"
+}`
+	rule := types.Rule{
+		Name:                       "Remove id from non-legacy clusters",
+		TargetResourceType:         "google_container_cluster",
+		TargetResourceLabelPattern: "^primary$",
+		Actions:                    []types.RuleAction{{Type: types.RemoveAttribute, Path: []string{"id"}}},
+	}
+
+	t.Run("Only the matching resource is processed", func(t *testing.T) {
+		modifier := newTestModifier(t, hclContent)
+		modifications, errs := modifier.ApplyRules([]types.Rule{rule})
+		assert.Empty(t, errs)
+		assert.Equal(t, 1, modifications)
+		blocks := modifier.File().Body().Blocks()
+		assert.Nil(t, blocks[0].Body().GetAttribute("id"))
+		assert.NotNil(t, blocks[1].Body().GetAttribute("id"))
+	})
+
+	t.Run("Empty pattern matches every resource", func(t *testing.T) {
+		modifier := newTestModifier(t, hclContent)
+		allResourcesRule := rule
+		allResourcesRule.TargetResourceLabelPattern = ""
+		modifications, errs := modifier.ApplyRules([]types.Rule{allResourcesRule})
+		assert.Empty(t, errs)
+		assert.Equal(t, 2, modifications)
+	})
+
+	t.Run("Invalid pattern is an error", func(t *testing.T) {
+		modifier := newTestModifier(t, hclContent)
+		invalidRule := rule
+		invalidRule.TargetResourceLabelPattern = "(legacy"
+		modifications, errs := modifier.ApplyRules([]types.Rule{invalidRule})
+		assert.Len(t, errs, 1)
+		assert.Equal(t, 0, modifications)
+	})
+}
+
+func TestApplyRulesTargetBlockType(t *testing.T) {
+	const hclContent = `data "google_container_cluster" "existing" {
+  name    = "existing"
+  project = "my-project"
+}
+
+resource "google_container_cluster" "primary" {
+  name    = "primary"
+  project = "my-project"
+}`
+	newRule := func(targetBlockType string) types.Rule {
+		return types.Rule{
+			Name:               "Remove project",
+			TargetBlockType:    targetBlockType,
+			TargetResourceType: "google_container_cluster",
+			Actions:            []types.RuleAction{{Type: types.RemoveAttribute, Path: []string{"project"}}},
+		}
+	}
+
+	t.Run("Data rule only processes data blocks", func(t *testing.T) {
+		modifier := newTestModifier(t, hclContent)
+		modifications, errs := modifier.ApplyRules([]types.Rule{newRule("data")})
+		assert.Empty(t, errs)
+		assert.Equal(t, 1, modifications)
+		blocks := modifier.File().Body().Blocks()
+		assert.Nil(t, blocks[0].Body().GetAttribute("project"))
+		assert.NotNil(t, blocks[1].Body().GetAttribute("project"))
+	})
+
+	t.Run("Resource rules ignore data blocks by default", func(t *testing.T) {
+		modifier := newTestModifier(t, hclContent)
+		modifications, errs := modifier.ApplyRules([]types.Rule{newRule("")})
+		assert.Empty(t, errs)
+		assert.Equal(t, 1, modifications)
+		blocks := modifier.File().Body().Blocks()
+		assert.NotNil(t, blocks[0].Body().GetAttribute("project"))
+		assert.Nil(t, blocks[1].Body().GetAttribute("project"))
+	})
+}
+
+func TestApplyRulesRunAfter(t *testing.T) {
+	const hclContent = `resource "google_container_cluster" "primary" {
+  node_version = "1.29.4-gke.1043002"
+}`
+	removeNodeVersion := types.Rule{
+		Name:               "Remove node_version",
+		TargetResourceType: "google_container_cluster",
+		Conditions:         []types.RuleCondition{{Type: types.AttributeExists, Path: []string{"min_master_version"}}},
+		Actions:            []types.RuleAction{{Type: types.RemoveAttribute, Path: []string{"node_version"}}},
+		RunAfter:           []string{"Set min_master_version"},
+	}
+	setMinMasterVersion := types.Rule{
+		Name:               "Set min_master_version",
+		TargetResourceType: "google_container_cluster",
+		Conditions:         []types.RuleCondition{{Type: types.AttributeExists, Path: []string{"node_version"}}},
+		Actions:            []types.RuleAction{{Type: types.SetAttributeValue, Path: []string{"min_master_version"}, PathToSet: []string{"node_version"}}},
+	}
+
+	t.Run("Dependent rule runs after its prerequisite", func(t *testing.T) {
+		modifier := newTestModifier(t, hclContent)
+		report, errs := modifier.ApplyRulesWithReport([]types.Rule{removeNodeVersion, setMinMasterVersion})
+		assert.Empty(t, errs)
+		assert.Equal(t, []string{"Set min_master_version", "Remove node_version"}, report.AppliedRuleNames())
+		body := modifier.File().Body().Blocks()[0].Body()
+		assert.Nil(t, body.GetAttribute("node_version"))
+		assert.NotNil(t, body.GetAttribute("min_master_version"))
+	})
+
+	t.Run("Rules without dependencies keep their order", func(t *testing.T) {
+		first := types.Rule{Name: "first"}
+		second := types.Rule{Name: "second"}
+		third := types.Rule{Name: "third", RunAfter: []string{"fourth", "not a rule"}}
+		fourth := types.Rule{Name: "fourth"}
+		ordered, err := orderRules([]types.Rule{third, first, second, fourth})
+		assert.NoError(t, err)
+		var names []string
+		for _, rule := range ordered {
+			names = append(names, rule.Name)
+		}
+		assert.Equal(t, []string{"first", "second", "fourth", "third"}, names)
+	})
+
+	t.Run("Cycle is an error", func(t *testing.T) {
+		cyclic := setMinMasterVersion
+		cyclic.RunAfter = []string{"Remove node_version"}
+		modifier := newTestModifier(t, hclContent)
+		modifications, errs := modifier.ApplyRules([]types.Rule{removeNodeVersion, cyclic})
+		if assert.Len(t, errs, 1) {
+			assert.Contains(t, errs[0].Error(), "cycle")
+		}
+		assert.Equal(t, 0, modifications)
+	})
+}
+
+func TestAttributeChangesLogValues(t *testing.T) {
+	const hclContent = `resource "google_container_cluster" "primary" {
+  logging_service    = "logging.googleapis.com/kubernetes"
+  monitoring_service = var.monitoring_service
+  node_version       = "1.28.3-gke.1286000"
+}`
+	rule := types.Rule{
+		Name:               "Change attributes",
+		TargetResourceType: "google_container_cluster",
+		Actions: []types.RuleAction{
+			{Type: types.RemoveAttribute, Path: []string{"logging_service"}},
+			{Type: types.RemoveAttribute, Path: []string{"monitoring_service"}},
+			{Type: types.SetAttributeValue, Path: []string{"node_version"}, ValueToSet: "1.29.4-gke.1043002"},
+		},
+	}
+
+	core, logs := observer.New(zap.InfoLevel)
+	modifier := newTestModifier(t, hclContent)
+	modifier.Logger = zap.New(core)
+	modifications, errs := modifier.ApplyRules([]types.Rule{rule})
+	assert.Empty(t, errs)
+	assert.Equal(t, 3, modifications)
+
+	var removedValues []string
+	for _, entry := range logs.FilterMessage("RemoveAttributeByPath: Successfully removed attribute.").All() {
+		removedValues = append(removedValues, entry.ContextMap()["removedValue"].(string))
+	}
+	assert.Equal(t, []string{`cty.StringVal("logging.googleapis.com/kubernetes")`, "var.monitoring_service"}, removedValues,
+		"Non-literal values are logged as their expression")
+
+	setEntries := logs.FilterMessage("SetAttributeValueByPath: Successfully set/updated attribute.").All()
+	if assert.Len(t, setEntries, 1) {
+		fields := setEntries[0].ContextMap()
+		assert.Equal(t, `cty.StringVal("1.28.3-gke.1286000")`, fields["previousValue"])
+		assert.Equal(t, `cty.StringVal("1.29.4-gke.1043002")`, fields["valueToSet"])
+	}
+}
+
+func TestConditionAttributeExistsAnyOf(t *testing.T) {
+	rule := types.Rule{
+		Name:               "Warn about legacy services",
+		TargetResourceType: "google_container_cluster",
+		Conditions: []types.RuleCondition{
+			{
+				Type:  types.AttributeExistsAnyOf,
+				Paths: [][]string{{"logging_service"}, {"monitoring_service"}, {"addons_config", "kubernetes_dashboard", "disabled"}},
+			},
+		},
+		Actions: []types.RuleAction{{Type: types.ReportWarning, Message: "legacy service settings"}},
+	}
+
+	tests := []struct {
+		name          string
+		hclContent    string
+		expectWarning bool
+	}{
+		{
+			name: "None present",
+			hclContent: `resource "google_container_cluster" "primary" {
+  name = "primary"
+}`,
+			expectWarning: false,
+		},
+		{
+			name: "One present",
+			hclContent: `resource "google_container_cluster" "primary" {
+  monitoring_service = "monitoring.googleapis.com/kubernetes"
+}`,
+			expectWarning: true,
+		},
+		{
+			name: "Nested candidate present",
+			hclContent: `resource "google_container_cluster" "primary" {
+  addons_config {
+    kubernetes_dashboard {
+      disabled = true
+    }
+  }
+}`,
+			expectWarning: true,
+		},
+		{
+			name: "Several present",
+			hclContent: `resource "google_container_cluster" "primary" {
+  logging_service    = "logging.googleapis.com/kubernetes"
+  monitoring_service = "monitoring.googleapis.com/kubernetes"
+}`,
+			expectWarning: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			modifier := newTestModifier(t, tc.hclContent)
+			report, errs := modifier.ApplyRulesWithReport([]types.Rule{rule})
+			assert.Empty(t, errs)
+			if tc.expectWarning {
+				assert.Len(t, report.Warnings, 1, "One warning per resource, however many attributes are present")
+			} else {
+				assert.Empty(t, report.Warnings)
+			}
+		})
+	}
+}
+
+func TestAllRulesKeepReferences(t *testing.T) {
+	// Rules whose conditions only check that an attribute exists must not fail on values that aren't literals.
+	modifier, _ := applyRulesToFixture(t, "testdata/TestAllRulesReferences.tf", rules.AllRules())
+
+	content := string(modifier.File().Bytes())
+	for _, expected := range []string{
+		"node_locations            = var.zones",
+		"resource_labels           = local.labels",
+		"metadata        = var.metadata",
+		"tags            = var.tags",
+		"node_locations = var.zones",
+	} {
+		assert.Contains(t, content, expected)
+	}
+}
@@ -6,6 +6,7 @@ import (
 
 	"github.com/hashicorp/hcl/v2"
 	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/stretchr/testify/assert"
 	"go.uber.org/zap"
 
 	"github.com/kotatut/cluster_import_cleaner/hclmodifier/rules"
@@ -399,3 +400,236 @@ func TestApplyClusterIPV4CIDRRule(t *testing.T) {
 		})
 	}
 }
+
+func TestApplyDefaultSnatStatusRule(t *testing.T) {
+	tests := []struct {
+		name                  string
+		fixture               string
+		expectedModifications int
+	}{
+		{
+			name:                  "disabled = false is removed",
+			fixture:               "testdata/TestApplyDefaultSnatStatusRule_DisabledFalse.tf",
+			expectedModifications: 1,
+		},
+		{
+			name:                  "disabled = true is kept",
+			fixture:               "testdata/TestApplyDefaultSnatStatusRule_DisabledTrue.tf",
+			expectedModifications: 0,
+		},
+		{
+			name:                  "Block absent",
+			fixture:               "testdata/TestApplyDefaultSnatStatusRule_BlockAbsent.tf",
+			expectedModifications: 0,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			modifier, modifications := applyRulesToFixture(t, tc.fixture, []types.Rule{rules.DefaultSnatStatusRuleDefinition})
+			assert.Equal(t, tc.expectedModifications, modifications)
+			assertMatchesGolden(t, modifier, tc.fixture+".golden")
+		})
+	}
+}
+
+func TestApplyGatewayAPIConfigRule(t *testing.T) {
+	tests := []struct {
+		name                  string
+		fixture               string
+		expectedModifications int
+	}{
+		{
+			name:                  "CHANNEL_DISABLED is removed",
+			fixture:               "testdata/TestApplyGatewayAPIConfigRule_Disabled.tf",
+			expectedModifications: 1,
+		},
+		{
+			name:                  "CHANNEL_STANDARD is kept",
+			fixture:               "testdata/TestApplyGatewayAPIConfigRule_Standard.tf",
+			expectedModifications: 0,
+		},
+		{
+			name:                  "Block absent",
+			fixture:               "testdata/TestApplyGatewayAPIConfigRule_BlockAbsent.tf",
+			expectedModifications: 0,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			modifier, modifications := applyRulesToFixture(t, tc.fixture, []types.Rule{rules.GatewayAPIConfigRuleDefinition})
+			assert.Equal(t, tc.expectedModifications, modifications)
+			assertMatchesGolden(t, modifier, tc.fixture+".golden")
+		})
+	}
+}
+
+func TestApplyPrivateClusterConfigDefaultsRules(t *testing.T) {
+	tests := []struct {
+		name                  string
+		fixture               string
+		expectedModifications int
+	}{
+		{
+			name:                  "All defaults",
+			fixture:               "testdata/TestApplyPrivateClusterConfigDefaultsRules_AllDefault.tf",
+			expectedModifications: 3,
+		},
+		{
+			name:                  "Private nodes enabled",
+			fixture:               "testdata/TestApplyPrivateClusterConfigDefaultsRules_PrivateNodes.tf",
+			expectedModifications: 0,
+		},
+		{
+			name:                  "master_ipv4_cidr_block set",
+			fixture:               "testdata/TestApplyPrivateClusterConfigDefaultsRules_MasterCIDR.tf",
+			expectedModifications: 0,
+		},
+		{
+			name:                  "Block keeps other settings",
+			fixture:               "testdata/TestApplyPrivateClusterConfigDefaultsRules_OtherSettings.tf",
+			expectedModifications: 2,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			allRules := append([]types.Rule{rules.MasterCIDRRuleDefinition}, rules.PrivateClusterConfigDefaultsRules...)
+			modifier, modifications := applyRulesToFixture(t, tc.fixture, allRules)
+			assert.Equal(t, tc.expectedModifications, modifications)
+			assertMatchesGolden(t, modifier, tc.fixture+".golden")
+		})
+	}
+}
+
+func TestApplyNetworkSelfLinkRules(t *testing.T) {
+	tests := []struct {
+		name                  string
+		fixture               string
+		expectedModifications int
+	}{
+		{
+			name:                  "Self-links are shortened",
+			fixture:               "testdata/TestApplyNetworkSelfLinkRules_SelfLink.tf",
+			expectedModifications: 2,
+		},
+		{
+			name:                  "Names and references are kept",
+			fixture:               "testdata/TestApplyNetworkSelfLinkRules_Short.tf",
+			expectedModifications: 0,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			modifier, modifications := applyRulesToFixture(t, tc.fixture, rules.NetworkSelfLinkRules)
+			assert.Equal(t, tc.expectedModifications, modifications)
+			assertMatchesGolden(t, modifier, tc.fixture+".golden")
+		})
+	}
+
+	t.Run("References resolved for the condition are kept", func(t *testing.T) {
+		const fixture = "testdata/TestApplyNetworkSelfLinkRules_ResolvedReference.tf"
+		modifier, err := NewFromFile(fixture, zap.NewNop())
+		if err != nil {
+			t.Fatalf("NewFromFile(%s) error = %v", fixture, err)
+		}
+		modifier.ResolveReferences = true
+		modifications, errs := modifier.ApplyRules(rules.NetworkSelfLinkRules)
+		assert.Empty(t, errs)
+		assert.Equal(t, 0, modifications)
+		assertMatchesGolden(t, modifier, fixture+".golden")
+	})
+}
+
+func TestApplyDefaultMaxPodsPerNodeRule(t *testing.T) {
+	tests := []struct {
+		name                  string
+		fixture               string
+		expectedModifications int
+	}{
+		{
+			name:                  "No ip_allocation_policy",
+			fixture:               "testdata/TestApplyDefaultMaxPodsPerNodeRule_NoPolicy.tf",
+			expectedModifications: 1,
+		},
+		{
+			name:                  "With ip_allocation_policy",
+			fixture:               "testdata/TestApplyDefaultMaxPodsPerNodeRule_WithPolicy.tf",
+			expectedModifications: 0,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			modifier, modifications := applyRulesToFixture(t, tc.fixture, []types.Rule{rules.DefaultMaxPodsPerNodeRuleDefinition})
+			assert.Equal(t, tc.expectedModifications, modifications)
+			assertMatchesGolden(t, modifier, tc.fixture+".golden")
+		})
+	}
+}
+
+func TestApplyL4IlbSubsettingFalseRule(t *testing.T) {
+	tests := []struct {
+		name                  string
+		fixture               string
+		expectedModifications int
+	}{
+		{
+			name:                  "enable_l4_ilb_subsetting is false",
+			fixture:               "testdata/TestApplyL4IlbSubsettingFalseRule_False.tf",
+			expectedModifications: 1,
+		},
+		{
+			name:                  "enable_l4_ilb_subsetting is true",
+			fixture:               "testdata/TestApplyL4IlbSubsettingFalseRule_True.tf",
+			expectedModifications: 0,
+		},
+		{
+			name:                  "enable_l4_ilb_subsetting is absent",
+			fixture:               "testdata/TestApplyL4IlbSubsettingFalseRule_Absent.tf",
+			expectedModifications: 0,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			modifier, modifications := applyRulesToFixture(t, tc.fixture, []types.Rule{rules.L4IlbSubsettingFalseRuleDefinition})
+			assert.Equal(t, tc.expectedModifications, modifications)
+			assertMatchesGolden(t, modifier, tc.fixture+".golden")
+		})
+	}
+}
+
+func TestApplyDefaultDNSConfigRule(t *testing.T) {
+	tests := []struct {
+		name                  string
+		fixture               string
+		expectedModifications int
+	}{
+		{
+			name:                  "Empty dns_config",
+			fixture:               "testdata/TestApplyDefaultDNSConfigRule_Empty.tf",
+			expectedModifications: 1,
+		},
+		{
+			name:                  "Default values",
+			fixture:               "testdata/TestApplyDefaultDNSConfigRule_Defaults.tf",
+			expectedModifications: 1,
+		},
+		{
+			name:                  "Cloud DNS",
+			fixture:               "testdata/TestApplyDefaultDNSConfigRule_CloudDNS.tf",
+			expectedModifications: 0,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			modifier, modifications := applyRulesToFixture(t, tc.fixture, []types.Rule{rules.DefaultDNSConfigRuleDefinition})
+			assert.Equal(t, tc.expectedModifications, modifications)
+			assertMatchesGolden(t, modifier, tc.fixture+".golden")
+		})
+	}
+}
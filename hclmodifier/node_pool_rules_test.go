@@ -6,6 +6,7 @@ import (
 
 	"github.com/hashicorp/hcl/v2"
 	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/stretchr/testify/assert"
 	"github.com/zclconf/go-cty/cty"
 	"go.uber.org/zap"
 
@@ -334,3 +335,438 @@ resource "google_container_cluster" "gke_two" {
 		})
 	}
 }
+
+func TestApplyNetworkTagsRule(t *testing.T) {
+	tests := []struct {
+		name                  string
+		fixture               string
+		expectedModifications int
+	}{
+		{
+			name:                  "Managed and user tags mixed",
+			fixture:               "testdata/TestApplyNetworkTagsRule_MixedTags.tf",
+			expectedModifications: 2,
+		},
+		{
+			name:                  "Only managed tags in one pool removes the attribute",
+			fixture:               "testdata/TestApplyNetworkTagsRule_OnlyManagedTags.tf",
+			expectedModifications: 2,
+		},
+		{
+			name:                  "Only user tags",
+			fixture:               "testdata/TestApplyNetworkTagsRule_OnlyUserTags.tf",
+			expectedModifications: 0,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			modifier, modifications := applyRulesToFixture(t, tc.fixture, []types.Rule{rules.RemoveGKEManagedNetworkTagsRuleDefinition})
+			assert.Equal(t, tc.expectedModifications, modifications)
+			assertMatchesGolden(t, modifier, tc.fixture+".golden")
+		})
+	}
+}
+
+func TestApplyGuestAcceleratorRule(t *testing.T) {
+	tests := []struct {
+		name                  string
+		fixture               string
+		expectedModifications int
+	}{
+		{
+			name:                  "Pool with multiple accelerators and a pool without",
+			fixture:               "testdata/TestApplyGuestAcceleratorRule_MultipleAccelerators.tf",
+			expectedModifications: 3, // two gpu_partition_size attributes, one empty gpu_sharing_config
+		},
+		{
+			name:                  "Block comments above removed fields go too",
+			fixture:               "testdata/TestApplyGuestAcceleratorRule_Comments.tf",
+			expectedModifications: 2,
+		},
+		{
+			name:                  "No accelerators",
+			fixture:               "testdata/TestApplyGuestAcceleratorRule_NoAccelerators.tf",
+			expectedModifications: 0,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			modifier, modifications := applyRulesToFixture(t, tc.fixture, []types.Rule{rules.GuestAcceleratorComputedFieldsRuleDefinition})
+			assert.Equal(t, tc.expectedModifications, modifications)
+			assertMatchesGolden(t, modifier, tc.fixture+".golden")
+		})
+	}
+}
+
+func TestApplyZonalTotalNodeCountsRule(t *testing.T) {
+	tests := []struct {
+		name                  string
+		fixture               string
+		expectedModifications int
+	}{
+		{
+			name:                  "Zonal cluster",
+			fixture:               "testdata/TestApplyZonalTotalNodeCountsRule_Zonal.tf",
+			expectedModifications: 2,
+		},
+		{
+			name:                  "Regional cluster",
+			fixture:               "testdata/TestApplyZonalTotalNodeCountsRule_Regional.tf",
+			expectedModifications: 0,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			modifier, modifications := applyRulesToFixture(t, tc.fixture, []types.Rule{rules.ZonalTotalNodeCountsRuleDefinition})
+			assert.Equal(t, tc.expectedModifications, modifications)
+			assertMatchesGolden(t, modifier, tc.fixture+".golden")
+		})
+	}
+}
+
+func TestApplyLocalSsdCountRule(t *testing.T) {
+	tests := []struct {
+		name                  string
+		fixture               string
+		expectedModifications int
+	}{
+		{
+			name:                  "Zero and non-zero counts",
+			fixture:               "testdata/TestApplyLocalSsdCountRule_ZeroAndNonZero.tf",
+			expectedModifications: 1,
+		},
+		{
+			name:                  "Non-zero count only",
+			fixture:               "testdata/TestApplyLocalSsdCountRule_NonZero.tf",
+			expectedModifications: 0,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			modifier, modifications := applyRulesToFixture(t, tc.fixture, []types.Rule{rules.LocalSsdCountRuleDefinition})
+			assert.Equal(t, tc.expectedModifications, modifications)
+			assertMatchesGolden(t, modifier, tc.fixture+".golden")
+		})
+	}
+}
+
+func TestApplyNodePoolPodRangeRule(t *testing.T) {
+	tests := []struct {
+		name                  string
+		fixture               string
+		expectedModifications int
+	}{
+		{
+			name:                  "CIDR block and pod range",
+			fixture:               "testdata/TestApplyNodePoolPodRangeRule_BothPresent.tf",
+			expectedModifications: 2,
+		},
+		{
+			name:                  "CIDR block only",
+			fixture:               "testdata/TestApplyNodePoolPodRangeRule_OnlyCIDR.tf",
+			expectedModifications: 0,
+		},
+		{
+			name:                  "No network_config",
+			fixture:               "testdata/TestApplyNodePoolPodRangeRule_NoNetworkConfig.tf",
+			expectedModifications: 0,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			modifier, modifications := applyRulesToFixture(t, tc.fixture, []types.Rule{rules.NodePoolPodRangeRuleDefinition})
+			assert.Equal(t, tc.expectedModifications, modifications)
+			assertMatchesGolden(t, modifier, tc.fixture+".golden")
+		})
+	}
+}
+
+func TestApplyUpgradeSettingsDefaultsRule(t *testing.T) {
+	tests := []struct {
+		name                  string
+		fixture               string
+		expectedModifications int
+	}{
+		{
+			name:                  "All defaults",
+			fixture:               "testdata/TestApplyUpgradeSettingsDefaultsRule_Defaults.tf",
+			expectedModifications: 1,
+		},
+		{
+			name:                  "Customized surge and strategy",
+			fixture:               "testdata/TestApplyUpgradeSettingsDefaultsRule_Customized.tf",
+			expectedModifications: 0,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			modifier, modifications := applyRulesToFixture(t, tc.fixture, []types.Rule{rules.UpgradeSettingsDefaultsRuleDefinition})
+			assert.Equal(t, tc.expectedModifications, modifications)
+			assertMatchesGolden(t, modifier, tc.fixture+".golden")
+		})
+	}
+}
+
+func TestApplyNodeConfigMetadataRules(t *testing.T) {
+	tests := []struct {
+		name                  string
+		fixture               string
+		expectedModifications int
+	}{
+		{
+			name:                  "Only disable-legacy-endpoints",
+			fixture:               "testdata/TestApplyNodeConfigMetadataRules_OnlyLegacyEndpoints.tf",
+			expectedModifications: 2, // the entry, then the empty metadata
+		},
+		{
+			name:                  "Extra keys are left unchanged",
+			fixture:               "testdata/TestApplyNodeConfigMetadataRules_ExtraKeys.tf",
+			expectedModifications: 0,
+		},
+		{
+			name:                  "No metadata",
+			fixture:               "testdata/TestApplyNodeConfigMetadataRules_NoMetadata.tf",
+			expectedModifications: 0,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			modifier, modifications := applyRulesToFixture(t, tc.fixture, rules.NodeConfigMetadataRules)
+			assert.Equal(t, tc.expectedModifications, modifications)
+			assertMatchesGolden(t, modifier, tc.fixture+".golden")
+		})
+	}
+}
+
+func TestApplyNodeConfigDiskDefaultsRules(t *testing.T) {
+	tests := []struct {
+		name                  string
+		fixture               string
+		rules                 []types.Rule
+		expectedModifications int
+	}{
+		{
+			name:                  "GKE defaults",
+			fixture:               "testdata/TestApplyNodeConfigDiskDefaultsRule_Mixed.tf",
+			rules:                 rules.NodeConfigDiskDefaultsRules,
+			expectedModifications: 4, // disk_size_gb and disk_type in default-pool, one of each in the others
+		},
+		{
+			name:                  "Custom defaults",
+			fixture:               "testdata/TestApplyNodeConfigDiskDefaultsRule_CustomDefaults.tf",
+			rules:                 rules.NewNodeConfigDiskDefaultsRules(500, "pd-ssd"),
+			expectedModifications: 2,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			modifier, modifications := applyRulesToFixture(t, tc.fixture, tc.rules)
+			assert.Equal(t, tc.expectedModifications, modifications)
+			assertMatchesGolden(t, modifier, tc.fixture+".golden")
+		})
+	}
+}
+
+func TestApplyDisabledClusterAutoscalingRule(t *testing.T) {
+	tests := []struct {
+		name                  string
+		fixture               string
+		expectedModifications int
+	}{
+		{
+			name:                  "Disabled autoscaling",
+			fixture:               "testdata/TestApplyDisabledClusterAutoscalingRule_Disabled.tf",
+			expectedModifications: 1,
+		},
+		{
+			name:                  "Enabled autoscaling",
+			fixture:               "testdata/TestApplyDisabledClusterAutoscalingRule_Enabled.tf",
+			expectedModifications: 0,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			modifier, modifications := applyRulesToFixture(t, tc.fixture, []types.Rule{rules.DisabledClusterAutoscalingRuleDefinition})
+			assert.Equal(t, tc.expectedModifications, modifications)
+			assertMatchesGolden(t, modifier, tc.fixture+".golden")
+		})
+	}
+}
+
+func TestApplyKubeletConfigDefaultsRules(t *testing.T) {
+	tests := []struct {
+		name                  string
+		fixture               string
+		expectedModifications int
+	}{
+		{
+			name:                  "Only defaults",
+			fixture:               "testdata/TestApplyKubeletConfigDefaultsRule_AllDefaults.tf",
+			expectedModifications: 4, // three attributes, then the empty kubelet_config block
+		},
+		{
+			name:                  "Real setting",
+			fixture:               "testdata/TestApplyKubeletConfigDefaultsRule_RealSetting.tf",
+			expectedModifications: 1,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			modifier, modifications := applyRulesToFixture(t, tc.fixture, rules.KubeletConfigDefaultsRules)
+			assert.Equal(t, tc.expectedModifications, modifications)
+			assertMatchesGolden(t, modifier, tc.fixture+".golden")
+		})
+	}
+}
+
+func TestApplySystemTaintsRule(t *testing.T) {
+	tests := []struct {
+		name                  string
+		fixture               string
+		expectedModifications int
+	}{
+		{
+			name:                  "System and user taints",
+			fixture:               "testdata/TestApplySystemTaintsRule_Mixed.tf",
+			expectedModifications: 2,
+		},
+		{
+			name:                  "Only user taints",
+			fixture:               "testdata/TestApplySystemTaintsRule_UserTaintsOnly.tf",
+			expectedModifications: 0,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			modifier, modifications := applyRulesToFixture(t, tc.fixture, []types.Rule{rules.SystemTaintsRuleDefinition})
+			assert.Equal(t, tc.expectedModifications, modifications)
+			assertMatchesGolden(t, modifier, tc.fixture+".golden")
+		})
+	}
+}
+
+func TestApplyEmptyNodeConfigRule(t *testing.T) {
+	tests := []struct {
+		name                  string
+		fixture               string
+		expectedModifications int
+	}{
+		{
+			name:                  "Emptied by prior rules",
+			fixture:               "testdata/TestApplyEmptyNodeConfigRule_EmptiedByRules.tf",
+			expectedModifications: 5, // local_ssd_count, two kubelet_config attributes, kubelet_config, node_config
+		},
+		{
+			name:                  "Still has content",
+			fixture:               "testdata/TestApplyEmptyNodeConfigRule_HasContent.tf",
+			expectedModifications: 1,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			// The empty node_config rule is listed first; RunAfter must still apply it last.
+			nodeConfigRules := append([]types.Rule{rules.EmptyNodeConfigRuleDefinition, rules.LocalSsdCountRuleDefinition}, rules.KubeletConfigDefaultsRules...)
+			modifier, modifications := applyRulesToFixture(t, tc.fixture, nodeConfigRules)
+			assert.Equal(t, tc.expectedModifications, modifications)
+			assertMatchesGolden(t, modifier, tc.fixture+".golden")
+		})
+	}
+}
+
+func TestApplyRemovedDefaultNodePoolRule(t *testing.T) {
+	tests := []struct {
+		name                  string
+		fixture               string
+		expectedModifications int
+	}{
+		{
+			name:                  "default-pool with remove_default_node_pool = true",
+			fixture:               "testdata/TestApplyRemovedDefaultNodePoolRule_Removed.tf",
+			expectedModifications: 1,
+		},
+		{
+			name:                  "remove_default_node_pool = false",
+			fixture:               "testdata/TestApplyRemovedDefaultNodePoolRule_Kept.tf",
+			expectedModifications: 0,
+		},
+		{
+			name:                  "Other node pool name",
+			fixture:               "testdata/TestApplyRemovedDefaultNodePoolRule_OtherName.tf",
+			expectedModifications: 0,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			modifier, modifications := applyRulesToFixture(t, tc.fixture, []types.Rule{rules.RemovedDefaultNodePoolRuleDefinition})
+			assert.Equal(t, tc.expectedModifications, modifications)
+			assertMatchesGolden(t, modifier, tc.fixture+".golden")
+		})
+	}
+}
+
+func TestApplyAutoProvisioningDefaultsRules(t *testing.T) {
+	tests := []struct {
+		name                  string
+		fixture               string
+		expectedModifications int
+	}{
+		{
+			name:                  "Default values",
+			fixture:               "testdata/TestApplyAutoProvisioningDefaultsRules_Defaults.tf",
+			expectedModifications: 2,
+		},
+		{
+			name:                  "Custom values",
+			fixture:               "testdata/TestApplyAutoProvisioningDefaultsRules_Custom.tf",
+			expectedModifications: 0,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			modifier, modifications := applyRulesToFixture(t, tc.fixture, rules.AutoProvisioningDefaultsRules)
+			assert.Equal(t, tc.expectedModifications, modifications)
+			assertMatchesGolden(t, modifier, tc.fixture+".golden")
+		})
+	}
+}
+
+func TestApplyPlacementPolicyDefaultsRule(t *testing.T) {
+	tests := []struct {
+		name                  string
+		fixture               string
+		expectedModifications int
+	}{
+		{
+			name:                  "Empty type and empty block",
+			fixture:               "testdata/TestApplyPlacementPolicyDefaultsRule_EmptyType.tf",
+			expectedModifications: 2,
+		},
+		{
+			name:                  "COMPACT type",
+			fixture:               "testdata/TestApplyPlacementPolicyDefaultsRule_Compact.tf",
+			expectedModifications: 0,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			modifier, modifications := applyRulesToFixture(t, tc.fixture, []types.Rule{rules.PlacementPolicyDefaultsRuleDefinition})
+			assert.Equal(t, tc.expectedModifications, modifications)
+			assertMatchesGolden(t, modifier, tc.fixture+".golden")
+		})
+	}
+}
@@ -0,0 +1,54 @@
+package hclmodifier
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/kotatut/cluster_import_cleaner/hclmodifier/types"
+)
+
+// orderRules returns inputRules sorted so that every rule comes after the rules named in its RunAfter.
+// The sort is stable: each position gets the first remaining rule whose dependencies have all been placed,
+// so rules without dependencies keep their relative order. Dependencies on names that aren't in inputRules
+// are ignored. It is an error for the dependencies to form a cycle.
+func orderRules(inputRules []types.Rule) ([]types.Rule, error) {
+	remainingByName := make(map[string]int, len(inputRules))
+	for _, rule := range inputRules {
+		remainingByName[rule.Name]++
+	}
+
+	ordered := make([]types.Rule, 0, len(inputRules))
+	placed := make([]bool, len(inputRules))
+	for len(ordered) < len(inputRules) {
+		next := -1
+		for i, rule := range inputRules {
+			if !placed[i] && dependenciesPlaced(rule, remainingByName) {
+				next = i
+				break
+			}
+		}
+		if next == -1 {
+			var blocked []string
+			for i, rule := range inputRules {
+				if !placed[i] {
+					blocked = append(blocked, fmt.Sprintf("'%s'", rule.Name))
+				}
+			}
+			return nil, fmt.Errorf("rule dependencies (RunAfter) form a cycle, cannot order rules %s", strings.Join(blocked, ", "))
+		}
+		placed[next] = true
+		remainingByName[inputRules[next].Name]--
+		ordered = append(ordered, inputRules[next])
+	}
+	return ordered, nil
+}
+
+// dependenciesPlaced reports whether no rule named in rule.RunAfter remains to be placed.
+func dependenciesPlaced(rule types.Rule, remainingByName map[string]int) bool {
+	for _, name := range rule.RunAfter {
+		if remainingByName[name] > 0 {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,266 @@
+package hclmodifier
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/zclconf/go-cty/cty"
+	"go.uber.org/zap"
+
+	"github.com/kotatut/cluster_import_cleaner/hclmodifier/types"
+)
+
+// fixReferencesRuleName is the rule name recorded in warnings returned by FixOrphanedReferences.
+const fixReferencesRuleName = "Orphaned References"
+
+// FixOrphanedReferences looks for attributes that reference resources, data sources or their attributes that
+// were present in original (the file before cleanup) but are gone from the current file.
+//
+// An attribute whose whole value is a reference to a removed resource or data source is removed, since it
+// would make Terraform fail. Any other orphaned reference (inside a larger expression, or to a removed
+// attribute or nested block, which may still be readable as a computed attribute) is only reported.
+// References to things that were not in original at all are ignored, as they are defined elsewhere.
+//
+// Returns the number of attributes removed and one warning per orphaned reference found.
+func (m *Modifier) FixOrphanedReferences(original *Modifier) (int, []types.Warning) {
+	var warnings []types.Warning
+	if m.file == nil || m.file.Body() == nil || original == nil || original.file == nil {
+		return 0, warnings
+	}
+
+	removed := 0
+	for _, block := range m.file.Body().Blocks() {
+		removed += m.fixOrphanedReferencesInBody(block.Body(), nil, original, block.Labels(), &warnings)
+	}
+	return removed, warnings
+}
+
+// fixOrphanedReferencesInBody handles the attributes of body and of its nested blocks, recursively.
+// path holds the types of the nested blocks leading to body, for messages.
+func (m *Modifier) fixOrphanedReferencesInBody(body *hclwrite.Body, path []string, original *Modifier, labels []string, warnings *[]types.Warning) int {
+	removed := 0
+	attributes := body.Attributes()
+	names := make([]string, 0, len(attributes))
+	for name := range attributes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		attr := attributes[name]
+		attributePath := strings.Join(append(append([]string{}, path...), name), ".")
+		expr, diags := hclsyntax.ParseExpression(attr.Expr().BuildTokens(nil).Bytes(), "attribute_expr", hcl.Pos{Line: 1, Column: 1})
+		if diags.HasErrors() {
+			m.Logger.Debug("FixOrphanedReferences: Skipping attribute with unparsable expression.", zap.String("attribute", attributePath), zap.Error(diags))
+			continue
+		}
+
+		for _, traversal := range expr.Variables() {
+			reference, blockMissing, orphaned := m.findOrphanedReference(traversal, original)
+			if !orphaned {
+				continue
+			}
+			if _, isPlainReference := expr.(*hclsyntax.ScopeTraversalExpr); isPlainReference && blockMissing {
+				body.RemoveAttribute(name)
+				removed++
+				m.Logger.Info("FixOrphanedReferences: Removed attribute referencing a removed block.", zap.Strings("blockLabels", labels), zap.String("attribute", attributePath), zap.String("reference", reference))
+				*warnings = append(*warnings, types.Warning{
+					RuleName:       fixReferencesRuleName,
+					ResourceLabels: labels,
+					Message:        fmt.Sprintf("attribute '%s' referenced '%s', which was removed, and was removed too", attributePath, reference),
+				})
+				break
+			}
+			*warnings = append(*warnings, types.Warning{
+				RuleName:       fixReferencesRuleName,
+				ResourceLabels: labels,
+				Message:        fmt.Sprintf("attribute '%s' references '%s', which was removed; review it manually", attributePath, reference),
+			})
+		}
+	}
+
+	for _, block := range body.Blocks() {
+		removed += m.fixOrphanedReferencesInBody(block.Body(), append(append([]string{}, path...), block.Type()), original, labels, warnings)
+	}
+	return removed
+}
+
+// findOrphanedReference checks whether traversal references a resource or data source (or something within one)
+// that exists in original but not in the current file. It returns the reference as written and whether the whole
+// resource or data source block is gone. Other references (variables, locals, modules...) are never orphaned.
+func (m *Modifier) findOrphanedReference(traversal hcl.Traversal, original *Modifier) (reference string, blockMissing bool, orphaned bool) {
+	blockType, labels, rest := splitResourceTraversal(traversal)
+	if blockType == "" {
+		return "", false, false
+	}
+	reference = strings.Join(labels, ".")
+	if blockType == "data" {
+		reference = "data." + reference
+	}
+
+	originalBlock := findTopLevelBlock(original.file.Body(), blockType, labels)
+	if originalBlock == nil {
+		return reference, false, false
+	}
+	currentBlock := findTopLevelBlock(m.file.Body(), blockType, labels)
+	if currentBlock == nil {
+		return reference, true, true
+	}
+
+	// Skip the instance key of resources using count or for_each, e.g. `google_container_cluster.primary[0]`.
+	if len(rest) > 0 {
+		if _, ok := rest[0].(hcl.TraverseIndex); ok {
+			rest = rest[1:]
+		}
+	}
+	for _, step := range rest {
+		attrStep, ok := step.(hcl.TraverseAttr)
+		if !ok {
+			break
+		}
+		reference += "." + attrStep.Name
+	}
+	return reference, false, resolvesInBody(originalBlock.Body(), rest) && !resolvesInBody(currentBlock.Body(), rest)
+}
+
+// splitResourceTraversal splits a traversal such as `google_container_cluster.primary.endpoint` or
+// `data.google_project.current.number` into the block type ("resource" or "data"), the block labels and the
+// remaining steps. blockType is empty if the traversal doesn't reference a resource or data source.
+func splitResourceTraversal(traversal hcl.Traversal) (blockType string, labels []string, rest hcl.Traversal) {
+	rootName := traversal.RootName()
+	switch rootName {
+	case "var", "local", "module", "path", "terraform", "count", "each", "self":
+		return "", nil, nil
+	}
+
+	blockType, labelSteps := "resource", 1
+	if rootName == "data" {
+		blockType, labelSteps = "data", 2
+	} else {
+		labels = []string{rootName}
+	}
+	if len(traversal) < labelSteps+1 {
+		return "", nil, nil
+	}
+	for _, step := range traversal[1 : labelSteps+1] {
+		attrStep, ok := step.(hcl.TraverseAttr)
+		if !ok {
+			return "", nil, nil
+		}
+		labels = append(labels, attrStep.Name)
+	}
+	return blockType, labels, traversal[labelSteps+1:]
+}
+
+// findTopLevelBlock returns the first top-level block of blockType with exactly labels, or nil.
+func findTopLevelBlock(body *hclwrite.Body, blockType string, labels []string) *hclwrite.Block {
+	for _, block := range body.Blocks() {
+		if block.Type() == blockType && strings.Join(block.Labels(), "\x00") == strings.Join(labels, "\x00") {
+			return block
+		}
+	}
+	return nil
+}
+
+// resolvesInBody reports whether steps lead to an attribute or nested block present in body. Attribute steps
+// descend into nested blocks of that type (the one selected by a following index step, or the first); the
+// walk stops successfully at the first attribute found, since anything after it is inside its value.
+func resolvesInBody(body *hclwrite.Body, steps hcl.Traversal) bool {
+	for i := 0; i < len(steps); i++ {
+		attrStep, ok := steps[i].(hcl.TraverseAttr)
+		if !ok {
+			return true
+		}
+		if body.GetAttribute(attrStep.Name) != nil {
+			return true
+		}
+
+		var matching []*hclwrite.Block
+		for _, block := range body.Blocks() {
+			if block.Type() == attrStep.Name {
+				matching = append(matching, block)
+			}
+		}
+		if len(matching) == 0 {
+			return false
+		}
+
+		index := 0
+		if i+1 < len(steps) {
+			if indexStep, ok := steps[i+1].(hcl.TraverseIndex); ok && indexStep.Key.Type().Equals(cty.Number) {
+				bigIndex, _ := indexStep.Key.AsBigFloat().Int64()
+				index = int(bigIndex)
+				i++
+			}
+		}
+		if index < 0 || index >= len(matching) {
+			return false
+		}
+		body = matching[index].Body()
+	}
+	return true
+}
+
+// evalContext builds an EvalContext that exposes the literal attributes of the file's resources (as
+// `<type>.<name>.<attribute>`), data sources (`data.<type>.<name>.<attribute>`) and locals (`local.<name>`).
+// Attributes that aren't literals themselves, nested blocks, variables and module outputs aren't included,
+// so references to them still fail to evaluate.
+func (m *Modifier) evalContext() *hcl.EvalContext {
+	variables := make(map[string]cty.Value)
+	if m.file == nil || m.file.Body() == nil {
+		return &hcl.EvalContext{Variables: variables}
+	}
+
+	resources := make(map[string]map[string]cty.Value)
+	dataSources := make(map[string]map[string]cty.Value)
+	locals := make(map[string]cty.Value)
+	for _, block := range m.file.Body().Blocks() {
+		switch {
+		case block.Type() == "resource" && len(block.Labels()) == 2:
+			addLabeledObject(resources, block.Labels(), m.literalAttributes(block.Body()))
+		case block.Type() == "data" && len(block.Labels()) == 2:
+			addLabeledObject(dataSources, block.Labels(), m.literalAttributes(block.Body()))
+		case block.Type() == "locals":
+			for name, value := range m.literalAttributes(block.Body()) {
+				locals[name] = value
+			}
+		}
+	}
+
+	for resourceType, byName := range resources {
+		variables[resourceType] = cty.ObjectVal(byName)
+	}
+	if len(dataSources) > 0 {
+		byType := make(map[string]cty.Value, len(dataSources))
+		for dataType, byName := range dataSources {
+			byType[dataType] = cty.ObjectVal(byName)
+		}
+		variables["data"] = cty.ObjectVal(byType)
+	}
+	if len(locals) > 0 {
+		variables["local"] = cty.ObjectVal(locals)
+	}
+	return &hcl.EvalContext{Variables: variables}
+}
+
+// literalAttributes returns the values of the attributes of body that are literals.
+func (m *Modifier) literalAttributes(body *hclwrite.Body) map[string]cty.Value {
+	values := make(map[string]cty.Value)
+	for name, attr := range body.Attributes() {
+		if value, err := m.evaluateAttribute(attr, nil); err == nil {
+			values[name] = value
+		}
+	}
+	return values
+}
+
+// addLabeledObject stores the object made of attributes under objects[labels[0]][labels[1]].
+func addLabeledObject(objects map[string]map[string]cty.Value, labels []string, attributes map[string]cty.Value) {
+	if objects[labels[0]] == nil {
+		objects[labels[0]] = make(map[string]cty.Value)
+	}
+	objects[labels[0]][labels[1]] = cty.ObjectVal(attributes)
+}
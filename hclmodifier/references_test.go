@@ -0,0 +1,146 @@
+package hclmodifier
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/kotatut/cluster_import_cleaner/hclmodifier/rules"
+	"github.com/kotatut/cluster_import_cleaner/hclmodifier/types"
+)
+
+func TestFixOrphanedReferences(t *testing.T) {
+	t.Run("Removed resource and attribute", func(t *testing.T) {
+		fixture := "testdata/TestFixOrphanedReferences_RemovedResource.tf"
+		modifier, _ := applyRulesToFixture(t, fixture, nil)
+		original := modifier.Clone()
+		_, errs := modifier.ApplyRules(rules.TopLevelComputedAttributesRules)
+		assert.Empty(t, errs)
+		assert.Equal(t, 1, modifier.RemoveEmptyResources("google_container_cluster", []string{"name", "location"}))
+
+		removed, warnings := modifier.FixOrphanedReferences(original)
+		assert.Equal(t, 1, removed)
+		assert.Equal(t, []types.Warning{
+			{
+				RuleName:       fixReferencesRuleName,
+				ResourceLabels: []string{"google_container_node_pool", "pool"},
+				Message:        "attribute 'cluster' referenced 'google_container_cluster.empty', which was removed, and was removed too",
+			},
+			{
+				RuleName:       fixReferencesRuleName,
+				ResourceLabels: []string{"google_container_node_pool", "pool"},
+				Message:        "attribute 'node_config.labels' references 'google_container_cluster.empty', which was removed; review it manually",
+			},
+			{
+				RuleName:       fixReferencesRuleName,
+				ResourceLabels: []string{"endpoint"},
+				Message:        "attribute 'value' references 'google_container_cluster.primary.endpoint', which was removed; review it manually",
+			},
+		}, warnings)
+		assertMatchesGolden(t, modifier, fixture+".golden")
+	})
+
+	t.Run("Nothing removed", func(t *testing.T) {
+		modifier := newTestModifier(t, `resource "google_container_node_pool" "pool" {
+  cluster = google_container_cluster.primary.name
+}
+
+resource "google_container_cluster" "primary" {
+  name = "primary"
+}`)
+		removed, warnings := modifier.FixOrphanedReferences(modifier.Clone())
+		assert.Equal(t, 0, removed)
+		assert.Empty(t, warnings)
+	})
+}
+
+func TestResolveReferences(t *testing.T) {
+	const hclContent = `resource "google_compute_network" "main" {
+  name      = "main"
+  self_link = "projects/p/global/networks/main"
+}
+
+data "google_project" "current" {
+  number = 1234
+}
+
+locals {
+  location = "us-central1"
+}
+
+resource "google_container_cluster" "primary" {
+  network    = google_compute_network.main.self_link
+  project    = data.google_project.current.number
+  location   = local.location
+  subnetwork = var.subnetwork
+  node_locations = [google_compute_network.main.id]
+}`
+
+	t.Run("Off by default", func(t *testing.T) {
+		modifier := newTestModifier(t, hclContent)
+		_, _, err := modifier.GetAttributeValueByPath(modifier.File().Body().Blocks()[3].Body(), []string{"network"})
+		assert.Error(t, err)
+	})
+
+	t.Run("Literal references resolve", func(t *testing.T) {
+		modifier := newTestModifier(t, hclContent)
+		modifier.ResolveReferences = true
+		body := modifier.File().Body().Blocks()[3].Body()
+
+		for attributeName, expected := range map[string]cty.Value{
+			"network":  cty.StringVal("projects/p/global/networks/main"),
+			"project":  cty.NumberIntVal(1234),
+			"location": cty.StringVal("us-central1"),
+		} {
+			value, _, err := modifier.GetAttributeValueByPath(body, []string{attributeName})
+			if assert.NoError(t, err, attributeName) {
+				assert.True(t, value.RawEquals(expected), "%s: got %#v", attributeName, value)
+			}
+		}
+	})
+
+	t.Run("Dynamic references still fail", func(t *testing.T) {
+		modifier := newTestModifier(t, hclContent)
+		modifier.ResolveReferences = true
+		body := modifier.File().Body().Blocks()[3].Body()
+
+		_, _, err := modifier.GetAttributeValueByPath(body, []string{"subnetwork"})
+		assert.Error(t, err, "var. references aren't resolvable")
+		_, _, err = modifier.GetAttributeValueByPath(body, []string{"node_locations"})
+		assert.Error(t, err, "attributes that aren't in the file aren't resolvable")
+	})
+
+	t.Run("Conditions see resolved values", func(t *testing.T) {
+		modifier := newTestModifier(t, hclContent)
+		modifier.ResolveReferences = true
+		modifications, errs := modifier.ApplyRules([]types.Rule{
+			{
+				Name:               "Remove default-network location",
+				TargetResourceType: "google_container_cluster",
+				Conditions: []types.RuleCondition{
+					{Type: types.AttributeValueEquals, Path: []string{"location"}, ExpectedValue: "us-central1"},
+				},
+				Actions: []types.RuleAction{{Type: types.RemoveAttribute, Path: []string{"location"}}},
+			},
+		})
+		assert.Empty(t, errs)
+		assert.Equal(t, 1, modifications)
+	})
+
+	t.Run("Actions keep references", func(t *testing.T) {
+		modifier := newTestModifier(t, hclContent)
+		modifier.ResolveReferences = true
+		modifications, _ := modifier.ApplyRules([]types.Rule{
+			{
+				Name:               "Rewrite network",
+				TargetResourceType: "google_container_cluster",
+				Actions: []types.RuleAction{
+					{Type: types.RewriteAttributeWithRegex, Path: []string{"network"}, Pattern: "^projects/p/", Replacement: ""},
+				},
+			},
+		})
+		assert.Equal(t, 0, modifications)
+		assert.Contains(t, string(modifier.File().Bytes()), "google_compute_network.main.self_link\n")
+	})
+}
@@ -0,0 +1,83 @@
+package hclmodifier
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/kotatut/cluster_import_cleaner/hclmodifier/rules"
+	"github.com/kotatut/cluster_import_cleaner/hclmodifier/types"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+// TestRemoveBlockWhenAttrEqualsRules covers the rules built by the shared remove-block-when-attribute-equals helper:
+// each one must keep the "<Area> Rule: " naming, remove its block when disabled and keep it otherwise.
+func TestRemoveBlockWhenAttrEqualsRules(t *testing.T) {
+	tests := []struct {
+		name     string
+		rule     types.Rule
+		disabled string
+		enabled  string
+		block    []string
+	}{
+		{
+			name:     "Default SNAT status",
+			rule:     rules.DefaultSnatStatusRuleDefinition,
+			disabled: "default_snat_status {\n    disabled = false\n  }",
+			enabled:  "default_snat_status {\n    disabled = true\n  }",
+			block:    []string{"default_snat_status"},
+		},
+		{
+			name:     "Gateway API config",
+			rule:     rules.GatewayAPIConfigRuleDefinition,
+			disabled: "gateway_api_config {\n    channel = \"CHANNEL_DISABLED\"\n  }",
+			enabled:  "gateway_api_config {\n    channel = \"CHANNEL_STANDARD\"\n  }",
+			block:    []string{"gateway_api_config"},
+		},
+		{
+			name:     "Vertical Pod Autoscaling",
+			rule:     rules.VerticalPodAutoscalingRuleDefinition,
+			disabled: "vertical_pod_autoscaling {\n    enabled = false\n  }",
+			enabled:  "vertical_pod_autoscaling {\n    enabled = true\n  }",
+			block:    []string{"vertical_pod_autoscaling"},
+		},
+		{
+			name:     "Identity Service config",
+			rule:     rules.IdentityServiceConfigRuleDefinition,
+			disabled: "identity_service_config {\n    enabled = false\n  }",
+			enabled:  "identity_service_config {\n    enabled = true\n  }",
+			block:    []string{"identity_service_config"},
+		},
+		{
+			name:     "Managed Prometheus",
+			rule:     rules.ManagedPrometheusDisabledRule,
+			disabled: "monitoring_config {\n    managed_prometheus {\n      enabled = false\n    }\n  }",
+			enabled:  "monitoring_config {\n    managed_prometheus {\n      enabled = true\n    }\n  }",
+			block:    []string{"monitoring_config", "managed_prometheus"},
+		},
+	}
+
+	namePattern := regexp.MustCompile(`^[A-Z][A-Za-z ]+ Rule: `)
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Regexp(t, namePattern, tc.rule.Name)
+			assert.Empty(t, rules.Validate([]types.Rule{tc.rule}))
+
+			hclContent := "resource \"google_container_cluster\" \"disabled\" {\n  " + tc.disabled + "\n}\n\n" +
+				"resource \"google_container_cluster\" \"enabled\" {\n  " + tc.enabled + "\n}\n"
+			modifier, err := NewFromBytes([]byte(hclContent), "test.tf", zap.NewNop())
+			if err != nil {
+				t.Fatalf("Failed to parse HCL: %v", err)
+			}
+			modifications, errs := modifier.ApplyRules([]types.Rule{tc.rule})
+			assert.Empty(t, errs)
+			assert.Equal(t, 1, modifications)
+
+			blocks := modifier.File().Body().Blocks()
+			_, err = modifier.GetNestedBlock(blocks[0].Body(), tc.block)
+			assert.Error(t, err, "The disabled block must be removed")
+			_, err = modifier.GetNestedBlock(blocks[1].Body(), tc.block)
+			assert.NoError(t, err, "The enabled block must be kept")
+		})
+	}
+}
@@ -0,0 +1,36 @@
+package rules
+
+import (
+	"github.com/kotatut/cluster_import_cleaner/hclmodifier/types"
+)
+
+// GuestAcceleratorComputedFieldsRuleDefinition defines a rule that cleans computed fields from every
+// `node_config.guest_accelerator` block of each `node_pool` in a `google_container_cluster` resource.
+//
+// What it does: For each `node_pool` with at least one `guest_accelerator` block, it removes the
+// `gpu_partition_size` attribute and any empty `gpu_sharing_config` block from all accelerator blocks.
+//
+// Why it's necessary for GKE imports: Imported node pools with GPUs often carry a computed
+// `gpu_partition_size` and an empty `gpu_sharing_config {}` that Terraform rejects on apply.
+var GuestAcceleratorComputedFieldsRuleDefinition = types.Rule{
+	Name:                  "Guest Accelerator Rule: Remove computed gpu_partition_size and empty gpu_sharing_config from node_pool accelerators",
+	TargetResourceType:    "google_container_cluster",
+	ExecutionType:         types.RuleExecutionForEachNestedBlock,
+	NestedBlockTargetType: "node_pool",
+	Conditions: []types.RuleCondition{
+		{
+			Type: types.BlockExists,
+			Path: []string{"node_config", "guest_accelerator"},
+		},
+	},
+	Actions: []types.RuleAction{
+		{
+			Type: types.RemoveAttributeFromAllMatchingBlocks,
+			Path: []string{"node_config", "guest_accelerator", "gpu_partition_size"},
+		},
+		{
+			Type: types.RemoveEmptyBlocksMatchingPath,
+			Path: []string{"node_config", "guest_accelerator", "gpu_sharing_config"},
+		},
+	},
+}
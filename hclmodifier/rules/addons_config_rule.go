@@ -0,0 +1,30 @@
+package rules
+
+import (
+	"github.com/kotatut/cluster_import_cleaner/hclmodifier/types"
+)
+
+// DuplicateAddonsConfigRuleDefinition defines a rule that removes duplicate sub-blocks from the `addons_config` block
+// of `google_container_cluster` resources.
+//
+// What it does: For each sub-block type within `addons_config` (e.g. `http_load_balancing`), the first block is kept
+// and any later block of the same type is removed.
+//
+// Why it's necessary for GKE imports: malformed imports occasionally repeat a sub-block such as `http_load_balancing`,
+// but GKE accepts at most one of each.
+var DuplicateAddonsConfigRuleDefinition = types.Rule{
+	Name:               "Addons Config Rule: Remove duplicate sub-blocks from addons_config",
+	TargetResourceType: "google_container_cluster",
+	Conditions: []types.RuleCondition{
+		{
+			Type: types.BlockExists,
+			Path: []string{"addons_config"},
+		},
+	},
+	Actions: []types.RuleAction{
+		{
+			Type: types.RemoveDuplicateNestedBlocks,
+			Path: []string{"addons_config"},
+		},
+	},
+}
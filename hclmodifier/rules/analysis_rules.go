@@ -0,0 +1,70 @@
+package rules
+
+import (
+	"github.com/kotatut/cluster_import_cleaner/hclmodifier/types"
+)
+
+// zonalLocationPattern matches GCP zone names such as `us-central1-a`, as opposed to region names such as `us-central1`.
+const zonalLocationPattern = `^[a-z]+-[a-z]+[0-9]+-[a-z]$`
+
+// ZonalLocationMultiZoneNodeLocationsRule defines a reporting-only rule that never modifies the file.
+//
+// What it does: It warns when a `google_container_cluster` resource has a zonal `location`
+// (e.g. `us-central1-a`) while `node_locations` lists more than one zone.
+//
+// Why it's necessary for GKE imports: A zonal control plane with nodes spread over several zones is
+// valid but unusual, and is often the result of an intended regional cluster being declared with a zone.
+// Changing `location` recreates the cluster, so the tool only points it out.
+var ZonalLocationMultiZoneNodeLocationsRule = types.Rule{
+	Name:               "Location Analysis: Warn when a zonal location is combined with multi-zone node_locations",
+	TargetResourceType: "google_container_cluster",
+	Conditions: []types.RuleCondition{
+		{
+			Type:          types.AttributeValueMatches,
+			Path:          []string{"location"},
+			ExpectedValue: zonalLocationPattern,
+		},
+		{
+			Type:          types.ListLengthGreaterThan,
+			Path:          []string{"node_locations"},
+			ExpectedValue: "1",
+		},
+	},
+	Actions: []types.RuleAction{
+		{
+			Type:    types.ReportWarning,
+			Message: "location is a zone but node_locations spans multiple zones; check whether a regional location was intended",
+		},
+	},
+}
+
+// KubernetesAlphaClusterRule defines a reporting-only rule that never modifies the file.
+//
+// What it does: It warns when a `google_container_cluster` resource sets `enable_kubernetes_alpha = true`.
+// `enable_kubernetes_alpha = false` is removed by RemovedFeatureFlagsRules instead.
+//
+// Why it's necessary for GKE imports: Alpha clusters are deleted automatically after 30 days and can't be upgraded,
+// so an imported alpha cluster is rarely something to keep managing with Terraform.
+var KubernetesAlphaClusterRule = types.Rule{
+	Name:               "Kubernetes Alpha Analysis: Warn about alpha clusters",
+	TargetResourceType: "google_container_cluster",
+	Conditions: []types.RuleCondition{
+		{
+			Type:          types.AttributeValueEquals,
+			Path:          []string{"enable_kubernetes_alpha"},
+			ExpectedValue: "true",
+		},
+	},
+	Actions: []types.RuleAction{
+		{
+			Type:    types.ReportWarning,
+			Message: "enable_kubernetes_alpha is true; alpha clusters can't be upgraded and are deleted after 30 days",
+		},
+	},
+}
+
+// AnalysisRules groups the reporting-only rules. They only raise warnings and never change the file.
+var AnalysisRules = []types.Rule{
+	ZonalLocationMultiZoneNodeLocationsRule,
+	KubernetesAlphaClusterRule,
+}
@@ -0,0 +1,121 @@
+package rules
+
+import (
+	"github.com/kotatut/cluster_import_cleaner/hclmodifier/types"
+)
+
+// MasterAuthClientCertificateRules define rules that clean up the default client certificate configuration
+// in the `master_auth` block of a `google_container_cluster` resource. They must be applied in order.
+//
+// What they do:
+//  1. Remove `master_auth.client_certificate_config.issue_client_certificate` when it is `false`.
+//  2. Remove the `client_certificate_config` block if that left it empty.
+//  3. Remove the `master_auth` block if that left it empty. A `master_auth` block that still holds
+//     anything else, such as `cluster_ca_certificate`, is kept.
+//
+// Why it's necessary for GKE imports: imported clusters get
+// `master_auth { client_certificate_config { issue_client_certificate = false } }`, which is the default
+// and only clutters the configuration.
+var MasterAuthClientCertificateRules = []types.Rule{
+	{
+		Name:               "Master Auth Rule: Remove default issue_client_certificate = false",
+		TargetResourceType: "google_container_cluster",
+		Conditions: []types.RuleCondition{
+			{
+				Type:          types.AttributeValueEquals,
+				Path:          []string{"master_auth", "client_certificate_config", "issue_client_certificate"},
+				ExpectedValue: "false",
+			},
+		},
+		Actions: []types.RuleAction{
+			{
+				Type: types.RemoveAttribute,
+				Path: []string{"master_auth", "client_certificate_config", "issue_client_certificate"},
+			},
+		},
+	},
+	{
+		Name:               "Master Auth Rule: Remove empty client_certificate_config",
+		TargetResourceType: "google_container_cluster",
+		Conditions: []types.RuleCondition{
+			{
+				Type: types.BlockIsEmpty,
+				Path: []string{"master_auth", "client_certificate_config"},
+			},
+		},
+		Actions: []types.RuleAction{
+			{Type: types.RemoveBlock, Path: []string{"master_auth", "client_certificate_config"}},
+		},
+	},
+	{
+		Name:               "Master Auth Rule: Remove empty master_auth",
+		TargetResourceType: "google_container_cluster",
+		Conditions: []types.RuleCondition{
+			{
+				Type: types.BlockIsEmpty,
+				Path: []string{"master_auth"},
+			},
+		},
+		Actions: []types.RuleAction{
+			{Type: types.RemoveBlock, Path: []string{"master_auth"}},
+		},
+	},
+}
+
+// RuleHandleLegacyAbacFalse defines a rule to clean up 'enable_legacy_abac'
+// when it is explicitly set to false.
+//
+// What it does: It checks if a 'google_container_cluster' resource has the
+// 'enable_legacy_abac' attribute set to 'false'. If so, it removes the attribute.
+// A value of 'true' is a real, non-default setting and is kept.
+//
+// Why it's necessary for GKE imports: imported clusters always get
+// 'enable_legacy_abac = false', which is the default when the attribute is omitted.
+var RuleHandleLegacyAbacFalse = types.Rule{
+	Name:               "Legacy ABAC Cleanup: Remove 'enable_legacy_abac' if explicitly set to false",
+	TargetResourceType: "google_container_cluster",
+	Conditions: []types.RuleCondition{
+		{
+			Type: types.AttributeExists,
+			Path: []string{"enable_legacy_abac"},
+		},
+		{
+			Type:          types.AttributeValueEquals,
+			Path:          []string{"enable_legacy_abac"},
+			ExpectedValue: "false",
+		},
+	},
+	Actions: []types.RuleAction{
+		{
+			Type: types.RemoveAttribute,
+			Path: []string{"enable_legacy_abac"},
+		},
+	},
+}
+
+// EmptyMasterAuthorizedNetworksConfigRuleDefinition defines a rule that removes the `master_authorized_networks_config`
+// block from `google_container_cluster` resources when it is effectively empty.
+//
+// What it does: If `master_authorized_networks_config` has no attributes, such as `gcp_public_cidrs_access_enabled`,
+// and no non-empty `cidr_blocks` sub-blocks, the whole block is removed.
+//
+// Why it's necessary for GKE imports: imports sometimes yield `master_authorized_networks_config {}`, which only adds
+// noise. However, the provider reads an empty block as authorized networks enabled without any external CIDR, and
+// removing it disables authorized networks, opening the control plane endpoint to any address. The rule is therefore
+// not part of the default set; see EmptyMasterAuthorizedNetworksConfigRegisteredRule.
+var EmptyMasterAuthorizedNetworksConfigRuleDefinition = types.Rule{
+	Name:               "Master Authorized Networks Rule: Remove master_authorized_networks_config if it is empty",
+	TargetResourceType: "google_container_cluster",
+	Conditions: []types.RuleCondition{
+		{
+			Type: types.BlockIsEmpty,
+			Path: []string{"master_authorized_networks_config"},
+		},
+	},
+	Actions: []types.RuleAction{
+		{
+			Type: types.RemoveBlock,
+			Path: []string{"master_authorized_networks_config"},
+		},
+	},
+}
@@ -0,0 +1,60 @@
+package rules
+
+import (
+	"github.com/kotatut/cluster_import_cleaner/hclmodifier/types"
+)
+
+// gkeDefaultOauthScopes are the scopes GKE gives nodes when none are set, known to gcloud as `gke-default`.
+var gkeDefaultOauthScopes = []string{
+	"https://www.googleapis.com/auth/devstorage.read_only",
+	"https://www.googleapis.com/auth/logging.write",
+	"https://www.googleapis.com/auth/monitoring",
+	"https://www.googleapis.com/auth/service.management.readonly",
+	"https://www.googleapis.com/auth/servicecontrol",
+	"https://www.googleapis.com/auth/trace.append",
+}
+
+// AutoProvisioningDefaultsRules remove the values GKE computes for `cluster_autoscaling.auto_provisioning_defaults`
+// of `google_container_cluster` resources.
+//
+// What they do: `oauth_scopes` is removed when it lists exactly the default GKE scopes, in any order, and
+// `service_account` is removed when it is empty or `default`. Custom scopes and service accounts are kept.
+//
+// Why it's necessary for GKE imports: imports write the scopes and service account that auto-provisioned node pools
+// get when none are configured. They are the same values GKE uses when the attributes are omitted.
+var AutoProvisioningDefaultsRules = []types.Rule{
+	{
+		Name:               "Auto Provisioning Defaults Rule: Remove default oauth_scopes",
+		TargetResourceType: "google_container_cluster",
+		Conditions: []types.RuleCondition{
+			{
+				Type:           types.ListValuesEqual,
+				Path:           []string{"cluster_autoscaling", "auto_provisioning_defaults", "oauth_scopes"},
+				ExpectedValues: gkeDefaultOauthScopes,
+			},
+		},
+		Actions: []types.RuleAction{
+			{
+				Type: types.RemoveAttribute,
+				Path: []string{"cluster_autoscaling", "auto_provisioning_defaults", "oauth_scopes"},
+			},
+		},
+	},
+	{
+		Name:               "Auto Provisioning Defaults Rule: Remove empty or default service_account",
+		TargetResourceType: "google_container_cluster",
+		Conditions: []types.RuleCondition{
+			{
+				Type:           types.AttributeValueIn,
+				Path:           []string{"cluster_autoscaling", "auto_provisioning_defaults", "service_account"},
+				ExpectedValues: []string{"", "default"},
+			},
+		},
+		Actions: []types.RuleAction{
+			{
+				Type: types.RemoveAttribute,
+				Path: []string{"cluster_autoscaling", "auto_provisioning_defaults", "service_account"},
+			},
+		},
+	},
+}
@@ -74,4 +74,24 @@ var AutopilotRules = []types.Rule{
 			{Type: types.RemoveAttribute, Path: []string{"binary_authorization", "enabled"}},
 		},
 	},
+	{
+		// Autopilot clusters are always VPC-native, so an empty ip_allocation_policy adds nothing.
+		// The IP allocation rules run before this one, so a block they have emptied is removed too.
+		Name:               "Autopilot Cleanup: Remove empty ip_allocation_policy when enable_autopilot = true",
+		TargetResourceType: "google_container_cluster",
+		Conditions: []types.RuleCondition{
+			{
+				Type:          types.AttributeValueEquals,
+				Path:          []string{"enable_autopilot"},
+				ExpectedValue: "true",
+			},
+			{
+				Type: types.BlockIsEmpty,
+				Path: []string{"ip_allocation_policy"},
+			},
+		},
+		Actions: []types.RuleAction{
+			{Type: types.RemoveBlock, Path: []string{"ip_allocation_policy"}},
+		},
+	},
 }
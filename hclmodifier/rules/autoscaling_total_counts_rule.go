@@ -0,0 +1,45 @@
+package rules
+
+import (
+	"github.com/kotatut/cluster_import_cleaner/hclmodifier/types"
+)
+
+// ZonalTotalNodeCountsRuleDefinition defines a rule that removes the `total_min_node_count` and
+// `total_max_node_count` attributes from the `autoscaling` block of every `node_pool` in a zonal
+// `google_container_cluster` resource.
+//
+// What it does: For clusters whose `location` is a zone (e.g. `us-central1-a`), it removes both total
+// counts from each `node_pool.autoscaling` block. Regional clusters are left unchanged.
+//
+// Why it's necessary for GKE imports: The import writes the total counts back next to the per-zone
+// `min_node_count`/`max_node_count`. For zonal clusters the total form is invalid there, while for
+// regional clusters it is a valid way to size the pool across zones.
+var ZonalTotalNodeCountsRuleDefinition = types.Rule{
+	Name:                  "Autoscaling Total Counts Rule: Remove node_pool autoscaling total_*_node_count for zonal clusters",
+	TargetResourceType:    "google_container_cluster",
+	ExecutionType:         types.RuleExecutionForEachNestedBlock,
+	NestedBlockTargetType: "node_pool",
+	ResourceConditions: []types.RuleCondition{
+		{
+			Type:          types.AttributeValueMatches,
+			Path:          []string{"location"},
+			ExpectedValue: zonalLocationPattern,
+		},
+	},
+	Conditions: []types.RuleCondition{
+		{
+			Type: types.BlockExists,
+			Path: []string{"autoscaling"},
+		},
+	},
+	Actions: []types.RuleAction{
+		{
+			Type: types.RemoveAttribute,
+			Path: []string{"autoscaling", "total_min_node_count"},
+		},
+		{
+			Type: types.RemoveAttribute,
+			Path: []string{"autoscaling", "total_max_node_count"},
+		},
+	},
+}
@@ -0,0 +1,37 @@
+package rules
+
+import (
+	"github.com/kotatut/cluster_import_cleaner/hclmodifier/types"
+)
+
+// DisabledClusterAutoscalingRuleDefinition defines a rule that removes the whole `cluster_autoscaling` block
+// of a `google_container_cluster` resource when node auto-provisioning is disabled.
+//
+// What it does: If `cluster_autoscaling.enabled` is `false` and no `autoscaling_profile` is set, the
+// `cluster_autoscaling` block is removed, together with any `auto_provisioning_defaults` or `resource_limits`
+// it holds, as they only apply when auto-provisioning is enabled.
+//
+// Why it's necessary for GKE imports: `terraform import` writes `cluster_autoscaling { enabled = false }`
+// (often with empty defaults) for every cluster without auto-provisioning, which is the default.
+// An `autoscaling_profile` applies to node pool autoscaling too, so blocks setting one are kept.
+var DisabledClusterAutoscalingRuleDefinition = types.Rule{
+	Name:               "Cluster Autoscaling Rule: Remove cluster_autoscaling block when enabled = false",
+	TargetResourceType: "google_container_cluster",
+	Conditions: []types.RuleCondition{
+		{
+			Type:          types.AttributeValueEquals,
+			Path:          []string{"cluster_autoscaling", "enabled"},
+			ExpectedValue: "false",
+		},
+		{
+			Type: types.AttributeDoesntExist,
+			Path: []string{"cluster_autoscaling", "autoscaling_profile"},
+		},
+	},
+	Actions: []types.RuleAction{
+		{
+			Type: types.RemoveBlock,
+			Path: []string{"cluster_autoscaling"},
+		},
+	},
+}
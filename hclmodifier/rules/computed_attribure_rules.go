@@ -18,14 +18,31 @@ var TopLevelComputedAttributesRules = []types.Rule{
 	createRemoveAttributeRule("google_container_cluster", []string{"self_link"}),
 }
 
+// StatusComputedAttributesRules removes read-only operation/status attributes such as 'operation' or 'master_version'.
+//
+// Why it's necessary for GKE imports: these attributes are reported by GCP after import and fail on apply when set.
+var StatusComputedAttributesRules = []types.Rule{
+	createRemoveAttributeRule("google_container_cluster", []string{"operation"}),
+	createRemoveAttributeRule("google_container_cluster", []string{"services_ipv4_cidr"}),
+	createRemoveAttributeRule("google_container_cluster", []string{"tpu_ipv4_cidr_block"}),
+	createRemoveAttributeRule("google_container_cluster", []string{"master_version"}),
+}
+
+// FleetComputedAttributesRules removes the attributes of the `fleet` block that GKE computes when the cluster is
+// registered to a fleet, such as 'membership'. The user-set 'project' is kept.
+//
+// Why it's necessary for GKE imports: these attributes are output only and are reported as a diff when set.
+var FleetComputedAttributesRules = []types.Rule{
+	createRemoveAttributeRule("google_container_cluster", []string{"fleet", "membership"}),
+	createRemoveAttributeRule("google_container_cluster", []string{"fleet", "membership_id"}),
+	createRemoveAttributeRule("google_container_cluster", []string{"fleet", "membership_location"}),
+	createRemoveAttributeRule("google_container_cluster", []string{"fleet", "pre_registered"}),
+}
+
 var OtherComputedAttributesRules = []types.Rule{
-	createRemoveAttributeRule("google_container_cluster", []string{"label_fingerprint"}),
-	createRemoveAttributeRule("google_container_cluster", []string{"self_link"}),
 	createRemoveAttributeRule("google_container_cluster", []string{"master_auth", "cluster_ca_certificate"}),
 	createRemoveAttributeInAllBlocksRule("google_container_cluster", "node_pool", []string{"instance_group_urls"}),
 	createRemoveAttributeInAllBlocksRule("google_container_cluster", "node_pool", []string{"managed_instance_group_urls"}),
-	createRemoveAttributeInAllBlocksRule("google_container_cluster", "node_pool", []string{"autoscaling", "total_max_node_count"}), // Will be handled by dedicated rule
-	createRemoveAttributeInAllBlocksRule("google_container_cluster", "node_pool", []string{"autoscaling", "total_min_node_count"}), // Will be handled by dedicated rule
 	createRemoveAttributeRule("google_container_cluster", []string{"private_cluster_config", "private_endpoint"}),
 	createRemoveAttributeRule("google_container_cluster", []string{"private_cluster_config", "public_endpoint"}),
 	createRemoveAttributeRule("google_container_cluster", []string{"control_plane_endpoints_config", "dns_endpoint_config", "endpoint"}),
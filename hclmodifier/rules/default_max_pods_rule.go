@@ -0,0 +1,34 @@
+package rules
+
+import (
+	"github.com/kotatut/cluster_import_cleaner/hclmodifier/types"
+)
+
+// DefaultMaxPodsPerNodeRuleDefinition defines a rule that removes `default_max_pods_per_node` from
+// `google_container_cluster` resources that have no `ip_allocation_policy` block.
+//
+// What it does: If `default_max_pods_per_node` is set and `ip_allocation_policy` is absent, the attribute is removed.
+// It is kept for VPC-native clusters, which have an `ip_allocation_policy` block.
+//
+// Why it's necessary for GKE imports: `default_max_pods_per_node` is only valid for VPC-native clusters. Routes-based
+// clusters may still get it on import, and applying it then fails.
+var DefaultMaxPodsPerNodeRuleDefinition = types.Rule{
+	Name:               "Default Max Pods Per Node Rule: Remove default_max_pods_per_node if ip_allocation_policy doesn't exist",
+	TargetResourceType: "google_container_cluster",
+	Conditions: []types.RuleCondition{
+		{
+			Type: types.AttributeExists,
+			Path: []string{"default_max_pods_per_node"},
+		},
+		{
+			Type: types.BlockDoesntExist,
+			Path: []string{"ip_allocation_policy"},
+		},
+	},
+	Actions: []types.RuleAction{
+		{
+			Type: types.RemoveAttribute,
+			Path: []string{"default_max_pods_per_node"},
+		},
+	},
+}
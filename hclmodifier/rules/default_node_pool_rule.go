@@ -0,0 +1,39 @@
+package rules
+
+import (
+	"github.com/kotatut/cluster_import_cleaner/hclmodifier/types"
+)
+
+// RemovedDefaultNodePoolRuleDefinition defines a rule that removes the inline `node_pool` named `default-pool` from
+// `google_container_cluster` resources that set `remove_default_node_pool = true`.
+//
+// What it does: If `remove_default_node_pool` is `true`, every `node_pool` block whose `name` is `default-pool` is
+// removed. Other node pools, and clusters that keep their default pool, are left unchanged.
+//
+// Why it's necessary for GKE imports: the provider creates `default-pool` and deletes it right away when
+// `remove_default_node_pool` is set. An import taken before the deletion finished still lists it, and declaring it
+// conflicts with the removal.
+var RemovedDefaultNodePoolRuleDefinition = types.Rule{
+	Name:               "Default Node Pool Rule: Remove the default-pool node_pool when remove_default_node_pool is true",
+	TargetResourceType: "google_container_cluster",
+	Conditions: []types.RuleCondition{
+		{
+			Type:          types.AttributeValueEquals,
+			Path:          []string{"remove_default_node_pool"},
+			ExpectedValue: "true",
+		},
+	},
+	Actions: []types.RuleAction{
+		{
+			Type:              types.RemoveAllBlocksOfType,
+			BlockTypeToRemove: "node_pool",
+			BlockConditions: []types.RuleCondition{
+				{
+					Type:          types.AttributeValueEquals,
+					Path:          []string{"name"},
+					ExpectedValue: "default-pool",
+				},
+			},
+		},
+	},
+}
@@ -0,0 +1,37 @@
+package rules
+
+import (
+	"github.com/kotatut/cluster_import_cleaner/hclmodifier/types"
+)
+
+// DefaultDNSConfigRuleDefinition defines a rule that removes the `dns_config` block from `google_container_cluster`
+// resources when it is empty or only restates the defaults.
+//
+// What it does: If `dns_config` sets nothing but `cluster_dns = "PROVIDER_UNSPECIFIED"`,
+// `cluster_dns_scope = "DNS_SCOPE_UNSPECIFIED"` and empty domains, the whole block is removed. Blocks selecting
+// a DNS provider such as `CLOUD_DNS`, a scope or a domain are kept.
+//
+// Why it's necessary for GKE imports: clusters using the default kube-dns are imported with an empty or unspecified
+// `dns_config`, which behaves the same as omitting it.
+var DefaultDNSConfigRuleDefinition = types.Rule{
+	Name:               "DNS Config Rule: Remove dns_config if it is empty or only has the default values",
+	TargetResourceType: "google_container_cluster",
+	Conditions: []types.RuleCondition{
+		{
+			Type: types.BlockMatchesDefaults,
+			Path: []string{"dns_config"},
+			Defaults: map[string]string{
+				"cluster_dns":                   "PROVIDER_UNSPECIFIED",
+				"cluster_dns_scope":             "DNS_SCOPE_UNSPECIFIED",
+				"cluster_dns_domain":            "",
+				"additive_vpc_scope_dns_domain": "",
+			},
+		},
+	},
+	Actions: []types.RuleAction{
+		{
+			Type: types.RemoveBlock,
+			Path: []string{"dns_config"},
+		},
+	},
+}
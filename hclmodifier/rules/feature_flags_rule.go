@@ -0,0 +1,38 @@
+package rules
+
+import (
+	"github.com/kotatut/cluster_import_cleaner/hclmodifier/types"
+)
+
+// RemovedFeatureFlagsRules define rules that remove GKE feature flags the provider no longer supports from
+// `google_container_cluster` resources.
+//
+// What they do:
+//  1. Remove `enable_tpu` and the legacy `enable_binary_authorization` boolean, whatever their values.
+//     Binary Authorization is configured with the `binary_authorization` block instead.
+//  2. Remove `enable_kubernetes_alpha` when it is `false`, the default. Alpha clusters keep it, since it can't be
+//     changed; KubernetesAlphaClusterRule warns about them.
+//
+// Why it's necessary for GKE imports: configurations imported from older clusters still carry these flags,
+// and the provider rejects them or reports a diff on every plan.
+var RemovedFeatureFlagsRules = []types.Rule{
+	createRemoveAttributeRule("google_container_cluster", []string{"enable_tpu"}),
+	createRemoveAttributeRule("google_container_cluster", []string{"enable_binary_authorization"}),
+	{
+		Name:               "Removed Feature Flags Rule: Remove enable_kubernetes_alpha if it is false",
+		TargetResourceType: "google_container_cluster",
+		Conditions: []types.RuleCondition{
+			{
+				Type:          types.AttributeValueEquals,
+				Path:          []string{"enable_kubernetes_alpha"},
+				ExpectedValue: "false",
+			},
+		},
+		Actions: []types.RuleAction{
+			{
+				Type: types.RemoveAttribute,
+				Path: []string{"enable_kubernetes_alpha"},
+			},
+		},
+	},
+}
@@ -0,0 +1,13 @@
+package rules
+
+// IdentityServiceConfigRuleDefinition defines a rule that removes the `identity_service_config` block from
+// `google_container_cluster` resources when Identity Service for GKE is disabled.
+//
+// What it does: If `identity_service_config.enabled` is `false`, the whole `identity_service_config` block is removed.
+// The block is kept when `enabled = true`.
+//
+// Why it's necessary for GKE imports: clusters are imported with `identity_service_config { enabled = false }`,
+// which matches the default and only adds noise to the configuration.
+var IdentityServiceConfigRuleDefinition = createRemoveBlockWhenAttrEqualsRule(
+	"Identity Service Config Rule: Remove identity_service_config block if enabled is false",
+	"google_container_cluster", []string{"identity_service_config"}, []string{"enabled"}, "false")
@@ -0,0 +1,41 @@
+package rules
+
+import (
+	"github.com/kotatut/cluster_import_cleaner/hclmodifier/types"
+)
+
+// KubeletConfigDefaultsRules define rules that clean up default values in `node_config.kubelet_config` of every
+// `node_pool` block of a `google_container_cluster` resource. They must be applied in order.
+//
+// What they do:
+//  1. Remove `cpu_manager_policy = ""`, `cpu_cfs_quota_period = ""`, `cpu_cfs_quota = false` and
+//     `pod_pids_limit = 0` from `node_config.kubelet_config`.
+//  2. Remove the `kubelet_config` block if that left it empty. Blocks with any real setting are kept.
+//
+// Why it's necessary for GKE imports: `terraform import` writes these unset values back for node pools without a
+// custom kubelet configuration, and keeping them causes plan churn.
+var KubeletConfigDefaultsRules = []types.Rule{
+	createRemoveNodePoolDefaultRule("Kubelet Config Rule: Remove empty node_config.kubelet_config.cpu_manager_policy from node_pools",
+		[]string{"node_config", "kubelet_config", "cpu_manager_policy"}, ""),
+	createRemoveNodePoolDefaultRule("Kubelet Config Rule: Remove empty node_config.kubelet_config.cpu_cfs_quota_period from node_pools",
+		[]string{"node_config", "kubelet_config", "cpu_cfs_quota_period"}, ""),
+	createRemoveNodePoolDefaultRule("Kubelet Config Rule: Remove node_config.kubelet_config.cpu_cfs_quota = false from node_pools",
+		[]string{"node_config", "kubelet_config", "cpu_cfs_quota"}, "false"),
+	createRemoveNodePoolDefaultRule("Kubelet Config Rule: Remove node_config.kubelet_config.pod_pids_limit = 0 from node_pools",
+		[]string{"node_config", "kubelet_config", "pod_pids_limit"}, "0"),
+	{
+		Name:                  "Kubelet Config Rule: Remove empty node_config.kubelet_config from node_pools",
+		TargetResourceType:    "google_container_cluster",
+		ExecutionType:         types.RuleExecutionForEachNestedBlock,
+		NestedBlockTargetType: "node_pool",
+		Conditions: []types.RuleCondition{
+			{
+				Type: types.BlockIsEmpty,
+				Path: []string{"node_config", "kubelet_config"},
+			},
+		},
+		Actions: []types.RuleAction{
+			{Type: types.RemoveBlock, Path: []string{"node_config", "kubelet_config"}},
+		},
+	},
+}
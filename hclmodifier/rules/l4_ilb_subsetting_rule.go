@@ -0,0 +1,36 @@
+package rules
+
+import (
+	"github.com/kotatut/cluster_import_cleaner/hclmodifier/types"
+)
+
+// L4IlbSubsettingFalseRuleDefinition defines a rule to clean up 'enable_l4_ilb_subsetting'
+// when it is explicitly set to false.
+//
+// What it does: It checks if a 'google_container_cluster' resource has the
+// 'enable_l4_ilb_subsetting' attribute set to 'false'. If so, it removes the attribute.
+// A value of 'true' enables GKE subsetting for internal passthrough load balancers and is kept.
+//
+// Why it's necessary for GKE imports: imported clusters always get
+// 'enable_l4_ilb_subsetting = false', which is the default when the attribute is omitted.
+var L4IlbSubsettingFalseRuleDefinition = types.Rule{
+	Name:               "L4 ILB Subsetting Cleanup: Remove 'enable_l4_ilb_subsetting' if explicitly set to false",
+	TargetResourceType: "google_container_cluster",
+	Conditions: []types.RuleCondition{
+		{
+			Type: types.AttributeExists,
+			Path: []string{"enable_l4_ilb_subsetting"},
+		},
+		{
+			Type:          types.AttributeValueEquals,
+			Path:          []string{"enable_l4_ilb_subsetting"},
+			ExpectedValue: "false",
+		},
+	},
+	Actions: []types.RuleAction{
+		{
+			Type: types.RemoveAttribute,
+			Path: []string{"enable_l4_ilb_subsetting"},
+		},
+	},
+}
@@ -0,0 +1,23 @@
+package rules
+
+import (
+	"github.com/kotatut/cluster_import_cleaner/hclmodifier/types"
+)
+
+// LifecycleRuleDefinition defines a rule that removes every `lifecycle` meta-block from `google_container_cluster`
+// resources.
+//
+// Why it's useful for GKE imports: imports are sometimes done with `lifecycle { ignore_changes = [...] }` listing
+// attributes that other rules remove, which Terraform then reports as errors. Dropping the block also drops any
+// `prevent_destroy` or `create_before_destroy` set on purpose, so the rule is not part of Registry and is only
+// applied when enabled with the `--remove-lifecycle` flag.
+var LifecycleRuleDefinition = types.Rule{
+	Name:               "Lifecycle Rule: Remove lifecycle blocks",
+	TargetResourceType: "google_container_cluster",
+	Actions: []types.RuleAction{
+		{
+			Type:              types.RemoveAllBlocksOfType,
+			BlockTypeToRemove: "lifecycle",
+		},
+	},
+}
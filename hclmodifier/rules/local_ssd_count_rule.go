@@ -0,0 +1,30 @@
+package rules
+
+import (
+	"github.com/kotatut/cluster_import_cleaner/hclmodifier/types"
+)
+
+// LocalSsdCountRuleDefinition defines a rule that removes `node_config.local_ssd_count` from every
+// `node_pool` block of a `google_container_cluster` resource when it is `0`.
+//
+// Why it's necessary for GKE imports: `local_ssd_count = 0` is the default and is written back on import
+// for every node pool without local SSDs, which only adds noise. Non-zero counts are kept.
+var LocalSsdCountRuleDefinition = types.Rule{
+	Name:                  "Local SSD Count Rule: Remove node_config.local_ssd_count = 0 from node_pools",
+	TargetResourceType:    "google_container_cluster",
+	ExecutionType:         types.RuleExecutionForEachNestedBlock,
+	NestedBlockTargetType: "node_pool",
+	Conditions: []types.RuleCondition{
+		{
+			Type:          types.AttributeValueEquals,
+			Path:          []string{"node_config", "local_ssd_count"},
+			ExpectedValue: "0",
+		},
+	},
+	Actions: []types.RuleAction{
+		{
+			Type: types.RemoveAttribute,
+			Path: []string{"node_config", "local_ssd_count"},
+		},
+	},
+}
@@ -94,3 +94,15 @@ var RuleRemoveMonitoringService = types.Rule{
 		},
 	},
 }
+
+// ManagedPrometheusDisabledRule defines a rule that removes the `monitoring_config.managed_prometheus` block from
+// `google_container_cluster` resources when it only restates the default.
+//
+// What it does: If `monitoring_config.managed_prometheus.enabled` is `false`, the `managed_prometheus` block is removed.
+// The block is kept when Managed Service for Prometheus is enabled.
+//
+// Why it's necessary for GKE imports: clusters are imported with `managed_prometheus { enabled = false }`,
+// which matches the default and only adds noise to the configuration.
+var ManagedPrometheusDisabledRule = createRemoveBlockWhenAttrEqualsRule(
+	"Managed Prometheus Rule: Remove monitoring_config.managed_prometheus if enabled is false",
+	"google_container_cluster", []string{"monitoring_config", "managed_prometheus"}, []string{"enabled"}, "false")
@@ -44,3 +44,74 @@ var MasterCIDRRuleDefinition = types.Rule{
 		},
 	},
 }
+
+// privateClusterConfigDefaultsRuleName names the first of PrivateClusterConfigDefaultsRules, which the second one
+// runs after.
+const privateClusterConfigDefaultsRuleName = "Private Cluster Config Rule: Remove default enable_private_endpoint and enable_private_nodes"
+
+// PrivateClusterConfigDefaultsRules define rules that clean up a default `private_cluster_config` block of a
+// `google_container_cluster` resource. The second one runs after the first.
+//
+// What they do:
+//  1. Remove `enable_private_endpoint` and `enable_private_nodes` when both are `false` and no
+//     `master_ipv4_cidr_block` is set, either in the block or at the top level.
+//  2. Remove the `private_cluster_config` block if that left it empty.
+//
+// Why it's necessary for GKE imports: public clusters are imported with
+// `private_cluster_config { enable_private_endpoint = false, enable_private_nodes = false }`, which is the default.
+// A `master_ipv4_cidr_block` means the master IP configuration matters, so the block is then left to
+// MasterCIDRRuleDefinition, which runs first.
+var PrivateClusterConfigDefaultsRules = []types.Rule{
+	{
+		Name:               privateClusterConfigDefaultsRuleName,
+		TargetResourceType: "google_container_cluster",
+		Conditions: []types.RuleCondition{
+			{
+				Type:          types.AttributeValueEquals,
+				Path:          []string{"private_cluster_config", "enable_private_endpoint"},
+				ExpectedValue: "false",
+			},
+			{
+				Type:          types.AttributeValueEquals,
+				Path:          []string{"private_cluster_config", "enable_private_nodes"},
+				ExpectedValue: "false",
+			},
+			{
+				Type: types.AttributeDoesntExist,
+				Path: []string{"private_cluster_config", "master_ipv4_cidr_block"},
+			},
+			{
+				Type: types.AttributeDoesntExist,
+				Path: []string{"master_ipv4_cidr_block"},
+			},
+		},
+		Actions: []types.RuleAction{
+			{
+				Type: types.RemoveAttribute,
+				Path: []string{"private_cluster_config", "enable_private_endpoint"},
+			},
+			{
+				Type: types.RemoveAttribute,
+				Path: []string{"private_cluster_config", "enable_private_nodes"},
+			},
+		},
+		RunAfter: []string{MasterCIDRRuleDefinition.Name},
+	},
+	{
+		Name:               "Private Cluster Config Rule: Remove empty private_cluster_config block",
+		TargetResourceType: "google_container_cluster",
+		Conditions: []types.RuleCondition{
+			{
+				Type: types.BlockIsEmpty,
+				Path: []string{"private_cluster_config"},
+			},
+		},
+		Actions: []types.RuleAction{
+			{
+				Type: types.RemoveBlock,
+				Path: []string{"private_cluster_config"},
+			},
+		},
+		RunAfter: []string{privateClusterConfigDefaultsRuleName},
+	},
+}
@@ -4,6 +4,9 @@ import (
 	"github.com/kotatut/cluster_import_cleaner/hclmodifier/types"
 )
 
+// managedReleaseChannels are the `release_channel.channel` values for which GKE manages the cluster version.
+var managedReleaseChannels = []string{"RAPID", "REGULAR", "STABLE", "EXTENDED"}
+
 // SetMinVersionRuleDefinition defines a rule for handling conflicts within the `node_version`
 // and `min_master_version` root attributes of `google_container_cluster` resources.
 //
@@ -21,6 +24,12 @@ var SetMinVersionRule = types.Rule{
 			Type: types.AttributeDoesntExist, // Ensure this type matches the one defined in types/types.go
 			Path: []string{"min_master_version"},
 		},
+		{
+			// With a release channel, GKE rejects an explicit min_master_version (see ReleaseChannelMinMasterVersionRule).
+			Type:           types.AttributeValueNotIn,
+			Path:           []string{"release_channel", "channel"},
+			ExpectedValues: managedReleaseChannels,
+		},
 	},
 	Actions: []types.RuleAction{
 		{
@@ -29,4 +38,92 @@ var SetMinVersionRule = types.Rule{
 			PathToSet: []string{"node_version"},
 		},
 	},
+	// Rules that remove min_master_version must be able to do so before it is filled in from node_version.
+	RunAfter: []string{StaleMinMasterVersionRule.Name, ReleaseChannelMinMasterVersionRule.Name},
+}
+
+// StaleMinMasterVersionRule defines a rule that removes `min_master_version` from `google_container_cluster`
+// resources when it is older than `node_version`.
+//
+// Why it's necessary for GKE imports: GKE never runs nodes newer than the control plane, so a `min_master_version`
+// older than `node_version` only reflects the version the cluster was created with, not its current state.
+// Once removed, SetMinVersionRule sets it to `node_version`, which is why SetMinVersionRule runs after this rule.
+// Partial versions (e.g. "1.27") are compared on their common components only; unparseable versions are left alone.
+var StaleMinMasterVersionRule = types.Rule{
+	Name:               "Stale Min Master Version Rule: remove min_master_version if it is older than node_version",
+	TargetResourceType: "google_container_cluster",
+	Conditions: []types.RuleCondition{
+		{
+			Type:        types.VersionLessThan,
+			Path:        []string{"min_master_version"},
+			ComparePath: []string{"node_version"},
+		},
+	},
+	Actions: []types.RuleAction{
+		{
+			Type: types.RemoveAttribute,
+			Path: []string{"min_master_version"},
+		},
+	},
+}
+
+// ReleaseChannelMinMasterVersionRule defines a rule that removes `min_master_version` from `google_container_cluster`
+// resources enrolled in a release channel other than UNSPECIFIED.
+//
+// Why it's necessary for GKE imports: the imported configuration contains the current control plane version, but GKE
+// rejects configurations setting both a release channel and an explicit `min_master_version`.
+// SetMinVersionRule runs after it and doesn't set `min_master_version` again for such clusters.
+var ReleaseChannelMinMasterVersionRule = types.Rule{
+	Name:               "Release Channel Min Master Version Rule: remove min_master_version if a release channel is set",
+	TargetResourceType: "google_container_cluster",
+	Conditions: []types.RuleCondition{
+		{
+			Type: types.BlockExists,
+			Path: []string{"release_channel"},
+		},
+		{
+			Type:           types.AttributeValueIn,
+			Path:           []string{"release_channel", "channel"},
+			ExpectedValues: managedReleaseChannels,
+		},
+		{
+			Type: types.AttributeExists,
+			Path: []string{"min_master_version"},
+		},
+	},
+	Actions: []types.RuleAction{
+		{
+			Type: types.RemoveAttribute,
+			Path: []string{"min_master_version"},
+		},
+	},
+}
+
+// NodePoolVersionRule defines a rule that removes `version` from every `node_pool` block of a
+// `google_container_cluster` resource when it equals the cluster's `min_master_version`.
+//
+// Why it's necessary for GKE imports: imported node pools carry the version they currently run. When it is the
+// control plane version anyway, pinning it per pool is redundant and can get in the way of upgrading the control
+// plane before the node pools. Pools on a different version keep it.
+// It runs after SetMinVersionRule, which may set `min_master_version` first.
+var NodePoolVersionRule = types.Rule{
+	Name:                  "Node Pool Version Rule: Remove node_pool version if it equals min_master_version",
+	TargetResourceType:    "google_container_cluster",
+	ExecutionType:         types.RuleExecutionForEachNestedBlock,
+	NestedBlockTargetType: "node_pool",
+	Conditions: []types.RuleCondition{
+		{
+			Type:              types.AttributeValueEqualsPath,
+			Path:              []string{"version"},
+			ComparePath:       []string{"min_master_version"},
+			CompareInResource: true,
+		},
+	},
+	Actions: []types.RuleAction{
+		{
+			Type: types.RemoveAttribute,
+			Path: []string{"version"},
+		},
+	},
+	RunAfter: []string{SetMinVersionRule.Name},
 }
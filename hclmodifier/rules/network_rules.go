@@ -0,0 +1,25 @@
+package rules
+
+// DefaultSnatStatusRuleDefinition defines a rule that removes the `default_snat_status` block from
+// `google_container_cluster` resources when it only restates the provider default.
+//
+// What it does: If `default_snat_status.disabled` is `false`, the whole `default_snat_status` block is removed.
+// The block is kept when `disabled = true`, since that disables default SNAT.
+//
+// Why it's necessary for GKE imports: VPC-native clusters are imported with `default_snat_status { disabled = false }`,
+// which matches the default and only adds noise to the configuration.
+var DefaultSnatStatusRuleDefinition = createRemoveBlockWhenAttrEqualsRule(
+	"Default SNAT Status Rule: Remove default_snat_status block if disabled is false",
+	"google_container_cluster", []string{"default_snat_status"}, []string{"disabled"}, "false")
+
+// GatewayAPIConfigRuleDefinition defines a rule that removes the `gateway_api_config` block from
+// `google_container_cluster` resources when the Gateway API is disabled.
+//
+// What it does: If `gateway_api_config.channel` is `CHANNEL_DISABLED`, the whole `gateway_api_config` block is removed.
+// The block is kept for any enabled channel, such as `CHANNEL_STANDARD`.
+//
+// Why it's necessary for GKE imports: clusters are imported with `gateway_api_config { channel = "CHANNEL_DISABLED" }`,
+// which matches the default and only adds noise to the configuration.
+var GatewayAPIConfigRuleDefinition = createRemoveBlockWhenAttrEqualsRule(
+	"Gateway API Config Rule: Remove gateway_api_config block if channel is CHANNEL_DISABLED",
+	"google_container_cluster", []string{"gateway_api_config"}, []string{"channel"}, "CHANNEL_DISABLED")
@@ -0,0 +1,48 @@
+package rules
+
+import (
+	"fmt"
+
+	"github.com/kotatut/cluster_import_cleaner/hclmodifier/types"
+)
+
+// computeSelfLinkPattern matches a Compute Engine self-link, capturing its relative resource name
+// (e.g. `projects/my-project/global/networks/default`).
+const computeSelfLinkPattern = `^https://www\.googleapis\.com/compute/(?:v1|beta)/(projects/.+)$`
+
+// NetworkSelfLinkRules shorten the `network` and `subnetwork` attributes of `google_container_cluster` resources
+// from full self-links to relative resource names.
+//
+// What it does: `https://www.googleapis.com/compute/v1/projects/p/global/networks/default` becomes
+// `projects/p/global/networks/default`. Values that already are names, relative resource names or references are kept.
+//
+// Why it's necessary for GKE imports: imports may write these attributes as full self-links, which the provider
+// reports as a diff on every plan. The project is kept, so networks shared from another project still resolve.
+var NetworkSelfLinkRules = []types.Rule{
+	createShortenSelfLinkRule("network"),
+	createShortenSelfLinkRule("subnetwork"),
+}
+
+// createShortenSelfLinkRule returns a rule rewriting the attributeName attribute of google_container_cluster
+// resources from a self-link to a relative resource name.
+func createShortenSelfLinkRule(attributeName string) types.Rule {
+	return types.Rule{
+		Name:               fmt.Sprintf("Network Self Link Rule: Shorten %s self-link to a relative resource name", attributeName),
+		TargetResourceType: "google_container_cluster",
+		Conditions: []types.RuleCondition{
+			{
+				Type:          types.AttributeValueMatches,
+				Path:          []string{attributeName},
+				ExpectedValue: computeSelfLinkPattern,
+			},
+		},
+		Actions: []types.RuleAction{
+			{
+				Type:        types.RewriteAttributeWithRegex,
+				Path:        []string{attributeName},
+				Pattern:     computeSelfLinkPattern,
+				Replacement: "$1",
+			},
+		},
+	}
+}
@@ -0,0 +1,34 @@
+package rules
+
+import (
+	"github.com/kotatut/cluster_import_cleaner/hclmodifier/types"
+)
+
+// RemoveGKEManagedNetworkTagsRuleDefinition defines a rule that strips GKE-managed network tags
+// from the `node_config.tags` list of every `node_pool` block in a `google_container_cluster` resource.
+//
+// What it does: For each `node_pool` block, it removes every element of `node_config.tags` that starts
+// with `gke-`. If no tags remain, the `tags` attribute itself is removed.
+//
+// Why it's necessary for GKE imports: GKE adds its own network tags (e.g. `gke-<cluster>-<hash>-node`)
+// to node pool instances, and `terraform import` writes them back into `node_config.tags`. Keeping them
+// in the configuration makes Terraform try to manage tags it does not own. User-defined tags are preserved.
+var RemoveGKEManagedNetworkTagsRuleDefinition = types.Rule{
+	Name:                  "Network Tags Rule: Remove GKE-managed 'gke-' tags from node_pool node_config.tags",
+	TargetResourceType:    "google_container_cluster",
+	ExecutionType:         types.RuleExecutionForEachNestedBlock,
+	NestedBlockTargetType: "node_pool",
+	Conditions: []types.RuleCondition{
+		{
+			Type: types.AttributeExists,
+			Path: []string{"node_config", "tags"},
+		},
+	},
+	Actions: []types.RuleAction{
+		{
+			Type:    types.RemoveListElementsMatching,
+			Path:    []string{"node_config", "tags"},
+			Pattern: "^gke-.*",
+		},
+	},
+}
@@ -0,0 +1,61 @@
+package rules
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/kotatut/cluster_import_cleaner/hclmodifier/types"
+)
+
+const (
+	// DefaultNodeDiskSizeGb is the boot disk size GKE uses for node pools when `node_config.disk_size_gb` is not set.
+	DefaultNodeDiskSizeGb = 100
+	// DefaultNodeDiskType is the boot disk type GKE uses for node pools when `node_config.disk_type` is not set.
+	DefaultNodeDiskType = "pd-balanced"
+)
+
+// NodeConfigDiskDefaultsRules defines rules that remove `node_config.disk_size_gb` and `node_config.disk_type`
+// from every `node_pool` block of a `google_container_cluster` resource when they are set to the GKE defaults.
+//
+// Why it's necessary for GKE imports: `terraform import` writes the effective disk settings back for every
+// node pool, including the defaults, which only adds noise. Custom sizes and types are kept.
+var NodeConfigDiskDefaultsRules = NewNodeConfigDiskDefaultsRules(DefaultNodeDiskSizeGb, DefaultNodeDiskType)
+
+// NewNodeConfigDiskDefaultsRules returns the rules of NodeConfigDiskDefaultsRules for the given defaults, for
+// environments where the node pool disk defaults differ (e.g. set by organization policy). The rule names don't
+// depend on the defaults, so the rules can replace NodeConfigDiskDefaultsRules (see WithNodeConfigDiskDefaults).
+func NewNodeConfigDiskDefaultsRules(diskSizeGb int, diskType string) []types.Rule {
+	return []types.Rule{
+		createRemoveNodePoolDefaultRule("Node Config Disk Rule: Remove default node_config.disk_size_gb from node_pools", []string{"node_config", "disk_size_gb"}, strconv.Itoa(diskSizeGb)),
+		createRemoveNodePoolDefaultRule("Node Config Disk Rule: Remove default node_config.disk_type from node_pools", []string{"node_config", "disk_type"}, diskType),
+	}
+}
+
+// nodeConfigDiskDefaultsDescription returns the registry description of the rules of NewNodeConfigDiskDefaultsRules.
+func nodeConfigDiskDefaultsDescription(diskSizeGb int, diskType string) string {
+	return fmt.Sprintf("Removes disk_size_gb = %d and disk_type = %s from node_config.", diskSizeGb, diskType)
+}
+
+// createRemoveNodePoolDefaultRule returns a rule removing the attribute at path, relative to each `node_pool` block,
+// when it equals defaultValue.
+func createRemoveNodePoolDefaultRule(name string, path []string, defaultValue string) types.Rule {
+	return types.Rule{
+		Name:                  name,
+		TargetResourceType:    "google_container_cluster",
+		ExecutionType:         types.RuleExecutionForEachNestedBlock,
+		NestedBlockTargetType: "node_pool",
+		Conditions: []types.RuleCondition{
+			{
+				Type:          types.AttributeValueEquals,
+				Path:          path,
+				ExpectedValue: defaultValue,
+			},
+		},
+		Actions: []types.RuleAction{
+			{
+				Type: types.RemoveAttribute,
+				Path: path,
+			},
+		},
+	}
+}
@@ -0,0 +1,65 @@
+package rules
+
+import (
+	"github.com/kotatut/cluster_import_cleaner/hclmodifier/types"
+)
+
+// LegacyEndpointsMetadataRuleDefinition defines a rule that removes the `disable-legacy-endpoints` entry from the
+// `node_config.metadata` map of every `node_pool` block of a `google_container_cluster` resource, when it is the only
+// entry.
+//
+// Why it's necessary for GKE imports: GKE adds `disable-legacy-endpoints = "true"` to the metadata of every node pool,
+// and the provider manages it itself, so keeping it in the configuration only leads to plan diffs. Metadata with other
+// keys is kept as it is: changing the metadata recreates the node pool, so the entry can only go along with the whole
+// attribute (see EmptyNodeConfigMetadataRuleDefinition).
+var LegacyEndpointsMetadataRuleDefinition = types.Rule{
+	Name:                  "Node Config Metadata Rule: Remove disable-legacy-endpoints from node_config.metadata in node_pools",
+	TargetResourceType:    "google_container_cluster",
+	ExecutionType:         types.RuleExecutionForEachNestedBlock,
+	NestedBlockTargetType: "node_pool",
+	Conditions: []types.RuleCondition{
+		{
+			Type:           types.MapKeysEqual,
+			Path:           []string{"node_config", "metadata"},
+			ExpectedValues: []string{"disable-legacy-endpoints"},
+		},
+	},
+	Actions: []types.RuleAction{
+		{
+			Type:   types.RemoveMapEntry,
+			Path:   []string{"node_config", "metadata"},
+			MapKey: "disable-legacy-endpoints",
+		},
+	},
+}
+
+// EmptyNodeConfigMetadataRuleDefinition defines a rule that removes `node_config.metadata` from every `node_pool` block
+// of a `google_container_cluster` resource when it is an empty map.
+//
+// Why it's necessary for GKE imports: `metadata = {}` is all that's left once LegacyEndpointsMetadataRuleDefinition has
+// removed `disable-legacy-endpoints`, which it runs after.
+var EmptyNodeConfigMetadataRuleDefinition = types.Rule{
+	Name:                  "Node Config Metadata Rule: Remove empty node_config.metadata from node_pools",
+	TargetResourceType:    "google_container_cluster",
+	ExecutionType:         types.RuleExecutionForEachNestedBlock,
+	NestedBlockTargetType: "node_pool",
+	Conditions: []types.RuleCondition{
+		{
+			Type: types.AttributeIsEmpty,
+			Path: []string{"node_config", "metadata"},
+		},
+	},
+	Actions: []types.RuleAction{
+		{
+			Type: types.RemoveAttribute,
+			Path: []string{"node_config", "metadata"},
+		},
+	},
+	RunAfter: []string{LegacyEndpointsMetadataRuleDefinition.Name},
+}
+
+// NodeConfigMetadataRules remove node pool metadata that only has the `disable-legacy-endpoints` entry.
+var NodeConfigMetadataRules = []types.Rule{
+	LegacyEndpointsMetadataRuleDefinition,
+	EmptyNodeConfigMetadataRuleDefinition,
+}
@@ -0,0 +1,45 @@
+package rules
+
+import (
+	"slices"
+
+	"github.com/kotatut/cluster_import_cleaner/hclmodifier/types"
+)
+
+// EmptyNodeConfigRuleDefinition defines a rule that removes the `node_config` block from every `node_pool` block
+// of a `google_container_cluster` resource when it has no attributes and no non-empty nested blocks.
+//
+// Why it's necessary for GKE imports: once the other node pool rules have stripped defaults and computed values,
+// `node_config {}` is often all that's left, which is the same as omitting it. It runs after all of those rules.
+var EmptyNodeConfigRuleDefinition = types.Rule{
+	Name:                  "Node Config Rule: Remove empty node_config from node_pools",
+	TargetResourceType:    "google_container_cluster",
+	ExecutionType:         types.RuleExecutionForEachNestedBlock,
+	NestedBlockTargetType: "node_pool",
+	Conditions: []types.RuleCondition{
+		{
+			Type: types.BlockIsEmpty,
+			Path: []string{"node_config"},
+		},
+	},
+	Actions: []types.RuleAction{
+		{Type: types.RemoveBlock, Path: []string{"node_config"}},
+	},
+	RunAfter: ruleNames(slices.Concat([]types.Rule{
+		SystemTaintsRuleDefinition,
+		LocalSsdCountRuleDefinition,
+		OsVersionNodePoolRuleDefinition,
+		GuestAcceleratorComputedFieldsRuleDefinition,
+		RemoveGKEManagedNetworkTagsRuleDefinition,
+		WorkloadIdentityOauthScopesRuleDefinition,
+	}, NodeConfigDiskDefaultsRules, KubeletConfigDefaultsRules, NodeConfigMetadataRules)),
+}
+
+// ruleNames returns the names of rulesToName, e.g. to list them in a RunAfter.
+func ruleNames(rulesToName []types.Rule) []string {
+	names := make([]string, 0, len(rulesToName))
+	for _, rule := range rulesToName {
+		names = append(names, rule.Name)
+	}
+	return names
+}
@@ -0,0 +1,59 @@
+package rules
+
+import (
+	"github.com/kotatut/cluster_import_cleaner/hclmodifier/types"
+)
+
+// EmptyNodeLocationsRuleDefinition defines a rule that removes the `node_locations` attribute from
+// `google_container_cluster` resources when it is an empty list.
+//
+// Why it's necessary for GKE imports: clusters whose nodes only run in the cluster's own zone (or the default
+// zones of its region) are imported with `node_locations = []`, which has the same effect as omitting it.
+var EmptyNodeLocationsRuleDefinition = types.Rule{
+	Name:               "Node Locations Rule: Remove node_locations if it is an empty list",
+	TargetResourceType: "google_container_cluster",
+	Conditions: []types.RuleCondition{
+		{
+			Type: types.AttributeIsEmptyList,
+			Path: []string{"node_locations"},
+		},
+	},
+	Actions: []types.RuleAction{
+		{
+			Type: types.RemoveAttribute,
+			Path: []string{"node_locations"},
+		},
+	},
+	RunAfter: []string{ZonalNodeLocationsOverlapRuleDefinition.Name},
+}
+
+// ZonalNodeLocationsOverlapRuleDefinition defines a rule that removes the cluster's own zone from the `node_locations`
+// list of zonal `google_container_cluster` resources.
+//
+// What it does: If `location` is a zone (e.g. `us-central1-a`) that `node_locations` also lists, that element is removed.
+// Other zones are kept; if none remain, `node_locations` is removed. Regional clusters are left alone.
+//
+// Why it's necessary for GKE imports: for zonal clusters, `node_locations` lists the additional zones of the nodes
+// besides the cluster's own zone. Imports may list that zone too, which the provider rejects.
+var ZonalNodeLocationsOverlapRuleDefinition = types.Rule{
+	Name:               "Node Locations Rule: Remove the cluster's zone from node_locations of zonal clusters",
+	TargetResourceType: "google_container_cluster",
+	Conditions: []types.RuleCondition{
+		{
+			Type:          types.AttributeValueMatches,
+			Path:          []string{"location"},
+			ExpectedValue: zonalLocationPattern,
+		},
+		{
+			Type: types.AttributeExists,
+			Path: []string{"node_locations"},
+		},
+	},
+	Actions: []types.RuleAction{
+		{
+			Type:             types.RemoveListElement,
+			Path:             []string{"node_locations"},
+			ElementValuePath: []string{"location"},
+		},
+	},
+}
@@ -0,0 +1,34 @@
+package rules
+
+import (
+	"github.com/kotatut/cluster_import_cleaner/hclmodifier/types"
+)
+
+// NodePoolPodRangeRuleDefinition defines a rule that removes `network_config.pod_ipv4_cidr_block` from every
+// `node_pool` block of a `google_container_cluster` resource whose `network_config` also sets `pod_range`.
+//
+// Why it's necessary for GKE imports: on import, node pools get both the name of their pod secondary range and the
+// CIDR block GKE computed for it. Setting both conflicts on apply, so the rule keeps the named range, the same
+// way PodIPV4CIDRRuleDefinition does for the cluster's own pod range.
+var NodePoolPodRangeRuleDefinition = types.Rule{
+	Name:                  "Node Pool Pod Range Rule: Remove network_config.pod_ipv4_cidr_block from node_pools if pod_range exists",
+	TargetResourceType:    "google_container_cluster",
+	ExecutionType:         types.RuleExecutionForEachNestedBlock,
+	NestedBlockTargetType: "node_pool",
+	Conditions: []types.RuleCondition{
+		{
+			Type: types.AttributeExists,
+			Path: []string{"network_config", "pod_ipv4_cidr_block"},
+		},
+		{
+			Type: types.AttributeExists,
+			Path: []string{"network_config", "pod_range"},
+		},
+	},
+	Actions: []types.RuleAction{
+		{
+			Type: types.RemoveAttribute,
+			Path: []string{"network_config", "pod_ipv4_cidr_block"},
+		},
+	},
+}
@@ -0,0 +1,33 @@
+package rules
+
+import (
+	"github.com/kotatut/cluster_import_cleaner/hclmodifier/types"
+)
+
+// PlacementPolicyDefaultsRuleDefinition defines a rule that removes the `placement_policy` block from every `node_pool`
+// block of a `google_container_cluster` resource when it sets no placement type.
+//
+// What it does: If `placement_policy` is empty or only sets `type = ""`, the whole block is removed. A placement type
+// such as `COMPACT` is kept.
+//
+// Why it's necessary for GKE imports: node pools without compact placement are imported with an empty policy, which
+// behaves the same as omitting it.
+var PlacementPolicyDefaultsRuleDefinition = types.Rule{
+	Name:                  "Placement Policy Rule: Remove node_pool.placement_policy if it sets no type",
+	TargetResourceType:    "google_container_cluster",
+	ExecutionType:         types.RuleExecutionForEachNestedBlock,
+	NestedBlockTargetType: "node_pool",
+	Conditions: []types.RuleCondition{
+		{
+			Type:     types.BlockMatchesDefaults,
+			Path:     []string{"placement_policy"},
+			Defaults: map[string]string{"type": ""},
+		},
+	},
+	Actions: []types.RuleAction{
+		{
+			Type: types.RemoveBlock,
+			Path: []string{"placement_policy"},
+		},
+	},
+}
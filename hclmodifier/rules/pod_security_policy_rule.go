@@ -0,0 +1,27 @@
+package rules
+
+import (
+	"github.com/kotatut/cluster_import_cleaner/hclmodifier/types"
+)
+
+// PodSecurityPolicyConfigRuleDefinition defines a rule that removes the `pod_security_policy_config` block from
+// `google_container_cluster` resources, whatever its content.
+//
+// Why it's necessary for GKE imports: PodSecurityPolicy was removed from GKE, but configurations imported from legacy
+// clusters still carry the block, and applying them fails.
+var PodSecurityPolicyConfigRuleDefinition = types.Rule{
+	Name:               "Pod Security Policy Rule: Remove deprecated pod_security_policy_config block",
+	TargetResourceType: "google_container_cluster",
+	Conditions: []types.RuleCondition{
+		{
+			Type: types.BlockExists,
+			Path: []string{"pod_security_policy_config"},
+		},
+	},
+	Actions: []types.RuleAction{
+		{
+			Type: types.RemoveBlock,
+			Path: []string{"pod_security_policy_config"},
+		},
+	},
+}
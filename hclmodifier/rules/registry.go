@@ -0,0 +1,159 @@
+package rules
+
+import (
+	"github.com/kotatut/cluster_import_cleaner/hclmodifier/types"
+)
+
+// Categories group the registered rules by the part of the cluster configuration they clean up.
+const (
+	CategoryNetwork   = "network"
+	CategoryVersion   = "version"
+	CategoryLogging   = "logging"
+	CategoryNodePool  = "node_pool"
+	CategorySecurity  = "security"
+	CategoryAutopilot = "autopilot"
+	CategoryAddons    = "addons"
+	CategoryDefaults  = "defaults"
+	CategoryComputed  = "computed"
+	CategoryAnalysis  = "analysis"
+	CategoryRemoved   = "removed"
+)
+
+// RegisteredRule is a rule of the default set along with metadata describing it.
+type RegisteredRule struct {
+	Rule types.Rule
+	// Category is one of the Category constants.
+	Category string
+	// Description is a one-line, human-readable summary of what the rule cleans up.
+	Description string
+}
+
+// register returns a RegisteredRule for each of rulesToRegister, all sharing category and description.
+func register(category, description string, rulesToRegister ...types.Rule) []RegisteredRule {
+	registered := make([]RegisteredRule, 0, len(rulesToRegister))
+	for _, rule := range rulesToRegister {
+		registered = append(registered, RegisteredRule{Rule: rule, Category: category, Description: description})
+	}
+	return registered
+}
+
+// Registry returns every rule of the default set with its metadata, in the order the rules are applied.
+// New rules must be added here to be applied by the CLI.
+func Registry() []RegisteredRule {
+	groups := [][]RegisteredRule{
+		register(CategoryNetwork, "Removes cluster_ipv4_cidr when ip_allocation_policy.cluster_ipv4_cidr_block is set.", ClusterIPV4CIDRRuleDefinition),
+		register(CategoryNetwork, "Removes private_cluster_config.private_endpoint_subnetwork when master_ipv4_cidr_block is set.", MasterCIDRRuleDefinition),
+		register(CategoryNetwork, "Removes ip_allocation_policy.services_ipv4_cidr_block when a services secondary range name is set.", ServicesIPV4CIDRRuleDefinition),
+		register(CategoryNetwork, "Removes ip_allocation_policy.cluster_ipv4_cidr_block when a cluster secondary range name is set.", PodIPV4CIDRRuleDefinition),
+		register(CategoryNetwork, "Removes default_snat_status when disabled is false.", DefaultSnatStatusRuleDefinition),
+		register(CategoryNetwork, "Removes default_max_pods_per_node from clusters without ip_allocation_policy.", DefaultMaxPodsPerNodeRuleDefinition),
+		register(CategoryNetwork, "Removes gateway_api_config when the channel is CHANNEL_DISABLED.", GatewayAPIConfigRuleDefinition),
+		register(CategoryNetwork, "Removes enable_l4_ilb_subsetting = false.", L4IlbSubsettingFalseRuleDefinition),
+		register(CategoryNetwork, "Removes dns_config when it is empty or only has the default values.", DefaultDNSConfigRuleDefinition),
+		register(CategoryNetwork, "Shortens network and subnetwork self-links to relative resource names.", NetworkSelfLinkRules...),
+		register(CategoryAddons, "Keeps only the first addons_config sub-block of each type.", DuplicateAddonsConfigRuleDefinition),
+		register(CategorySecurity, "Removes binary_authorization.enabled when evaluation_mode is set.", BinaryAuthorizationRuleDefinition),
+		register(CategoryLogging, "Removes logging_service when cluster_telemetry is enabled or logging_config is set.",
+			RuleRemoveLoggingService, RemoveLoggingServiceOnConfigPresentRule),
+		register(CategoryLogging, "Removes monitoring_service when monitoring_config is set.", RuleRemoveMonitoringService),
+		register(CategoryLogging, "Removes monitoring_config.managed_prometheus when enabled is false.", ManagedPrometheusDisabledRule),
+		register(CategoryVersion, "Removes min_master_version when a release channel manages the version.", ReleaseChannelMinMasterVersionRule),
+		register(CategoryVersion, "Removes min_master_version when it is older than node_version.", StaleMinMasterVersionRule),
+		register(CategoryVersion, "Sets a missing min_master_version to node_version.", SetMinVersionRule),
+		register(CategoryVersion, "Removes node_pool versions equal to min_master_version.", NodePoolVersionRule),
+		register(CategoryDefaults, "Removes pod_autoscaling when hpa_profile is HPA_PROFILE_UNSPECIFIED.", HpaProfileRuleDefinition),
+		register(CategoryDefaults, "Removes vertical_pod_autoscaling when enabled is false.", VerticalPodAutoscalingRuleDefinition),
+		register(CategoryDefaults, "Removes cluster_autoscaling when it is disabled and sets no autoscaling_profile.", DisabledClusterAutoscalingRuleDefinition),
+		register(CategoryDefaults, "Removes cluster_autoscaling.auto_provisioning_defaults.disk_size = 0.", DiskSizeRuleDefinition),
+		register(CategoryDefaults, "Removes default oauth_scopes and service_account from cluster_autoscaling.auto_provisioning_defaults.", AutoProvisioningDefaultsRules...),
+		register(CategoryNodePool, "Removes the default-pool node_pool when remove_default_node_pool is true.", RemovedDefaultNodePoolRuleDefinition),
+		register(CategoryNodePool, "Removes windows_node_config blocks from node_config when they set no osversion.",
+			OsVersionRuleDefinition, OsVersionNodePoolRuleDefinition),
+		register(CategoryNodePool, "Removes initial_node_count from node_pools.", InitialNodeCountRuleDefinition),
+		register(CategoryNodePool, "Removes total_min_node_count and total_max_node_count from node_pool autoscaling of zonal clusters.", ZonalTotalNodeCountsRuleDefinition),
+		register(CategoryNodePool, "Removes gke- network tags from node_config.tags.", RemoveGKEManagedNetworkTagsRuleDefinition),
+		register(CategoryNodePool, "Removes gpu_partition_size and empty gpu_sharing_config from node_config.guest_accelerator.", GuestAcceleratorComputedFieldsRuleDefinition),
+		register(CategoryNodePool, "Removes node_config.local_ssd_count = 0.", LocalSsdCountRuleDefinition),
+		register(CategoryNetwork, "Removes node_pool network_config.pod_ipv4_cidr_block when pod_range is set.", NodePoolPodRangeRuleDefinition),
+		register(CategoryNodePool, "Removes node_config taints with a GKE system key prefix.", SystemTaintsRuleDefinition),
+		register(CategoryAutopilot, "Removes enable_autopilot = false.", RuleHandleAutopilotFalse),
+		register(CategorySecurity, "Removes enable_legacy_abac = false.", RuleHandleLegacyAbacFalse),
+		register(CategorySecurity, "Migrates workload_identity_config.identity_namespace to workload_pool.",
+			WorkloadIdentityDuplicateNamespaceRuleDefinition, WorkloadIdentityNamespaceRuleDefinition),
+		register(CategorySecurity, "Removes node_config.oauth_scopes from node_pools when Workload Identity is enabled.", WorkloadIdentityOauthScopesRuleDefinition),
+		register(CategoryComputed, "Removes the goog-terraform-provisioned label from terraform_labels.", RuleTerraformLabel),
+		register(CategoryComputed, "Removes goog- labels managed by Google Cloud from resource_labels.", ManagedResourceLabelsRuleDefinition),
+		register(CategoryDefaults, "Removes empty resource_labels.", EmptyResourceLabelsRuleDefinition),
+		register(CategoryDefaults, "Removes the zone of zonal clusters from their node_locations.", ZonalNodeLocationsOverlapRuleDefinition),
+		register(CategoryDefaults, "Removes empty node_locations.", EmptyNodeLocationsRuleDefinition),
+		register(CategoryDefaults, "Removes timeouts blocks.", TimeoutsRuleDefinition),
+		register(CategorySecurity, "Removes the deprecated pod_security_policy_config block.", PodSecurityPolicyConfigRuleDefinition),
+		register(CategoryRemoved, "Removes enable_tpu, enable_binary_authorization and enable_kubernetes_alpha = false, which the provider no longer supports.", RemovedFeatureFlagsRules...),
+		register(CategoryNodePool, nodeConfigDiskDefaultsDescription(DefaultNodeDiskSizeGb, DefaultNodeDiskType), NodeConfigDiskDefaultsRules...),
+		register(CategoryNodePool, "Removes unset values from node_config.kubelet_config, then the block if it is empty.", KubeletConfigDefaultsRules...),
+		register(CategoryNodePool, "Removes node_config.metadata when disable-legacy-endpoints is its only entry.", NodeConfigMetadataRules...),
+		register(CategoryNodePool, "Removes node_pool upgrade_settings blocks that only have the default values.", UpgradeSettingsDefaultsRuleDefinition),
+		register(CategoryNodePool, "Removes node_pool placement_policy blocks that set no type.", PlacementPolicyDefaultsRuleDefinition),
+		register(CategoryNodePool, "Removes node_config blocks left empty by the other node pool rules.", EmptyNodeConfigRuleDefinition),
+		register(CategoryAutopilot, "Removes settings that Autopilot manages from Autopilot clusters.", AutopilotRules...),
+		register(CategoryComputed, "Removes attributes computed by GKE that can't be set in configuration.", TopLevelComputedAttributesRules...),
+		register(CategoryComputed, "Removes other attributes computed by GKE.", OtherComputedAttributesRules...),
+		register(CategorySecurity, "Removes master_auth.client_certificate_config.issue_client_certificate = false, then empty blocks.", MasterAuthClientCertificateRules...),
+		register(CategorySecurity, "Removes identity_service_config when enabled is false.", IdentityServiceConfigRuleDefinition),
+		register(CategoryNetwork, "Removes private_cluster_config defaults of public clusters, then the block if it is empty.", PrivateClusterConfigDefaultsRules...),
+		register(CategoryComputed, "Removes status attributes computed by GKE.", StatusComputedAttributesRules...),
+		register(CategoryComputed, "Removes the fleet membership attributes computed by GKE.", FleetComputedAttributesRules...),
+		register(CategoryAnalysis, "Reports configurations that look wrong without modifying them.", AnalysisRules...),
+	}
+
+	var registry []RegisteredRule
+	for _, group := range groups {
+		registry = append(registry, group...)
+	}
+	return registry
+}
+
+// LifecycleRegisteredRule is the opinionated LifecycleRuleDefinition with its metadata. It is left out of Registry
+// and only applied when enabled with the `--remove-lifecycle` flag.
+var LifecycleRegisteredRule = RegisteredRule{
+	Rule:        LifecycleRuleDefinition,
+	Category:    CategoryDefaults,
+	Description: "Removes lifecycle blocks (opt-in with --remove-lifecycle).",
+}
+
+// EmptyMasterAuthorizedNetworksConfigRegisteredRule is EmptyMasterAuthorizedNetworksConfigRuleDefinition with its
+// metadata. Removing the block disables authorized networks, so it is left out of Registry and only applied when
+// enabled with the `--remove-empty-authorized-networks` flag.
+var EmptyMasterAuthorizedNetworksConfigRegisteredRule = RegisteredRule{
+	Rule:        EmptyMasterAuthorizedNetworksConfigRuleDefinition,
+	Category:    CategorySecurity,
+	Description: "Removes empty master_authorized_networks_config blocks (opt-in with --remove-empty-authorized-networks).",
+}
+
+// WithNodeConfigDiskDefaults returns a copy of registry where the NodeConfigDiskDefaultsRules are replaced by the
+// rules of NewNodeConfigDiskDefaultsRules for the given defaults, keeping their position and category.
+func WithNodeConfigDiskDefaults(registry []RegisteredRule, diskSizeGb int, diskType string) []RegisteredRule {
+	customRules := make(map[string]types.Rule)
+	for _, rule := range NewNodeConfigDiskDefaultsRules(diskSizeGb, diskType) {
+		customRules[rule.Name] = rule
+	}
+	withDefaults := make([]RegisteredRule, 0, len(registry))
+	for _, registered := range registry {
+		if rule, ok := customRules[registered.Rule.Name]; ok {
+			registered.Rule = rule
+			registered.Description = nodeConfigDiskDefaultsDescription(diskSizeGb, diskType)
+		}
+		withDefaults = append(withDefaults, registered)
+	}
+	return withDefaults
+}
+
+// AllRules returns every rule of the default set, in the order they are applied.
+func AllRules() []types.Rule {
+	registry := Registry()
+	allRules := make([]types.Rule, 0, len(registry))
+	for _, registered := range registry {
+		allRules = append(allRules, registered.Rule)
+	}
+	return allRules
+}
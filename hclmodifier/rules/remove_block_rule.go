@@ -0,0 +1,34 @@
+package rules
+
+import (
+	"slices"
+
+	"github.com/kotatut/cluster_import_cleaner/hclmodifier/types"
+)
+
+// createRemoveBlockWhenAttrEqualsRule returns a rule named name that removes the block at blockPath from resourceType
+// resources when the attribute at attrPath, relative to that block, equals expectedValue. This is the shape of the
+// rules removing feature blocks imported with their default, disabled settings (e.g. `enabled = false`).
+func createRemoveBlockWhenAttrEqualsRule(name, resourceType string, blockPath, attrPath []string, expectedValue string) types.Rule {
+	return types.Rule{
+		Name:               name,
+		TargetResourceType: resourceType,
+		Conditions: []types.RuleCondition{
+			{
+				Type: types.BlockExists,
+				Path: blockPath,
+			},
+			{
+				Type:          types.AttributeValueEquals,
+				Path:          slices.Concat(blockPath, attrPath),
+				ExpectedValue: expectedValue,
+			},
+		},
+		Actions: []types.RuleAction{
+			{
+				Type: types.RemoveBlock,
+				Path: blockPath,
+			},
+		},
+	}
+}
@@ -0,0 +1,57 @@
+package rules
+
+import (
+	"github.com/kotatut/cluster_import_cleaner/hclmodifier/types"
+)
+
+// EmptyResourceLabelsRuleDefinition defines a rule that removes the `resource_labels` attribute from
+// `google_container_cluster` resources when it is an empty map.
+//
+// What it does: If `resource_labels = {}`, the attribute is removed.
+//
+// Why it's necessary for GKE imports: Once managed labels are stripped from `resource_labels`, an empty
+// map is often all that's left. It has the same effect as omitting the attribute, so it's just noise.
+// It runs after ManagedResourceLabelsRuleDefinition, which strips those labels.
+var EmptyResourceLabelsRuleDefinition = types.Rule{
+	Name:               "Resource Labels Rule: Remove resource_labels if it is empty",
+	TargetResourceType: "google_container_cluster",
+	Conditions: []types.RuleCondition{
+		{
+			Type: types.AttributeIsEmpty,
+			Path: []string{"resource_labels"},
+		},
+	},
+	Actions: []types.RuleAction{
+		{
+			Type: types.RemoveAttribute,
+			Path: []string{"resource_labels"},
+		},
+	},
+	RunAfter: []string{ManagedResourceLabelsRuleDefinition.Name},
+}
+
+// ManagedResourceLabelsRuleDefinition defines a rule that removes provider-managed labels from the `resource_labels`
+// attribute of `google_container_cluster` resources.
+//
+// What it does: Every `resource_labels` entry whose key starts with `goog-` is removed; user labels are kept as they are.
+//
+// Why it's necessary for GKE imports: labels such as `goog-terraform-provisioned` or `goog-k8s-cluster-name` are set
+// by Google Cloud, not by the user, so keeping them in the configuration only leads to plan diffs.
+var ManagedResourceLabelsRuleDefinition = types.Rule{
+	Name:               "Resource Labels Rule: Remove goog- managed labels from resource_labels",
+	TargetResourceType: "google_container_cluster",
+	Conditions: []types.RuleCondition{
+		{
+			Type: types.AttributeExists,
+			Path: []string{"resource_labels"},
+		},
+	},
+	Actions: []types.RuleAction{
+		{
+			Type:           types.RemoveMapEntry,
+			Path:           []string{"resource_labels"},
+			MapKey:         "goog-",
+			MatchKeyPrefix: true,
+		},
+	},
+}
@@ -0,0 +1,38 @@
+package rules
+
+import (
+	"github.com/kotatut/cluster_import_cleaner/hclmodifier/types"
+)
+
+// SystemTaintsRuleDefinition defines a rule that removes GKE-managed taints from the `node_config` of every
+// `node_pool` block of a `google_container_cluster` resource. A taint is GKE-managed when its key starts with
+// one of the system prefixes (`nvidia.com/`, `sandbox.gke.io/`, `components.gke.io/`); other taints are kept.
+//
+// Why it's necessary for GKE imports: GKE adds these taints itself, e.g. for GPU or GKE Sandbox node pools,
+// and they are written back as `taint` blocks on import. Terraform can't manage them, so keeping them causes
+// errors or permanent diffs.
+var SystemTaintsRuleDefinition = types.Rule{
+	Name:                  "System Taints Rule: Remove GKE-managed taint blocks from node_pools",
+	TargetResourceType:    "google_container_cluster",
+	ExecutionType:         types.RuleExecutionForEachNestedBlock,
+	NestedBlockTargetType: "node_pool",
+	Conditions: []types.RuleCondition{
+		{
+			Type: types.BlockExists,
+			Path: []string{"node_config", "taint"},
+		},
+	},
+	Actions: []types.RuleAction{
+		{
+			Type: types.RemoveAllNestedBlocksMatchingPath,
+			Path: []string{"node_config", "taint"},
+			BlockConditions: []types.RuleCondition{
+				{
+					Type:          types.AttributeValueMatches,
+					Path:          []string{"key"},
+					ExpectedValue: `^(nvidia\.com|sandbox\.gke\.io|components\.gke\.io)/`,
+				},
+			},
+		},
+	},
+}
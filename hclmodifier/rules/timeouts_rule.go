@@ -0,0 +1,21 @@
+package rules
+
+import (
+	"github.com/kotatut/cluster_import_cleaner/hclmodifier/types"
+)
+
+// TimeoutsRuleDefinition defines a rule that removes every `timeouts` block from `google_container_cluster` resources.
+//
+// Why it's necessary for GKE imports: generated configurations sometimes carry a `timeouts {}` block that was added
+// automatically and only restates the provider defaults. Custom timeouts are operational settings rather than a part
+// of the imported cluster, so they are dropped as well.
+var TimeoutsRuleDefinition = types.Rule{
+	Name:               "Timeouts Rule: Remove timeouts blocks",
+	TargetResourceType: "google_container_cluster",
+	Actions: []types.RuleAction{
+		{
+			Type:              types.RemoveAllBlocksOfType,
+			BlockTypeToRemove: "timeouts",
+		},
+	},
+}
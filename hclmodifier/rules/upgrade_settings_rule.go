@@ -0,0 +1,37 @@
+package rules
+
+import (
+	"github.com/kotatut/cluster_import_cleaner/hclmodifier/types"
+)
+
+// UpgradeSettingsDefaultsRuleDefinition defines a rule that removes the `upgrade_settings` block from every
+// `node_pool` block of a `google_container_cluster` resource when it only restates the GKE defaults.
+//
+// What it does: If `upgrade_settings` only sets `max_surge = 1`, `max_unavailable = 0` and `strategy = "SURGE"`,
+// the whole block is removed. Blocks with any other surge settings, strategy or `blue_green_settings` are kept.
+//
+// Why it's necessary for GKE imports: every node pool is imported with its upgrade settings, which are the defaults
+// unless they were customized, so they only add noise.
+var UpgradeSettingsDefaultsRuleDefinition = types.Rule{
+	Name:                  "Upgrade Settings Rule: Remove node_pool.upgrade_settings if it only has the default values",
+	TargetResourceType:    "google_container_cluster",
+	ExecutionType:         types.RuleExecutionForEachNestedBlock,
+	NestedBlockTargetType: "node_pool",
+	Conditions: []types.RuleCondition{
+		{
+			Type: types.BlockMatchesDefaults,
+			Path: []string{"upgrade_settings"},
+			Defaults: map[string]string{
+				"max_surge":       "1",
+				"max_unavailable": "0",
+				"strategy":        "SURGE",
+			},
+		},
+	},
+	Actions: []types.RuleAction{
+		{
+			Type: types.RemoveBlock,
+			Path: []string{"upgrade_settings"},
+		},
+	},
+}
@@ -0,0 +1,204 @@
+package rules
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+
+	"github.com/kotatut/cluster_import_cleaner/hclmodifier/types"
+)
+
+// knownConditionTypes are the condition types checkCondition knows how to evaluate.
+var knownConditionTypes = []types.ConditionType{
+	types.AttributeExists,
+	types.AttributeDoesntExist,
+	types.BlockExists,
+	types.BlockDoesntExist,
+	types.AttributeValueEquals,
+	types.AttributeExistsAnyOf,
+	types.NullValue,
+	types.AttributeValueIn,
+	types.AttributeValueNotIn,
+	types.AttributeValueMatches,
+	types.AttributeValueGreaterThan,
+	types.AttributeValueLessThan,
+	types.ListLengthGreaterThan,
+	types.ListValuesEqual,
+	types.MapKeysEqual,
+	types.AttributeIsEmpty,
+	types.AttributeIsEmptyList,
+	types.BlockCountEquals,
+	types.BlockIsEmpty,
+	types.BlockMatchesDefaults,
+	types.AttributeValueEqualsPath,
+	types.VersionLessThan,
+}
+
+// knownActionTypes are the action types executeAction knows how to perform.
+var knownActionTypes = []types.ActionType{
+	types.RemoveAttribute,
+	types.RemoveBlock,
+	types.SetAttributeValue,
+	types.RemoveAllBlocksOfType,
+	types.RemoveAllNestedBlocksMatchingPath,
+	types.RemoveListElementsMatching,
+	types.ReportWarning,
+	types.CommentOutAttribute,
+	types.RenameAttribute,
+	types.RemoveAttributeFromAllMatchingBlocks,
+	types.RemoveEmptyBlocksMatchingPath,
+	types.RemoveDuplicateNestedBlocks,
+	types.RemoveMapEntry,
+	types.RewriteAttributeWithRegex,
+	types.RemoveListElement,
+}
+
+// Validate statically checks rulesToValidate for mistakes that would otherwise only show up, if at all, as warnings
+// while the rules are applied: unknown condition and action types, missing required fields and invalid regular
+// expressions. It returns one error per problem found, or nil if every rule is valid.
+func Validate(rulesToValidate []types.Rule) []error {
+	var errs []error
+	for _, rule := range rulesToValidate {
+		errs = append(errs, validateRule(rule)...)
+	}
+	return errs
+}
+
+// validateRule returns the problems found in rule, each prefixed with the rule's name.
+func validateRule(rule types.Rule) []error {
+	var problems []error
+	if rule.Name == "" {
+		problems = append(problems, fmt.Errorf("name is empty"))
+	}
+	if rule.TargetResourceType == "" {
+		problems = append(problems, fmt.Errorf("TargetResourceType is empty"))
+	}
+	if rule.TargetBlockType != "" && rule.TargetBlockType != "resource" && rule.TargetBlockType != "data" {
+		problems = append(problems, fmt.Errorf("unknown TargetBlockType %q", rule.TargetBlockType))
+	}
+	if rule.TargetResourceLabelPattern != "" {
+		if _, err := regexp.Compile(rule.TargetResourceLabelPattern); err != nil {
+			problems = append(problems, fmt.Errorf("invalid TargetResourceLabelPattern: %w", err))
+		}
+	}
+	switch rule.ExecutionType {
+	case "", types.RuleExecutionStandard:
+	case types.RuleExecutionForEachNestedBlock:
+		if rule.NestedBlockTargetType == "" {
+			problems = append(problems, fmt.Errorf("NestedBlockTargetType is required for ForEachNestedBlock rules"))
+		}
+	default:
+		problems = append(problems, fmt.Errorf("unknown ExecutionType %q", rule.ExecutionType))
+	}
+	problems = append(problems, validateConditions("condition", rule.Conditions)...)
+	problems = append(problems, validateConditions("resource condition", rule.ResourceConditions)...)
+	if len(rule.Actions) == 0 {
+		problems = append(problems, fmt.Errorf("no actions"))
+	}
+	for i, action := range rule.Actions {
+		for _, problem := range validateAction(action) {
+			problems = append(problems, fmt.Errorf("action %d (%s): %w", i, action.Type, problem))
+		}
+	}
+
+	errs := make([]error, 0, len(problems))
+	for _, problem := range problems {
+		errs = append(errs, fmt.Errorf("rule %q: %w", rule.Name, problem))
+	}
+	return errs
+}
+
+// validateConditions returns the problems found in conditions; kind names them in the errors.
+func validateConditions(kind string, conditions []types.RuleCondition) []error {
+	var problems []error
+	for i, condition := range conditions {
+		for _, problem := range validateCondition(condition) {
+			problems = append(problems, fmt.Errorf("%s %d (%s): %w", kind, i, condition.Type, problem))
+		}
+	}
+	return problems
+}
+
+// validateCondition returns the problems found in condition.
+func validateCondition(condition types.RuleCondition) []error {
+	if !slices.Contains(knownConditionTypes, condition.Type) {
+		return []error{fmt.Errorf("unknown condition type")}
+	}
+
+	var problems []error
+	switch condition.Type {
+	case types.AttributeExistsAnyOf:
+		if len(condition.Paths) == 0 {
+			problems = append(problems, fmt.Errorf("Paths is empty"))
+		}
+		for j, path := range condition.Paths {
+			if len(path) == 0 {
+				problems = append(problems, fmt.Errorf("Paths[%d] is empty", j))
+			}
+		}
+		return problems
+	case types.AttributeValueEqualsPath, types.VersionLessThan:
+		if len(condition.ComparePath) == 0 {
+			problems = append(problems, fmt.Errorf("ComparePath is empty"))
+		}
+	case types.ListValuesEqual, types.MapKeysEqual:
+		if len(condition.ExpectedValues) == 0 {
+			problems = append(problems, fmt.Errorf("ExpectedValues is empty"))
+		}
+	case types.BlockMatchesDefaults:
+		if len(condition.Defaults) == 0 {
+			problems = append(problems, fmt.Errorf("Defaults is empty"))
+		}
+	case types.AttributeValueMatches:
+		if _, err := regexp.Compile(condition.ExpectedValue); err != nil {
+			problems = append(problems, fmt.Errorf("invalid ExpectedValue pattern: %w", err))
+		}
+	}
+	if len(condition.Path) == 0 {
+		problems = append(problems, fmt.Errorf("Path is empty"))
+	}
+	return problems
+}
+
+// validateAction returns the problems found in action.
+func validateAction(action types.RuleAction) []error {
+	if !slices.Contains(knownActionTypes, action.Type) {
+		return []error{fmt.Errorf("unknown action type")}
+	}
+
+	var problems []error
+	switch action.Type {
+	case types.RemoveAllBlocksOfType:
+		if action.BlockTypeToRemove == "" {
+			problems = append(problems, fmt.Errorf("BlockTypeToRemove is empty"))
+		}
+	case types.ReportWarning:
+		if action.Message == "" {
+			problems = append(problems, fmt.Errorf("Message is empty"))
+		}
+	default:
+		if len(action.Path) == 0 {
+			problems = append(problems, fmt.Errorf("Path is empty"))
+		}
+	}
+	switch action.Type {
+	case types.RenameAttribute:
+		if action.NewName == "" {
+			problems = append(problems, fmt.Errorf("NewName is empty"))
+		}
+	case types.RemoveListElement:
+		if action.ElementValue == "" && len(action.ElementValuePath) == 0 {
+			problems = append(problems, fmt.Errorf("ElementValue and ElementValuePath are both empty"))
+		}
+	case types.RemoveMapEntry:
+		if action.MapKey == "" {
+			problems = append(problems, fmt.Errorf("MapKey is empty"))
+		}
+	case types.RemoveListElementsMatching, types.RewriteAttributeWithRegex:
+		if _, err := regexp.Compile(action.Pattern); err != nil {
+			problems = append(problems, fmt.Errorf("invalid Pattern: %w", err))
+		}
+	}
+	problems = append(problems, validateConditions("block condition", action.BlockConditions)...)
+	return problems
+}
@@ -0,0 +1,13 @@
+package rules
+
+// VerticalPodAutoscalingRuleDefinition defines a rule that removes the `vertical_pod_autoscaling` block from
+// `google_container_cluster` resources when Vertical Pod Autoscaling is disabled.
+//
+// What it does: If `vertical_pod_autoscaling.enabled` is `false`, the whole `vertical_pod_autoscaling` block is removed.
+// The block is kept when `enabled = true`.
+//
+// Why it's necessary for GKE imports: clusters without VPA are imported with `vertical_pod_autoscaling { enabled = false }`,
+// which matches the default and only adds noise to the configuration.
+var VerticalPodAutoscalingRuleDefinition = createRemoveBlockWhenAttrEqualsRule(
+	"Vertical Pod Autoscaling Rule: Remove vertical_pod_autoscaling block if enabled is false",
+	"google_container_cluster", []string{"vertical_pod_autoscaling"}, []string{"enabled"}, "false")
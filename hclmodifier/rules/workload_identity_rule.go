@@ -0,0 +1,100 @@
+package rules
+
+import (
+	"github.com/kotatut/cluster_import_cleaner/hclmodifier/types"
+)
+
+// WorkloadIdentityNamespaceRuleDefinition defines a rule that migrates the deprecated
+// `workload_identity_config.identity_namespace` attribute of `google_container_cluster` resources.
+//
+// What it does: If `workload_identity_config` contains `identity_namespace`, the attribute is renamed
+// to `workload_pool`, keeping its value.
+//
+// Why it's necessary for GKE imports: Older provider versions used `identity_namespace`, which newer
+// provider versions have replaced with `workload_pool`. Configurations written for the old name fail to plan.
+var WorkloadIdentityNamespaceRuleDefinition = types.Rule{
+	Name:               "Workload Identity Rule: Rename deprecated identity_namespace to workload_pool in workload_identity_config",
+	TargetResourceType: "google_container_cluster",
+	Conditions: []types.RuleCondition{
+		{
+			Type: types.AttributeExists,
+			Path: []string{"workload_identity_config", "identity_namespace"},
+		},
+		{
+			Type: types.AttributeDoesntExist,
+			Path: []string{"workload_identity_config", "workload_pool"},
+		},
+	},
+	Actions: []types.RuleAction{
+		{
+			Type:    types.RenameAttribute,
+			Path:    []string{"workload_identity_config", "identity_namespace"},
+			NewName: "workload_pool",
+		},
+	},
+}
+
+// WorkloadIdentityDuplicateNamespaceRuleDefinition defines a rule that removes the deprecated
+// `workload_identity_config.identity_namespace` attribute of `google_container_cluster` resources when
+// `workload_pool` is also set.
+//
+// What it does: If `workload_identity_config` contains both `identity_namespace` and `workload_pool`,
+// `identity_namespace` is removed. If only `identity_namespace` is set, it is left for
+// WorkloadIdentityNamespaceRuleDefinition to rename.
+//
+// Why it's necessary for GKE imports: Imported configurations may carry both attributes, and the provider
+// rejects setting the deprecated one alongside its replacement.
+var WorkloadIdentityDuplicateNamespaceRuleDefinition = types.Rule{
+	Name:               "Workload Identity Rule: Remove deprecated identity_namespace when workload_pool is set in workload_identity_config",
+	TargetResourceType: "google_container_cluster",
+	Conditions: []types.RuleCondition{
+		{
+			Type: types.AttributeExists,
+			Path: []string{"workload_identity_config", "identity_namespace"},
+		},
+		{
+			Type: types.AttributeExists,
+			Path: []string{"workload_identity_config", "workload_pool"},
+		},
+	},
+	Actions: []types.RuleAction{
+		{
+			Type: types.RemoveAttribute,
+			Path: []string{"workload_identity_config", "identity_namespace"},
+		},
+	},
+}
+
+// WorkloadIdentityOauthScopesRuleDefinition defines a rule that removes `node_config.oauth_scopes` from every
+// `node_pool` block of a `google_container_cluster` resource with Workload Identity enabled, i.e. whose
+// `workload_identity_config` block sets `workload_pool`.
+//
+// Why it's necessary for GKE imports: with Workload Identity, workloads authenticate as their own service
+// accounts, so the broad scope list written back on import for every node pool is unnecessary and causes diffs.
+// Clusters without Workload Identity keep their scopes, as their workloads still rely on them.
+var WorkloadIdentityOauthScopesRuleDefinition = types.Rule{
+	Name:                  "Workload Identity Rule: Remove node_config.oauth_scopes from node_pools when workload_pool is set",
+	TargetResourceType:    "google_container_cluster",
+	ExecutionType:         types.RuleExecutionForEachNestedBlock,
+	NestedBlockTargetType: "node_pool",
+	ResourceConditions: []types.RuleCondition{
+		{
+			Type: types.AttributeExists,
+			Path: []string{"workload_identity_config", "workload_pool"},
+		},
+	},
+	Conditions: []types.RuleCondition{
+		{
+			Type: types.AttributeExists,
+			Path: []string{"node_config", "oauth_scopes"},
+		},
+	},
+	Actions: []types.RuleAction{
+		{
+			Type: types.RemoveAttribute,
+			Path: []string{"node_config", "oauth_scopes"},
+		},
+	},
+	// identity_namespace must first be renamed to workload_pool.
+	RunAfter: []string{WorkloadIdentityNamespaceRuleDefinition.Name},
+}
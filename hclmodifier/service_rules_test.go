@@ -202,3 +202,30 @@ func TestApplyMonitoringServiceRule(t *testing.T) {
 		})
 	}
 }
+
+func TestApplyManagedPrometheusRule(t *testing.T) {
+	tests := []struct {
+		name                  string
+		fixture               string
+		expectedModifications int
+	}{
+		{
+			name:                  "Disabled managed_prometheus is removed",
+			fixture:               "testdata/TestApplyManagedPrometheusRule_Disabled.tf",
+			expectedModifications: 1,
+		},
+		{
+			name:                  "Enabled managed_prometheus is kept",
+			fixture:               "testdata/TestApplyManagedPrometheusRule_Enabled.tf",
+			expectedModifications: 0,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			modifier, modifications := applyRulesToFixture(t, tc.fixture, []types.Rule{rules.ManagedPrometheusDisabledRule})
+			assert.Equal(t, tc.expectedModifications, modifications)
+			assertMatchesGolden(t, modifier, tc.fixture+".golden")
+		})
+	}
+}
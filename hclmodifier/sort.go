@@ -0,0 +1,137 @@
+package hclmodifier
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"go.uber.org/zap"
+)
+
+// SortResourceAttributes reorders the top-level attributes of every `google_container_cluster` resource
+// alphabetically. Nested blocks keep their positions among the attributes, and attributes keep their comments.
+// Blank lines separating items are kept at the same positions.
+//
+// The file is re-parsed afterwards, so blocks and attributes obtained from the Modifier before the call must
+// not be used anymore. Returns the number of resources whose attributes were reordered.
+func (m *Modifier) SortResourceAttributes() (int, error) {
+	syntaxFile, diags := hclsyntax.ParseConfig(m.file.Bytes(), "", hcl.Pos{Line: 1, Column: 1})
+	if diags.HasErrors() {
+		return 0, fmt.Errorf("failed to parse HCL content for sorting: %w", diags)
+	}
+	// Work on a copy, so the file is left untouched if anything goes wrong.
+	working := m.Clone()
+	if working == nil {
+		return 0, fmt.Errorf("failed to copy HCL content for sorting")
+	}
+	syntaxBlocks := syntaxFile.Body.(*hclsyntax.Body).Blocks
+	blocks := working.file.Body().Blocks()
+	if len(syntaxBlocks) != len(blocks) {
+		return 0, fmt.Errorf("failed to match %d parsed blocks with %d blocks", len(syntaxBlocks), len(blocks))
+	}
+
+	sorted := 0
+	for i, block := range blocks {
+		if block.Type() != "resource" || len(block.Labels()) == 0 || block.Labels()[0] != "google_container_cluster" {
+			continue
+		}
+		if sortBodyAttributes(block.Body(), syntaxBlocks[i].Body) {
+			m.Logger.Debug("SortResourceAttributes: Reordered attributes.", zap.Strings("blockLabels", block.Labels()))
+			sorted++
+		}
+	}
+	if sorted == 0 {
+		return 0, nil
+	}
+
+	// Attributes were re-added as raw tokens; parse again so they are attributes once more.
+	hclFile, diags := hclwrite.ParseConfig(working.file.Bytes(), "", hcl.Pos{Line: 1, Column: 1})
+	if diags.HasErrors() {
+		return 0, fmt.Errorf("failed to re-parse sorted HCL content: %w", diags)
+	}
+	m.file = hclFile
+	m.Logger.Info("Sorted resource attributes", zap.Int("resourcesSorted", sorted))
+	return sorted, nil
+}
+
+// bodyItem is an attribute or a nested block of a body, located by its source lines.
+type bodyItem struct {
+	attributeName string
+	blockIndex    int
+	startByte     int
+	startLine     int
+	endLine       int
+}
+
+// sortBodyAttributes rebuilds body with its attributes in alphabetical order, using syntaxBody (the same body
+// parsed by hclsyntax) to find where attributes and blocks are. Reports whether the order changed.
+func sortBodyAttributes(body *hclwrite.Body, syntaxBody *hclsyntax.Body) bool {
+	attributes := body.Attributes()
+	blocks := body.Blocks()
+	if len(syntaxBody.Blocks) != len(blocks) {
+		return false
+	}
+
+	var items []bodyItem
+	for name, attr := range syntaxBody.Attributes {
+		items = append(items, bodyItem{
+			attributeName: name,
+			startByte:     attr.SrcRange.Start.Byte,
+			startLine:     attr.SrcRange.Start.Line - leadCommentLines(attributes[name].BuildTokens(nil)),
+			endLine:       attr.SrcRange.End.Line,
+		})
+	}
+	for i, block := range syntaxBody.Blocks {
+		items = append(items, bodyItem{
+			blockIndex: i,
+			startByte:  block.Range().Start.Byte,
+			startLine:  block.Range().Start.Line - leadCommentLines(blocks[i].BuildTokens(nil)),
+			endLine:    block.Range().End.Line,
+		})
+	}
+	sort.Slice(items, func(i, j int) bool { return items[i].startByte < items[j].startByte })
+
+	var names []string
+	for _, item := range items {
+		if item.attributeName != "" {
+			names = append(names, item.attributeName)
+		}
+	}
+	sortedNames := slices.Clone(names)
+	sort.Strings(sortedNames)
+	if slices.Equal(names, sortedNames) {
+		return false
+	}
+
+	// The body starts with the newline after the block's opening brace.
+	body.Clear()
+	body.AppendNewline()
+	nextName := 0
+	for i, item := range items {
+		if i > 0 && item.startLine-items[i-1].endLine > 1 {
+			body.AppendNewline()
+		}
+		if item.attributeName != "" {
+			body.AppendUnstructuredTokens(attributes[sortedNames[nextName]].BuildTokens(nil))
+			nextName++
+		} else {
+			body.AppendBlock(blocks[item.blockIndex])
+		}
+	}
+	return true
+}
+
+// leadCommentLines counts the comment lines at the start of tokens, i.e. the lead comments of an attribute or block.
+func leadCommentLines(tokens hclwrite.Tokens) int {
+	lines := 0
+	for _, token := range tokens {
+		if token.Type != hclsyntax.TokenComment {
+			break
+		}
+		lines++
+	}
+	return lines
+}
@@ -0,0 +1,74 @@
+package hclmodifier
+
+import (
+	"testing"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSortResourceAttributes(t *testing.T) {
+	t.Run("Attributes are sorted around nested blocks", func(t *testing.T) {
+		modifier := newTestModifier(t, `resource "google_container_cluster" "primary" {
+  name     = "my-cluster"
+  location = "us-central1"
+  # Keep the default pool small.
+  initial_node_count = 1
+
+  node_pool {
+    name       = "pool"
+    node_count = 1
+  }
+  deletion_protection = false
+  enable_autopilot    = false # Standard cluster
+}
+
+resource "google_compute_network" "vpc" {
+  name                    = "vpc"
+  auto_create_subnetworks = false
+}
+`)
+		sorted, err := modifier.SortResourceAttributes()
+		assert.NoError(t, err)
+		assert.Equal(t, 1, sorted)
+
+		output := modifier.File().Bytes()
+		assert.Equal(t, `resource "google_container_cluster" "primary" {
+  deletion_protection = false
+  enable_autopilot    = false # Standard cluster
+  # Keep the default pool small.
+  initial_node_count = 1
+
+  node_pool {
+    name       = "pool"
+    node_count = 1
+  }
+  location = "us-central1"
+  name     = "my-cluster"
+}
+
+resource "google_compute_network" "vpc" {
+  name                    = "vpc"
+  auto_create_subnetworks = false
+}
+`, string(output))
+
+		_, diags := hclwrite.ParseConfig(output, "sorted.tf", hcl.InitialPos)
+		assert.False(t, diags.HasErrors(), "Sorted output must remain parseable: %v", diags)
+		assert.NotNil(t, modifier.File().Body().Blocks()[0].Body().GetAttribute("location"), "Sorted attributes must still be attributes")
+	})
+
+	t.Run("Already sorted resource is left unchanged", func(t *testing.T) {
+		const hclContent = `resource "google_container_cluster" "primary" {
+  location = "us-central1"
+  name     = "my-cluster"
+}
+`
+		modifier := newTestModifier(t, hclContent)
+		sorted, err := modifier.SortResourceAttributes()
+		assert.NoError(t, err)
+		assert.Equal(t, 0, sorted)
+		assert.Equal(t, hclContent, string(modifier.File().Bytes()))
+	})
+}
@@ -0,0 +1,48 @@
+package hclmodifier
+
+import (
+	"github.com/hashicorp/hcl/v2/hclwrite"
+)
+
+// FileStats holds structural metrics about an HCL file, as returned by Modifier.Stats.
+type FileStats struct {
+	// Resources is the number of top-level `resource` blocks.
+	Resources int
+	// Clusters is the number of `google_container_cluster` resources.
+	Clusters int
+	// NodePools counts both `node_pool` blocks nested in clusters and `google_container_node_pool` resources.
+	NodePools int
+	// Attributes is the number of attributes in all blocks, at any depth.
+	Attributes int
+	// Blocks is the number of blocks at any depth, top-level blocks included.
+	Blocks int
+	// MaxBlockDepth is the deepest block nesting level; a top-level block has depth 1.
+	MaxBlockDepth int
+}
+
+// Stats walks the file and returns its structural metrics. It never modifies the file.
+func (m *Modifier) Stats() FileStats {
+	var stats FileStats
+	m.Walk(func(block *hclwrite.Block, path []string) bool {
+		stats.Blocks++
+		stats.Attributes += len(block.Body().Attributes())
+		stats.MaxBlockDepth = max(stats.MaxBlockDepth, len(path))
+
+		if len(path) == 1 && block.Type() == "resource" {
+			stats.Resources++
+			if len(block.Labels()) > 0 {
+				switch block.Labels()[0] {
+				case "google_container_cluster":
+					stats.Clusters++
+				case "google_container_node_pool":
+					stats.NodePools++
+				}
+			}
+		}
+		if len(path) == 2 && path[0] == "resource" && block.Type() == "node_pool" {
+			stats.NodePools++
+		}
+		return true
+	})
+	return stats
+}
@@ -0,0 +1,92 @@
+package hclmodifier
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStats(t *testing.T) {
+	t.Run("Two clusters with five node pools", func(t *testing.T) {
+		modifier := newTestModifier(t, `resource "google_container_cluster" "primary" {
+  name     = "primary"
+  location = "us-central1"
+  node_pool {
+    name = "default-pool"
+    node_config {
+      machine_type = "e2-medium"
+      guest_accelerator {
+        type  = "nvidia-tesla-t4"
+        count = 1
+      }
+    }
+  }
+  node_pool {
+    name = "gpu-pool"
+  }
+}
+
+resource "google_container_cluster" "secondary" {
+  name = "secondary"
+  node_pool {
+    name = "pool-a"
+  }
+  node_pool {
+    name = "pool-b"
+  }
+}
+
+resource "google_container_node_pool" "extra" {
+  name    = "extra"
+  cluster = "secondary"
+}
+
+variable "region" {
+  default = "us-central1"
+}`)
+		assert.Equal(t, FileStats{
+			Resources:     3,
+			Clusters:      2,
+			NodePools:     5,
+			Attributes:    13,
+			Blocks:        10,
+			MaxBlockDepth: 4,
+		}, modifier.Stats())
+	})
+
+	t.Run("Empty file", func(t *testing.T) {
+		modifier := newTestModifier(t, "")
+		assert.Equal(t, FileStats{}, modifier.Stats())
+	})
+}
+
+func TestWalk(t *testing.T) {
+	modifier := newTestModifier(t, `resource "google_container_cluster" "primary" {
+  node_pool {
+    node_config {
+      name = "a"
+    }
+  }
+  addons_config {}
+}`)
+
+	t.Run("Visits every block with its path", func(t *testing.T) {
+		var visited []string
+		modifier.Walk(func(block *hclwrite.Block, path []string) bool {
+			visited = append(visited, strings.Join(path, "."))
+			return true
+		})
+		assert.Equal(t, []string{"resource", "resource.node_pool", "resource.node_pool.node_config", "resource.addons_config"}, visited)
+	})
+
+	t.Run("Returning false skips nested blocks", func(t *testing.T) {
+		var visited []string
+		modifier.Walk(func(block *hclwrite.Block, path []string) bool {
+			visited = append(visited, strings.Join(path, "."))
+			return block.Type() != "node_pool"
+		})
+		assert.Equal(t, []string{"resource", "resource.node_pool", "resource.addons_config"}, visited)
+	})
+}
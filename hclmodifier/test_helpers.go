@@ -2,11 +2,19 @@ package hclmodifier
 
 import (
 	"fmt"
+	"os"
+	"slices"
+	"strings"
 	"testing"
 
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
 	"github.com/hashicorp/hcl/v2/hclwrite"
 	"github.com/stretchr/testify/assert"
 	"github.com/zclconf/go-cty/cty"
+	"go.uber.org/zap"
+
+	"github.com/kotatut/cluster_import_cleaner/hclmodifier/types"
 )
 
 func findBlockInParsedFile(file *hclwrite.File, blockType string, blockName string) (*hclwrite.Block, error) {
@@ -28,15 +36,13 @@ func assertAttributeValue(t *testing.T, modifier *Modifier, block *hclwrite.Bloc
 }
 
 func findNodePoolInBlock(gkeBlock *hclwrite.Block, nodePoolName string, modifier *Modifier) (*hclwrite.Block, error) {
-	for _, block := range gkeBlock.Body().Blocks() {
-		if block.Type() == "node_pool" {
-			value, err := modifier.GetAttributeValue(block.Body().GetAttribute("name"))
-			if err != nil {
-				continue
-			}
-			if value.AsString() == nodePoolName {
-				return block, nil
-			}
+	for _, block := range modifier.GetAllBlocksOfType(gkeBlock.Body(), "node_pool") {
+		value, err := modifier.GetAttributeValue(block.Body().GetAttribute("name"))
+		if err != nil {
+			continue
+		}
+		if value.AsString() == nodePoolName {
+			return block, nil
 		}
 	}
 	return nil, fmt.Errorf("node pool not found")
@@ -98,3 +104,64 @@ func assertNodePoolAttributeValue(t *testing.T, modifier *Modifier, gkeResourceN
 func intPtr(i int) *int {
 	return &i
 }
+
+// applyRulesToFixture parses the HCL fixture at fixturePath, applies rulesToApply to it and
+// fails the test if any rule returns an error. It returns the modifier and the modification count.
+func applyRulesToFixture(t *testing.T, fixturePath string, rulesToApply []types.Rule) (*Modifier, int) {
+	t.Helper()
+	modifier, err := NewFromFile(fixturePath, zap.NewNop())
+	if err != nil {
+		t.Fatalf("NewFromFile(%s) error = %v", fixturePath, err)
+	}
+	modifications, errs := modifier.ApplyRules(rulesToApply)
+	if len(errs) > 0 {
+		t.Fatalf("ApplyRules() on %s returned errors: %v", fixturePath, errs)
+	}
+	return modifier, modifications
+}
+
+// assertMatchesGolden compares the serialized HCL of modifier with the content of goldenPath.
+func assertMatchesGolden(t *testing.T, modifier *Modifier, goldenPath string) {
+	t.Helper()
+	expected, err := os.ReadFile(goldenPath)
+	if err != nil {
+		t.Fatalf("Failed to read golden file %s: %v", goldenPath, err)
+	}
+	assert.Equal(t, string(expected), string(modifier.File().Bytes()), "HCL content mismatch with %s", goldenPath)
+}
+
+// assertCommentsRemoved checks that the comments of the HCL at originalPath, once cleaned by modifier, are all kept
+// in order, except for removedComments. Comments are compared without surrounding whitespace.
+func assertCommentsRemoved(t *testing.T, modifier *Modifier, originalPath string, removedComments ...string) {
+	t.Helper()
+	original, err := os.ReadFile(originalPath)
+	if err != nil {
+		t.Fatalf("Failed to read %s: %v", originalPath, err)
+	}
+	var expected []string
+	for _, comment := range commentsOf(t, original) {
+		if removedIndex := slices.Index(removedComments, comment); removedIndex >= 0 {
+			removedComments = slices.Delete(removedComments, removedIndex, removedIndex+1)
+			continue
+		}
+		expected = append(expected, comment)
+	}
+	assert.Empty(t, removedComments, "comments expected to be removed are not in %s", originalPath)
+	assert.Equal(t, expected, commentsOf(t, modifier.File().Bytes()), "unexpected comments after cleaning %s", originalPath)
+}
+
+// commentsOf returns the text of every comment of the HCL src, in order.
+func commentsOf(t *testing.T, src []byte) []string {
+	t.Helper()
+	tokens, diags := hclsyntax.LexConfig(src, "", hcl.InitialPos)
+	if diags.HasErrors() {
+		t.Fatalf("Failed to lex HCL: %v", diags)
+	}
+	var comments []string
+	for _, token := range tokens {
+		if token.Type == hclsyntax.TokenComment {
+			comments = append(comments, strings.TrimSpace(string(token.Bytes)))
+		}
+	}
+	return comments
+}
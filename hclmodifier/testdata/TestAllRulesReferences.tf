@@ -0,0 +1,108 @@
+locals {
+  labels = { team = "platform" }
+}
+
+resource "google_container_cluster" "primary" {
+  name                     = var.cluster_name
+  location                 = var.location
+  project                  = var.project
+  node_locations           = var.zones
+  network                  = var.network
+  subnetwork               = var.subnetwork
+  min_master_version       = var.master_version
+  node_version             = var.node_version
+  cluster_ipv4_cidr        = var.cluster_cidr
+  resource_labels          = local.labels
+  enable_autopilot         = var.autopilot
+  logging_service          = var.logging_service
+  monitoring_service       = var.monitoring_service
+  remove_default_node_pool = var.remove_default_node_pool
+  initial_node_count       = var.initial_node_count
+  networking_mode          = var.networking_mode
+  default_max_pods_per_node = var.max_pods
+  enable_l4_ilb_subsetting = var.l4_ilb_subsetting
+  enable_legacy_abac       = var.legacy_abac
+  enable_shielded_nodes    = var.shielded_nodes
+  enable_kubernetes_alpha  = var.alpha
+  enable_tpu               = var.tpu
+  datapath_provider        = var.datapath_provider
+  deletion_protection      = var.deletion_protection
+
+  ip_allocation_policy {
+    cluster_ipv4_cidr_block  = var.pods_cidr
+    services_ipv4_cidr_block = var.services_cidr
+    stack_type               = var.stack_type
+  }
+
+  private_cluster_config {
+    enable_private_nodes    = var.private_nodes
+    enable_private_endpoint = var.private_endpoint
+    master_ipv4_cidr_block  = var.master_cidr
+  }
+
+  master_authorized_networks_config {
+    cidr_blocks {
+      cidr_block = var.admin_cidr
+    }
+  }
+
+  binary_authorization {
+    enabled         = var.binauthz
+    evaluation_mode = var.binauthz_mode
+  }
+
+  release_channel {
+    channel = var.release_channel
+  }
+
+  workload_identity_config {
+    workload_pool = var.workload_pool
+  }
+
+  dns_config {
+    cluster_dns       = var.cluster_dns
+    cluster_dns_scope = var.cluster_dns_scope
+  }
+
+  node_config {
+    disk_size_gb    = var.disk_size
+    disk_type       = var.disk_type
+    image_type      = var.image_type
+    machine_type    = var.machine_type
+    metadata        = var.metadata
+    tags            = var.tags
+    labels          = local.labels
+    oauth_scopes    = var.oauth_scopes
+    service_account = var.service_account
+  }
+
+  node_pool {
+    name               = var.pool_name
+    initial_node_count = var.pool_initial_node_count
+    node_count         = var.pool_node_count
+    node_locations     = var.pool_zones
+    version            = var.pool_version
+
+    node_config {
+      disk_size_gb    = var.disk_size
+      image_type      = var.image_type
+      metadata        = var.metadata
+      tags            = var.tags
+      labels          = local.labels
+      resource_labels = local.labels
+      oauth_scopes    = var.oauth_scopes
+      service_account = var.service_account
+    }
+
+    management {
+      auto_repair  = var.auto_repair
+      auto_upgrade = var.auto_upgrade
+    }
+  }
+}
+
+resource "google_container_cluster" "zonal" {
+  name           = var.cluster_name
+  location       = "us-central1-a"
+  node_locations = var.zones
+}
@@ -0,0 +1,15 @@
+resource "google_container_cluster" "primary" {
+  name     = "primary-cluster"
+  location = "us-central1"
+
+  cluster_autoscaling {
+    enabled = true
+
+    auto_provisioning_defaults {
+      service_account = "nap-nodes@my-project.iam.gserviceaccount.com"
+      oauth_scopes = [
+        "https://www.googleapis.com/auth/cloud-platform",
+      ]
+    }
+  }
+}
@@ -0,0 +1,7 @@
+resource "google_container_cluster" "primary" {
+  name             = "my-cluster"
+  location         = "us-central1"
+  enable_autopilot = true
+  ip_allocation_policy {
+  }
+}
@@ -0,0 +1,9 @@
+resource "google_container_cluster" "primary" {
+  name             = "my-cluster"
+  location         = "us-central1"
+  enable_autopilot = true
+  ip_allocation_policy {
+    cluster_secondary_range_name  = "pods"
+    services_secondary_range_name = "services"
+  }
+}
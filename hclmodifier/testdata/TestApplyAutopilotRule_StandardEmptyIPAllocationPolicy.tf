@@ -0,0 +1,6 @@
+resource "google_container_cluster" "primary" {
+  name     = "my-cluster"
+  location = "us-central1"
+  ip_allocation_policy {
+  }
+}
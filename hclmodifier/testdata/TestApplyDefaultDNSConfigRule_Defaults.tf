@@ -0,0 +1,10 @@
+resource "google_container_cluster" "primary" {
+  name     = "primary-cluster"
+  location = "us-central1"
+
+  dns_config {
+    cluster_dns        = "PROVIDER_UNSPECIFIED"
+    cluster_dns_scope  = "DNS_SCOPE_UNSPECIFIED"
+    cluster_dns_domain = ""
+  }
+}
@@ -0,0 +1,10 @@
+resource "google_container_cluster" "primary" {
+  name                      = "primary-cluster"
+  location                  = "us-central1"
+  default_max_pods_per_node = 110
+
+  ip_allocation_policy {
+    cluster_secondary_range_name  = "pods"
+    services_secondary_range_name = "services"
+  }
+}
@@ -0,0 +1,4 @@
+resource "google_container_cluster" "primary" {
+  name     = "my-cluster"
+  location = "us-central1"
+}
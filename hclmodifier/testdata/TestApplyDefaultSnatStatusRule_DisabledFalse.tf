@@ -0,0 +1,7 @@
+resource "google_container_cluster" "primary" {
+  name     = "my-cluster"
+  location = "us-central1"
+  default_snat_status {
+    disabled = false
+  }
+}
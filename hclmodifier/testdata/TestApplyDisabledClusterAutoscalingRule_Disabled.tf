@@ -0,0 +1,19 @@
+resource "google_container_cluster" "primary" {
+  name     = "my-cluster"
+  location = "us-central1"
+  cluster_autoscaling {
+    enabled = false
+    auto_provisioning_defaults {
+      disk_size = 0
+    }
+  }
+}
+
+resource "google_container_cluster" "with_profile" {
+  name     = "with-profile"
+  location = "us-central1"
+  cluster_autoscaling {
+    enabled             = false
+    autoscaling_profile = "OPTIMIZE_UTILIZATION"
+  }
+}
@@ -0,0 +1,8 @@
+resource "google_container_cluster" "primary" {
+  name     = "primary-cluster"
+  location = "us-central1"
+
+  master_authorized_networks_config {
+    gcp_public_cidrs_access_enabled = true
+  }
+}
@@ -0,0 +1,17 @@
+resource "google_container_cluster" "primary" {
+  name     = "my-cluster"
+  location = "us-central1"
+
+  node_pool {
+    name = "default-pool"
+
+    node_config {
+      local_ssd_count = 0
+
+      kubelet_config {
+        cpu_cfs_quota  = false
+        pod_pids_limit = 0
+      }
+    }
+  }
+}
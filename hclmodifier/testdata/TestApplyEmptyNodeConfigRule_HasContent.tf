@@ -0,0 +1,13 @@
+resource "google_container_cluster" "primary" {
+  name     = "my-cluster"
+  location = "us-central1"
+
+  node_pool {
+    name = "default-pool"
+
+    node_config {
+      machine_type    = "e2-standard-4"
+      local_ssd_count = 0
+    }
+  }
+}
@@ -0,0 +1,5 @@
+resource "google_container_cluster" "primary" {
+  name           = "primary-cluster"
+  location       = "us-central1-a"
+  node_locations = []
+}
@@ -0,0 +1,5 @@
+resource "google_container_cluster" "primary" {
+  name            = "my-cluster"
+  location        = "us-central1"
+  resource_labels = {}
+}
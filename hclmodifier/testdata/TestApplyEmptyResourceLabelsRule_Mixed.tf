@@ -0,0 +1,8 @@
+resource "google_container_cluster" "primary" {
+  name     = "my-cluster"
+  location = "us-central1"
+  resource_labels = {
+    "goog-managed-by" = "gke"
+    env               = "prod"
+  }
+}
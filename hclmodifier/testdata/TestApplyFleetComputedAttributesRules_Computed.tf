@@ -0,0 +1,12 @@
+resource "google_container_cluster" "primary" {
+  name     = "primary-cluster"
+  location = "us-central1"
+
+  fleet {
+    membership          = "//gkehub.googleapis.com/projects/my-project/locations/us-central1/memberships/primary-cluster"
+    membership_id       = "primary-cluster"
+    membership_location = "us-central1"
+    pre_registered      = false
+    project             = "my-project"
+  }
+}
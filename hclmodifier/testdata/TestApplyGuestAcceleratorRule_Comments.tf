@@ -0,0 +1,19 @@
+resource "google_container_cluster" "primary" {
+  name     = "my-cluster"
+  location = "us-central1"
+  node_pool {
+    name = "gpu-pool"
+    node_config {
+      machine_type = "a2-highgpu-2g"
+      guest_accelerator {
+        type  = "nvidia-tesla-a100"
+        count = 1
+        /* Computed by GKE. */
+        gpu_partition_size = ""
+        /* Left empty by the import. */
+        gpu_sharing_config {
+        }
+      }
+    }
+  }
+}
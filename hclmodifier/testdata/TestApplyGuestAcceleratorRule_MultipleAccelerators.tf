@@ -0,0 +1,32 @@
+resource "google_container_cluster" "primary" {
+  name     = "my-cluster"
+  location = "us-central1"
+  node_pool {
+    name = "gpu-pool"
+    node_config {
+      machine_type = "a2-highgpu-2g"
+      guest_accelerator {
+        type               = "nvidia-tesla-a100"
+        count              = 1
+        gpu_partition_size = ""
+        gpu_sharing_config {
+        }
+      }
+      guest_accelerator {
+        type               = "nvidia-tesla-a100"
+        count              = 1
+        gpu_partition_size = ""
+        gpu_sharing_config {
+          gpu_sharing_strategy       = "TIME_SHARING"
+          max_shared_clients_per_gpu = 2
+        }
+      }
+    }
+  }
+  node_pool {
+    name = "cpu-pool"
+    node_config {
+      machine_type = "e2-medium"
+    }
+  }
+}
@@ -0,0 +1,10 @@
+resource "google_container_cluster" "primary" {
+  name     = "my-cluster"
+  location = "us-central1"
+  node_pool {
+    name = "cpu-pool"
+    node_config {
+      machine_type = "e2-medium"
+    }
+  }
+}
@@ -0,0 +1,8 @@
+resource "google_container_cluster" "primary" {
+  name     = "primary-cluster"
+  location = "us-central1"
+
+  identity_service_config {
+    enabled = false
+  }
+}
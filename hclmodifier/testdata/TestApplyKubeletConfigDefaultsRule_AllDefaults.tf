@@ -0,0 +1,15 @@
+resource "google_container_cluster" "primary" {
+  name     = "my-cluster"
+  location = "us-central1"
+  node_pool {
+    name = "default-pool"
+    node_config {
+      machine_type = "e2-medium"
+      kubelet_config {
+        cpu_cfs_quota      = false
+        cpu_manager_policy = ""
+        pod_pids_limit     = 0
+      }
+    }
+  }
+}
@@ -0,0 +1,14 @@
+resource "google_container_cluster" "primary" {
+  name     = "my-cluster"
+  location = "us-central1"
+  node_pool {
+    name = "static-cpu-pool"
+    node_config {
+      machine_type = "n2-standard-8"
+      kubelet_config {
+        cpu_cfs_quota      = false
+        cpu_manager_policy = "static"
+      }
+    }
+  }
+}
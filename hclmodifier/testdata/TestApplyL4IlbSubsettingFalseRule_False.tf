@@ -0,0 +1,5 @@
+resource "google_container_cluster" "primary" {
+  name                     = "primary-cluster"
+  location                 = "us-central1"
+  enable_l4_ilb_subsetting = false
+}
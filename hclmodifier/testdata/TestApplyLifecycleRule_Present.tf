@@ -0,0 +1,15 @@
+resource "google_container_cluster" "primary" {
+  name     = "primary-cluster"
+  location = "us-central1"
+
+  lifecycle {
+    ignore_changes = [
+      node_config,
+      initial_node_count,
+    ]
+  }
+
+  node_pool {
+    name = "default-pool"
+  }
+}
@@ -0,0 +1,18 @@
+resource "google_container_cluster" "primary" {
+  name     = "my-cluster"
+  location = "us-central1"
+  node_pool {
+    name = "default-pool"
+    node_config {
+      machine_type    = "e2-medium"
+      local_ssd_count = 0
+    }
+  }
+  node_pool {
+    name = "ssd-pool"
+    node_config {
+      machine_type    = "n2-standard-8"
+      local_ssd_count = 2
+    }
+  }
+}
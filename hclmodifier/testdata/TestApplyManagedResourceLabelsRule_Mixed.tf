@@ -0,0 +1,11 @@
+resource "google_container_cluster" "primary" {
+  name     = "primary-cluster"
+  location = "us-central1"
+
+  resource_labels = {
+    env                        = "prod" # Set by the platform team
+    goog-terraform-provisioned = "true"
+    "goog-k8s-cluster-name"    = "primary-cluster"
+    team                       = "payments"
+  }
+}
@@ -0,0 +1,9 @@
+resource "google_container_cluster" "primary" {
+  name     = "primary-cluster"
+  location = "us-central1"
+
+  resource_labels = {
+    env     = "prod"
+    googler = "false"
+  }
+}
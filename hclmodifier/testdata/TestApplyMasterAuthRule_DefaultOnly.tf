@@ -0,0 +1,9 @@
+resource "google_container_cluster" "primary" {
+  name     = "my-cluster"
+  location = "us-central1"
+  master_auth {
+    client_certificate_config {
+      issue_client_certificate = false
+    }
+  }
+}
@@ -0,0 +1,20 @@
+resource "google_container_cluster" "primary" {
+  name     = "my-cluster"
+  location = "us-central1"
+  master_auth {
+    cluster_ca_certificate = "LS0tLS1CRUdJTi..."
+    client_certificate_config {
+      issue_client_certificate = false
+    }
+  }
+}
+
+resource "google_container_cluster" "legacy" {
+  name     = "legacy-cluster"
+  location = "us-central1"
+  master_auth {
+    client_certificate_config {
+      issue_client_certificate = true
+    }
+  }
+}
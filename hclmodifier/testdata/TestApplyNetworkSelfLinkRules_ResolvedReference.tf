@@ -0,0 +1,10 @@
+resource "google_compute_network" "main" {
+  name      = "main"
+  self_link = "https://www.googleapis.com/compute/v1/projects/my-project/global/networks/main"
+}
+
+resource "google_container_cluster" "primary" {
+  name     = "primary-cluster"
+  location = "us-central1"
+  network  = google_compute_network.main.self_link
+}
@@ -0,0 +1,6 @@
+resource "google_container_cluster" "primary" {
+  name       = "primary-cluster"
+  location   = "us-central1"
+  network    = "https://www.googleapis.com/compute/v1/projects/my-project/global/networks/default"
+  subnetwork = "https://www.googleapis.com/compute/v1/projects/my-project/regions/us-central1/subnetworks/default"
+}
@@ -0,0 +1,6 @@
+resource "google_container_cluster" "primary" {
+  name       = "primary-cluster"
+  location   = "us-central1"
+  network    = "default"
+  subnetwork = google_compute_subnetwork.default.self_link
+}
@@ -0,0 +1,11 @@
+resource "google_container_cluster" "primary" {
+  name     = "my-cluster"
+  location = "us-central1"
+  node_pool {
+    name = "default-pool"
+    node_config {
+      machine_type = "e2-medium"
+      tags         = ["gke-my-cluster-1a2b3c4d-node", "web", "gke-my-cluster-1a2b3c4d-default", "ssh"]
+    }
+  }
+}
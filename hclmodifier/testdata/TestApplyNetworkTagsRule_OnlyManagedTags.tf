@@ -0,0 +1,18 @@
+resource "google_container_cluster" "primary" {
+  name     = "my-cluster"
+  location = "us-central1"
+  node_pool {
+    name = "pool-one"
+    node_config {
+      machine_type = "e2-medium"
+      tags         = ["gke-my-cluster-1a2b3c4d-node"]
+    }
+  }
+  node_pool {
+    name = "pool-two"
+    node_config {
+      machine_type = "e2-standard-4"
+      tags         = ["gke-my-cluster-5e6f7a8b-node", "batch"]
+    }
+  }
+}
@@ -0,0 +1,10 @@
+resource "google_container_cluster" "primary" {
+  name     = "my-cluster"
+  location = "us-central1"
+  node_pool {
+    name = "default-pool"
+    node_config {
+      tags = ["web", "ssh"]
+    }
+  }
+}
@@ -0,0 +1,28 @@
+resource "google_container_cluster" "primary" {
+  name     = "my-cluster"
+  location = "us-central1"
+  node_pool {
+    name = "default-pool"
+    node_config {
+      machine_type = "e2-medium"
+      disk_size_gb = 100
+      disk_type    = "pd-balanced"
+    }
+  }
+  node_pool {
+    name = "big-disk-pool"
+    node_config {
+      machine_type = "n2-standard-8"
+      disk_size_gb = 500
+      disk_type    = "pd-balanced"
+    }
+  }
+  node_pool {
+    name = "ssd-pool"
+    node_config {
+      machine_type = "n2-standard-8"
+      disk_size_gb = 100
+      disk_type    = "pd-ssd"
+    }
+  }
+}
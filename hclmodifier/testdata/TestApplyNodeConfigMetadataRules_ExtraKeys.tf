@@ -0,0 +1,16 @@
+resource "google_container_cluster" "primary" {
+  name     = "primary-cluster"
+  location = "us-central1"
+
+  node_pool {
+    name = "default-pool"
+
+    node_config {
+      machine_type = "e2-medium"
+      metadata = {
+        disable-legacy-endpoints = "true"
+        startup-script           = "echo hello"
+      }
+    }
+  }
+}
@@ -0,0 +1,23 @@
+resource "google_container_cluster" "primary" {
+  name     = "primary"
+  location = "us-central1"
+
+  node_pool {
+    name = "default-pool"
+
+    network_config {
+      create_pod_range    = false
+      pod_ipv4_cidr_block = "10.4.0.0/14"
+      pod_range           = "pods"
+    }
+  }
+
+  node_pool {
+    name = "other-pool"
+
+    network_config {
+      pod_ipv4_cidr_block = "10.8.0.0/14"
+      pod_range           = "other-pods"
+    }
+  }
+}
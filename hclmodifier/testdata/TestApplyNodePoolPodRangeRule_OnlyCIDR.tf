@@ -0,0 +1,12 @@
+resource "google_container_cluster" "primary" {
+  name     = "primary"
+  location = "us-central1"
+
+  node_pool {
+    name = "default-pool"
+
+    network_config {
+      pod_ipv4_cidr_block = "10.4.0.0/14"
+    }
+  }
+}
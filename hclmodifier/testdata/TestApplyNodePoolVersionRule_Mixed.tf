@@ -0,0 +1,19 @@
+resource "google_container_cluster" "primary" {
+  name               = "primary-cluster"
+  location           = "us-central1"
+  min_master_version = "1.29.4-gke.1043002"
+
+  node_pool {
+    name    = "matching-pool"
+    version = "1.29.4-gke.1043002"
+  }
+
+  node_pool {
+    name    = "older-pool"
+    version = "1.28.9-gke.1000000"
+  }
+
+  node_pool {
+    name = "unversioned-pool"
+  }
+}
@@ -0,0 +1,9 @@
+resource "google_container_cluster" "primary" {
+  name     = "primary-cluster"
+  location = "us-central1"
+
+  node_pool {
+    name    = "default-pool"
+    version = "1.29.4-gke.1043002"
+  }
+}
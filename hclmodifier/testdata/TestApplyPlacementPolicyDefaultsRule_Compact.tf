@@ -0,0 +1,12 @@
+resource "google_container_cluster" "primary" {
+  name     = "primary-cluster"
+  location = "us-central1"
+
+  node_pool {
+    name = "hpc"
+
+    placement_policy {
+      type = "COMPACT"
+    }
+  }
+}
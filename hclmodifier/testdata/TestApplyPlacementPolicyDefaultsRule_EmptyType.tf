@@ -0,0 +1,18 @@
+resource "google_container_cluster" "primary" {
+  name     = "primary-cluster"
+  location = "us-central1"
+
+  node_pool {
+    name = "default-pool"
+
+    placement_policy {
+      type = ""
+    }
+  }
+
+  node_pool {
+    name = "batch"
+
+    placement_policy {}
+  }
+}
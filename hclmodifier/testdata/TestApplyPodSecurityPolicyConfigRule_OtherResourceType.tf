@@ -0,0 +1,12 @@
+resource "google_container_cluster" "primary" {
+  name     = "primary-cluster"
+  location = "us-central1"
+}
+
+resource "google_container_cluster_policy" "legacy" {
+  name = "legacy-policy"
+
+  pod_security_policy_config {
+    enabled = true
+  }
+}
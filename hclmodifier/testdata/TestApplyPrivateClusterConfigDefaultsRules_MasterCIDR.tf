@@ -0,0 +1,10 @@
+resource "google_container_cluster" "primary" {
+  name     = "primary-cluster"
+  location = "us-central1"
+
+  private_cluster_config {
+    enable_private_endpoint = false
+    enable_private_nodes    = false
+    master_ipv4_cidr_block  = "172.16.0.0/28"
+  }
+}
@@ -0,0 +1,13 @@
+resource "google_container_cluster" "primary" {
+  name     = "primary-cluster"
+  location = "us-central1"
+
+  private_cluster_config {
+    enable_private_endpoint = false
+    enable_private_nodes    = false
+
+    master_global_access_config {
+      enabled = true
+    }
+  }
+}
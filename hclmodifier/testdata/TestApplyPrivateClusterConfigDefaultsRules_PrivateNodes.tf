@@ -0,0 +1,9 @@
+resource "google_container_cluster" "primary" {
+  name     = "primary-cluster"
+  location = "us-central1"
+
+  private_cluster_config {
+    enable_private_endpoint = false
+    enable_private_nodes    = true
+  }
+}
@@ -0,0 +1,6 @@
+resource "google_container_cluster" "primary" {
+  name               = "primary-cluster"
+  location           = "us-central1"
+  min_master_version = "1.29.4-gke.1043002"
+  node_version       = "1.29.4-gke.1043002"
+}
@@ -0,0 +1,15 @@
+resource "google_container_cluster" "primary" {
+  name                     = "primary-cluster"
+  location                 = "us-central1"
+  remove_default_node_pool = true
+
+  node_pool {
+    name       = "system-pool"
+    node_count = 1
+  }
+
+  node_pool {
+    name       = "workers"
+    node_count = 3
+  }
+}
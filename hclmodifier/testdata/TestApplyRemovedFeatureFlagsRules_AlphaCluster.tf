@@ -0,0 +1,5 @@
+resource "google_container_cluster" "alpha" {
+  name                    = "alpha-cluster"
+  location                = "us-central1"
+  enable_kubernetes_alpha = true
+}
@@ -0,0 +1,10 @@
+resource "google_container_cluster" "primary" {
+  name       = "primary-cluster"
+  location   = "us-central1"
+  enable_tpu = true
+}
+
+resource "google_container_node_pool" "pool" {
+  name       = "pool"
+  enable_tpu = true
+}
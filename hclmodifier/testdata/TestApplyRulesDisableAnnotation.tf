@@ -0,0 +1,32 @@
+# gke-tf-cleaner:disable
+resource "google_container_cluster" "untouched" {
+  name     = "untouched"
+  location = "us-central1"
+  id       = "projects/p/locations/us-central1/clusters/untouched"
+  node_pool {
+    name               = "pool-1"
+    initial_node_count = 1
+  }
+}
+
+# Keep initial_node_count on purpose.
+# gke-tf-cleaner:disable=Initial Node Count Rule
+resource "google_container_cluster" "partial" {
+  name     = "partial"
+  location = "us-central1"
+  id       = "projects/p/locations/us-central1/clusters/partial"
+  node_pool {
+    name               = "pool-1"
+    initial_node_count = 1
+  }
+}
+
+resource "google_container_cluster" "cleaned" {
+  name     = "cleaned"
+  location = "us-central1"
+  id       = "projects/p/locations/us-central1/clusters/cleaned"
+  node_pool {
+    name               = "pool-1"
+    initial_node_count = 1
+  }
+}
@@ -0,0 +1,5 @@
+resource "google_container_cluster" "primary" {
+  name               = "primary-cluster"
+  node_version       = "1.27.8-gke.1067004"
+  min_master_version = "1.27.8-gke.200"
+}
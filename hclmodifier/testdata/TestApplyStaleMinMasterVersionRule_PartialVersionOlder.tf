@@ -0,0 +1,5 @@
+resource "google_container_cluster" "primary" {
+  name               = "primary-cluster"
+  node_version       = "1.28.3-gke.1286000"
+  min_master_version = "1.27"
+}
@@ -0,0 +1,7 @@
+resource "google_container_cluster" "primary" {
+  name                = "my-cluster"
+  operation           = "operation-123"
+  services_ipv4_cidr  = "10.4.0.0/20"
+  tpu_ipv4_cidr_block = "10.5.0.0/20"
+  master_version      = "1.30.1-gke.100"
+}
@@ -0,0 +1,5 @@
+resource "google_container_node_pool" "pool" {
+  name           = "pool"
+  operation      = "operation-123"
+  master_version = "1.30.1-gke.100"
+}
@@ -0,0 +1,36 @@
+resource "google_container_cluster" "primary" {
+  name     = "primary-cluster"
+  location = "us-central1"
+
+  node_pool {
+    name = "gpu-pool"
+
+    node_config {
+      machine_type = "n1-standard-4"
+
+      taint {
+        key    = "nvidia.com/gpu"
+        value  = "present"
+        effect = "NO_SCHEDULE"
+      }
+
+      taint {
+        key    = "dedicated"
+        value  = "ml"
+        effect = "NO_SCHEDULE"
+      }
+    }
+  }
+
+  node_pool {
+    name = "sandbox-pool"
+
+    node_config {
+      taint {
+        key    = "sandbox.gke.io/runtime"
+        value  = "gvisor"
+        effect = "NO_SCHEDULE"
+      }
+    }
+  }
+}
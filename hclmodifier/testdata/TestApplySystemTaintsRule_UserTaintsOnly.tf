@@ -0,0 +1,30 @@
+resource "google_container_cluster" "primary" {
+  name     = "primary-cluster"
+  location = "us-central1"
+
+  node_pool {
+    name = "default-pool"
+
+    node_config {
+      taint {
+        key    = "dedicated"
+        value  = "ml"
+        effect = "NO_SCHEDULE"
+      }
+
+      taint {
+        key    = "team.example.com/nvidia.com"
+        value  = "true"
+        effect = "PREFER_NO_SCHEDULE"
+      }
+    }
+  }
+
+  node_pool {
+    name = "no-taints-pool"
+
+    node_config {
+      machine_type = "e2-medium"
+    }
+  }
+}
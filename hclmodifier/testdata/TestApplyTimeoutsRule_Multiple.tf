@@ -0,0 +1,18 @@
+resource "google_container_cluster" "primary" {
+  name     = "primary-cluster"
+  location = "us-central1"
+
+  timeouts {
+    create = "30m"
+  }
+
+  node_pool {
+    name = "default-pool"
+
+    timeouts {}
+  }
+
+  timeouts {
+    delete = "40m"
+  }
+}
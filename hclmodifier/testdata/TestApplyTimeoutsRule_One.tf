@@ -0,0 +1,6 @@
+resource "google_container_cluster" "primary" {
+  name     = "primary-cluster"
+  location = "us-central1"
+
+  timeouts {}
+}
@@ -0,0 +1,28 @@
+resource "google_container_cluster" "primary" {
+  name     = "primary-cluster"
+  location = "us-central1"
+
+  node_pool {
+    name = "surge-pool"
+
+    upgrade_settings {
+      max_surge       = 3
+      max_unavailable = 0
+      strategy        = "SURGE"
+    }
+  }
+
+  node_pool {
+    name = "blue-green-pool"
+
+    upgrade_settings {
+      max_surge       = 1
+      max_unavailable = 0
+      strategy        = "BLUE_GREEN"
+
+      blue_green_settings {
+        node_pool_soak_duration = "3600s"
+      }
+    }
+  }
+}
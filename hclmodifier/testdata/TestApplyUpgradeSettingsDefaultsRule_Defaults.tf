@@ -0,0 +1,14 @@
+resource "google_container_cluster" "primary" {
+  name     = "primary-cluster"
+  location = "us-central1"
+
+  node_pool {
+    name = "default-pool"
+
+    upgrade_settings {
+      max_surge       = 1
+      max_unavailable = 0
+      strategy        = "SURGE"
+    }
+  }
+}
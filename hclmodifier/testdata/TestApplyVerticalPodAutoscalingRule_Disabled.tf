@@ -0,0 +1,8 @@
+resource "google_container_cluster" "primary" {
+  name     = "primary-cluster"
+  location = "us-central1"
+
+  vertical_pod_autoscaling {
+    enabled = false
+  }
+}
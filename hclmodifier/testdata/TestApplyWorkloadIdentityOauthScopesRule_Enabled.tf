@@ -0,0 +1,27 @@
+resource "google_container_cluster" "primary" {
+  name     = "primary-cluster"
+  location = "us-central1"
+
+  workload_identity_config {
+    workload_pool = "my-project.svc.id.goog"
+  }
+
+  node_pool {
+    name = "default-pool"
+
+    node_config {
+      machine_type = "e2-medium"
+      oauth_scopes = [
+        "https://www.googleapis.com/auth/cloud-platform",
+      ]
+    }
+  }
+
+  node_pool {
+    name = "no-scopes-pool"
+
+    node_config {
+      machine_type = "e2-medium"
+    }
+  }
+}
@@ -0,0 +1,7 @@
+resource "google_container_cluster" "primary" {
+  name     = "my-cluster"
+  location = "us-central1"
+  workload_identity_config {
+    workload_pool = "${var.project_id}.svc.id.goog"
+  }
+}
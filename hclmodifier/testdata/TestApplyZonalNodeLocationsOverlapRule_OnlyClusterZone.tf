@@ -0,0 +1,5 @@
+resource "google_container_cluster" "primary" {
+  name           = "primary-cluster"
+  location       = "europe-west1-b"
+  node_locations = ["europe-west1-b"]
+}
@@ -0,0 +1,12 @@
+resource "google_container_cluster" "primary" {
+  name     = "regional-cluster"
+  location = "us-central1"
+  node_pool {
+    name = "default-pool"
+    autoscaling {
+      total_min_node_count = 3
+      total_max_node_count = 9
+      location_policy      = "BALANCED"
+    }
+  }
+}
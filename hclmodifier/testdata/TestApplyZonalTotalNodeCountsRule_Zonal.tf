@@ -0,0 +1,13 @@
+resource "google_container_cluster" "primary" {
+  name     = "zonal-cluster"
+  location = "us-central1-a"
+  node_pool {
+    name = "default-pool"
+    autoscaling {
+      min_node_count       = 1
+      max_node_count       = 3
+      total_min_node_count = 1
+      total_max_node_count = 3
+    }
+  }
+}
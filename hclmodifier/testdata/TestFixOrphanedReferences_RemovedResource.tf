@@ -0,0 +1,31 @@
+resource "google_container_cluster" "empty" {
+  name     = "empty-cluster"
+  location = "us-central1"
+  id       = "projects/p/locations/us-central1/clusters/empty-cluster"
+}
+
+resource "google_container_cluster" "primary" {
+  name               = "my-cluster"
+  location           = "us-central1"
+  min_master_version = "1.30"
+  endpoint           = "10.0.0.1"
+}
+
+resource "google_container_node_pool" "pool" {
+  name     = "pool"
+  cluster  = google_container_cluster.empty.name
+  location = google_container_cluster.primary.location
+  node_config {
+    labels = {
+      cluster = "${google_container_cluster.empty.name}-pool"
+    }
+  }
+}
+
+output "endpoint" {
+  value = google_container_cluster.primary.endpoint
+}
+
+output "unmanaged" {
+  value = google_compute_network.vpc.id
+}
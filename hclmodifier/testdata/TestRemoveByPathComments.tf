@@ -0,0 +1,40 @@
+resource "google_container_cluster" "primary" {
+  # The cluster name.
+  name     = "primary-cluster"
+  location = "us-central1" # Regional cluster.
+
+  # Computed by GKE.
+  id = "projects/my-project/locations/us-central1/clusters/primary-cluster"
+  /* Set by the import. */
+  node_version = "1.29.4-gke.1043002"
+
+  # Unrelated comment, separated by a blank line.
+
+  // Deprecated block.
+  pod_security_policy_config {
+    enabled = false
+  }
+  /*
+   * Left empty by the import.
+   */
+  timeouts {}
+
+  node_config {
+    # Default disk size.
+    disk_size_gb = 100 # GB
+    # Custom machine type.
+    machine_type = "e2-standard-4"
+
+    /* GPU taint added by GKE */
+    taint {
+      key    = "nvidia.com/gpu"
+      value  = "present"
+      effect = "NO_SCHEDULE"
+    }
+  }
+
+  # Added by the import.
+  lifecycle {
+    ignore_changes = [node_config]
+  }
+}
@@ -0,0 +1,22 @@
+resource "google_container_cluster" "empty" {
+  name     = "empty-cluster"
+  location = "us-central1"
+}
+
+resource "google_container_cluster" "primary" {
+  name               = "my-cluster"
+  location           = "us-central1"
+  min_master_version = "1.30"
+}
+
+resource "google_container_cluster" "with_block" {
+  name     = "with-block"
+  location = "us-central1"
+  release_channel {
+    channel = "REGULAR"
+  }
+}
+
+resource "google_compute_network" "vpc" {
+  name = "vpc"
+}
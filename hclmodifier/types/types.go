@@ -1,14 +1,62 @@
 package types
 
+import "errors"
+
+// ErrBlockNotFound is returned (wrapped) when a block addressed by a path doesn't exist.
+// Check for it with errors.Is.
+var ErrBlockNotFound = errors.New("block not found")
+
 // ConditionType is an enumeration defining the types of conditions that can be checked by a Rule.
 type ConditionType string
 
 const (
-	AttributeExists      ConditionType = "AttributeExists"
+	// AttributeExists is met when the attribute at Path exists, even if its value isn't a literal (e.g. `var.name`).
+	AttributeExists ConditionType = "AttributeExists"
+	// AttributeDoesntExist is met when the attribute at Path is absent or set to null.
 	AttributeDoesntExist ConditionType = "AttributeDoesntExist"
 	BlockExists          ConditionType = "BlockExists"
+	// BlockDoesntExist is met when there is no block at Path.
+	BlockDoesntExist     ConditionType = "BlockDoesntExist"
 	AttributeValueEquals ConditionType = "AttributeValueEquals"
-	NullValue            ConditionType = "NullValue"
+	// AttributeExistsAnyOf is met when at least one of the attributes at Paths exists. Path is not used.
+	AttributeExistsAnyOf ConditionType = "AttributeExistsAnyOf"
+	// NullValue is met when the attribute at Path exists and evaluates to null (e.g. `node_version = null`).
+	NullValue ConditionType = "NullValue"
+	// AttributeValueIn is met when the attribute's value equals one of ExpectedValues. An empty ExpectedValues never matches.
+	AttributeValueIn ConditionType = "AttributeValueIn"
+	// AttributeValueNotIn is met when the attribute is absent or its value equals none of ExpectedValues.
+	// An attribute whose value can't be evaluated (e.g. `var.channel`) doesn't meet it.
+	AttributeValueNotIn ConditionType = "AttributeValueNotIn"
+	// AttributeValueMatches is met when the attribute is a string matching the regular expression in ExpectedValue.
+	AttributeValueMatches ConditionType = "AttributeValueMatches"
+	// AttributeValueGreaterThan is met when the attribute's numeric value is strictly greater than the number in ExpectedValue.
+	AttributeValueGreaterThan ConditionType = "AttributeValueGreaterThan"
+	// AttributeValueLessThan is met when the attribute's numeric value is strictly less than the number in ExpectedValue.
+	AttributeValueLessThan ConditionType = "AttributeValueLessThan"
+	// ListLengthGreaterThan is met when the attribute is a list with more elements than the integer in ExpectedValue.
+	ListLengthGreaterThan ConditionType = "ListLengthGreaterThan"
+	// ListValuesEqual is met when the attribute is a list whose elements are exactly ExpectedValues, in any order.
+	// Elements are compared by their string form.
+	ListValuesEqual ConditionType = "ListValuesEqual"
+	// MapKeysEqual is met when the attribute is a map whose keys are exactly ExpectedValues, in any order.
+	MapKeysEqual ConditionType = "MapKeysEqual"
+	// AttributeIsEmpty is met when the attribute at Path exists and is an empty map (e.g. `resource_labels = {}`).
+	AttributeIsEmpty ConditionType = "AttributeIsEmpty"
+	// AttributeIsEmptyList is met when the attribute at Path exists and is an empty list (e.g. `node_locations = []`).
+	AttributeIsEmptyList ConditionType = "AttributeIsEmptyList"
+	// BlockCountEquals is met when the number of blocks whose type is the last element of Path, directly within
+	// the block resolved by the preceding elements, equals the integer in ExpectedValue.
+	BlockCountEquals ConditionType = "BlockCountEquals"
+	// BlockIsEmpty is met when the block at Path exists and has no attributes and no non-empty nested blocks.
+	BlockIsEmpty ConditionType = "BlockIsEmpty"
+	// BlockMatchesDefaults is met when the block at Path exists, every attribute it has is listed in Defaults and
+	// equals its default value, and it has no non-empty nested blocks. Listed attributes may be absent.
+	BlockMatchesDefaults ConditionType = "BlockMatchesDefaults"
+	// AttributeValueEqualsPath is met when the attributes at Path and ComparePath both exist and have equal, non-null values.
+	AttributeValueEqualsPath ConditionType = "AttributeValueEqualsPath"
+	// VersionLessThan is met when the attribute at Path holds a GKE version (e.g. "1.27.3-gke.100")
+	// strictly older than the version held by the attribute at ComparePath.
+	VersionLessThan ConditionType = "VersionLessThan"
 )
 
 // ActionType is an enumeration defining the types of actions that can be performed by a Rule.
@@ -20,6 +68,34 @@ const (
 	SetAttributeValue                 ActionType = "SetAttributeValue"
 	RemoveAllBlocksOfType             ActionType = "RemoveAllBlocksOfType"
 	RemoveAllNestedBlocksMatchingPath ActionType = "RemoveAllNestedBlocksMatchingPath"
+	RemoveListElementsMatching        ActionType = "RemoveListElementsMatching"
+	// ReportWarning doesn't modify the file; it records Message as a warning in the ChangeReport.
+	ReportWarning ActionType = "ReportWarning"
+	// CommentOutAttribute removes the attribute at Path and appends a comment with its original `key = value`
+	// text to the same block, so a reviewer can restore it.
+	CommentOutAttribute ActionType = "CommentOutAttribute"
+	// RenameAttribute renames the attribute at Path to NewName, keeping its value expression unchanged.
+	RenameAttribute ActionType = "RenameAttribute"
+	// RemoveAttributeFromAllMatchingBlocks removes the attribute at the end of Path from every block matching
+	// the preceding block path, instead of only the one block a path normally resolves to. Each attribute is
+	// removed as by RemoveAttribute, along with the comments above it.
+	RemoveAttributeFromAllMatchingBlocks ActionType = "RemoveAttributeFromAllMatchingBlocks"
+	// RemoveEmptyBlocksMatchingPath removes every block matching Path that has no attributes and
+	// no non-empty nested blocks, along with the comments above it, as RemoveBlock does.
+	RemoveEmptyBlocksMatchingPath ActionType = "RemoveEmptyBlocksMatchingPath"
+	// RemoveDuplicateNestedBlocks keeps only the first nested block of each type within every block matching Path,
+	// removing the later duplicates.
+	RemoveDuplicateNestedBlocks ActionType = "RemoveDuplicateNestedBlocks"
+	// RemoveMapEntry removes the entries of the map attribute at Path whose key is MapKey (or, with MatchKeyPrefix,
+	// starts with MapKey). The other entries keep their formatting; a map left without entries stays as `{}`.
+	RemoveMapEntry ActionType = "RemoveMapEntry"
+	// RemoveListElement removes the elements of the list attribute at Path equal to ElementValue, or to the value of
+	// the attribute at ElementValuePath, keeping the others in order. If the list ends up empty, the attribute is
+	// removed as well.
+	RemoveListElement ActionType = "RemoveListElement"
+	// RewriteAttributeWithRegex replaces the matches of Pattern in the string attribute at Path with Replacement.
+	// Values that aren't literal strings are left alone.
+	RewriteAttributeWithRegex ActionType = "RewriteAttributeWithRegex"
 )
 
 // RuleExecutionType defines how a rule should be executed.
@@ -38,9 +114,24 @@ type RuleCondition struct {
 	// Example for a nested attribute: `["block_name", "nested_block_name", "attribute_name"]`
 	// Example for a block: `["block_name", "nested_block_name"]`
 	Path []string
+	// Paths are the candidate attribute paths for AttributeExistsAnyOf, each one like Path.
+	Paths [][]string
 	// ExpectedValue is the string representation of the value to compare against for AttributeValueEquals.
 	// This string will be parsed into a cty.Value for comparison during rule processing.
 	ExpectedValue string
+	// ExpectedValues are the string representations of the values compared by AttributeValueIn and AttributeValueNotIn.
+	// Each one is parsed the same way as ExpectedValue. ListValuesEqual compares them with the list's elements,
+	// and MapKeysEqual with the map's keys.
+	ExpectedValues []string
+	// ComparePath is the path to a second attribute whose value is compared against the attribute at Path.
+	// Used by VersionLessThan and AttributeValueEqualsPath.
+	ComparePath []string
+	// Defaults maps attribute names to the string representations of their default values, for BlockMatchesDefaults.
+	// Each value is parsed the same way as ExpectedValue.
+	Defaults map[string]string
+	// CompareInResource makes ComparePath relative to the resource block instead of the block the condition is
+	// checked against. This lets ForEachNestedBlock rules compare a nested attribute with a resource-level one.
+	CompareInResource bool
 }
 
 // RuleAction defines an action to be performed on an HCL structure if all conditions of a Rule are met.
@@ -56,22 +147,127 @@ type RuleAction struct {
 	ValueToSet string
 	// PathToSet is a slice of strings representing the hierarchical path to the attribute to set as Value.
 	PathToSet []string
+	// OnlyIfAbsent makes SetAttributeValue a no-op when the attribute at Path already exists,
+	// regardless of its current value. The value is only set when the attribute is missing.
+	OnlyIfAbsent bool
+	// NewName is the new attribute name for the RenameAttribute action.
+	NewName string
 	// BlockTypeToRemove specifies the type of block to remove for the RemoveAllBlocksOfType action.
 	BlockTypeToRemove string
+	// BlockConditions optionally restricts RemoveAllBlocksOfType and RemoveAllNestedBlocksMatchingPath to the
+	// blocks meeting ALL of these conditions. Their paths are relative to each candidate block's body.
+	BlockConditions []RuleCondition
+	// Pattern is a regular expression used by the RemoveListElementsMatching and RewriteAttributeWithRegex actions.
+	// List elements whose string form matches the pattern are removed; if the list ends up empty,
+	// the attribute is removed as well.
+	Pattern string
+	// Replacement replaces the matches of Pattern for the RewriteAttributeWithRegex action. It can refer to
+	// submatches as in regexp.Regexp.ReplaceAllString, e.g. `$1`.
+	Replacement string
+	// ElementValue is the value the RemoveListElement action removes from the list. Elements are compared by their
+	// string form, so "1" also matches the number 1.
+	ElementValue string
+	// ElementValuePath is the path to the attribute whose value the RemoveListElement action removes from the list.
+	// It takes precedence over ElementValue.
+	ElementValuePath []string
+	// Message is the warning text recorded by the ReportWarning action.
+	Message string
+	// MapKey is the key of the map entries removed by the RemoveMapEntry action.
+	MapKey string
+	// MatchKeyPrefix makes RemoveMapEntry remove every entry whose key starts with MapKey, instead of only the entry
+	// whose key equals it.
+	MatchKeyPrefix bool
 }
 
 // Rule defines a single, named modification operation to be conditionally applied to HCL resources.
 type Rule struct {
 	// Name is a human-readable identifier for the rule.
 	Name string
+	// TargetBlockType is the type of the top-level blocks this rule applies to: "resource" (the default if empty)
+	// or "data", to target data sources such as `data "google_container_cluster"`.
+	TargetBlockType string
 	// TargetResourceType is the HCL resource type this rule applies to (e.g., "google_container_cluster").
 	TargetResourceType string
+	// TargetResourceLabelPattern optionally restricts the rule to resources whose name (the second label, e.g.
+	// "primary") matches this regular expression. If empty, every resource of TargetResourceType is processed.
+	TargetResourceLabelPattern string
 	// Conditions is a list of conditions that must ALL be true.
 	Conditions []RuleCondition
+	// ResourceConditions is a list of conditions that must ALL be true for the resource block itself.
+	// Their paths are always relative to the resource block, even for ForEachNestedBlock rules,
+	// which lets a nested rule depend on resource-level attributes such as `location`.
+	ResourceConditions []RuleCondition
 	// Actions is a list of actions to be performed if all conditions are met.
 	Actions []RuleAction
 	// ExecutionType specifies how the rule is executed. Defaults to RuleExecutionStandard.
 	ExecutionType RuleExecutionType
 	// It specifies the type of nested block to target (e.g., "node_pool").
 	NestedBlockTargetType string
+	// NestedBlockTargetLabels optionally restricts a ForEachNestedBlock rule to nested blocks with exactly these labels.
+	// If empty, all nested blocks of NestedBlockTargetType are processed regardless of their labels.
+	NestedBlockTargetLabels []string
+	// RunAfter optionally names rules that must be applied before this one. ApplyRules reorders the rules it is given
+	// accordingly; names that aren't among them are ignored.
+	RunAfter []string
+}
+
+// ChangeEntry describes a single modification made to the HCL file by a rule action.
+type ChangeEntry struct {
+	// RuleName is the name of the rule whose action made the change.
+	RuleName string
+	// ResourceLabels are the labels of the resource block that was changed (e.g. `["google_container_cluster", "primary"]`).
+	ResourceLabels []string
+	// ActionType is the type of action that made the change.
+	ActionType ActionType
+	// Path is the path of the affected attribute or block, relative to the resource block body.
+	// For rules executed per nested block, the path is prefixed with the nested block type (e.g. `["node_pool", "initial_node_count"]`).
+	Path []string
+	// Before and After are the source text of the attribute's value before and after the change, empty if the
+	// attribute didn't exist then. Only Modifier.Diff sets them.
+	Before string
+	After  string
+}
+
+// Warning describes a potential problem found in the HCL file that the tool doesn't fix automatically.
+type Warning struct {
+	// RuleName is the name of the rule that raised the warning.
+	RuleName string
+	// ResourceLabels are the labels of the resource block the warning refers to.
+	ResourceLabels []string
+	// Message is a human-readable description of the problem.
+	Message string
+}
+
+// RuleStats counts, for a single rule, the resources it was checked against during a rule application run.
+// It tells a rule that had nothing to do apart from one whose conditions never matched.
+type RuleStats struct {
+	RuleName string
+	// Matched is the number of resources of the rule's target type (and label pattern, if any).
+	Matched int
+	// ConditionsMet is the number of matched resources whose conditions were all met. For ForEachNestedBlock rules,
+	// a resource counts if the conditions were met for at least one of its nested blocks.
+	ConditionsMet int
+	// Acted is the number of resources the rule's actions changed or raised a warning for.
+	Acted int
+}
+
+// ChangeReport collects every modification made and every warning raised during a rule application run.
+type ChangeReport struct {
+	Changes  []ChangeEntry
+	Warnings []Warning
+	// RuleStats has one entry per rule applied, in the order the rules were applied.
+	RuleStats []RuleStats
+}
+
+// AppliedRuleNames returns the names of the rules that made at least one change, in the order they first did so.
+func (r ChangeReport) AppliedRuleNames() []string {
+	var names []string
+	seen := make(map[string]bool)
+	for _, change := range r.Changes {
+		if !seen[change.RuleName] {
+			seen[change.RuleName] = true
+			names = append(names, change.RuleName)
+		}
+	}
+	return names
 }
@@ -0,0 +1,80 @@
+package hclmodifier
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"go.uber.org/zap"
+
+	"github.com/kotatut/cluster_import_cleaner/hclmodifier/types"
+)
+
+// clusterConflict is a combination of settings that the provider rejects in a google_container_cluster resource,
+// typically left behind when a rule that should have removed one of them did not apply.
+type clusterConflict struct {
+	// Message describes the conflict.
+	Message string
+	// Conditions are ALL met by a cluster with the conflict.
+	Conditions []types.RuleCondition
+}
+
+// clusterConflicts are the conflicts Validate looks for in every google_container_cluster resource.
+var clusterConflicts = []clusterConflict{
+	{
+		Message: "logging_service is set while cluster_telemetry.type is ENABLED",
+		Conditions: []types.RuleCondition{
+			{Type: types.AttributeExists, Path: []string{"logging_service"}},
+			{Type: types.AttributeValueEquals, Path: []string{"cluster_telemetry", "type"}, ExpectedValue: "ENABLED"},
+		},
+	},
+	{
+		Message: "both logging_service and logging_config are set",
+		Conditions: []types.RuleCondition{
+			{Type: types.AttributeExists, Path: []string{"logging_service"}},
+			{Type: types.BlockExists, Path: []string{"logging_config"}},
+		},
+	},
+	{
+		Message: "both monitoring_service and monitoring_config are set",
+		Conditions: []types.RuleCondition{
+			{Type: types.AttributeExists, Path: []string{"monitoring_service"}},
+			{Type: types.BlockExists, Path: []string{"monitoring_config"}},
+		},
+	},
+	{
+		Message: "both cluster_ipv4_cidr and ip_allocation_policy.cluster_ipv4_cidr_block are set",
+		Conditions: []types.RuleCondition{
+			{Type: types.AttributeExists, Path: []string{"cluster_ipv4_cidr"}},
+			{Type: types.AttributeExists, Path: []string{"ip_allocation_policy", "cluster_ipv4_cidr_block"}},
+		},
+	},
+}
+
+// Validate checks that the file, as it would be written, still parses and that no google_container_cluster
+// resource is left with obviously conflicting settings, such as both `logging_service` and an ENABLED
+// `cluster_telemetry` block. It returns one error per problem found, or nil if there are none. It never
+// modifies the file.
+func (m *Modifier) Validate() []error {
+	if m.file == nil || m.file.Body() == nil {
+		return nil
+	}
+
+	if _, diags := hclwrite.ParseConfig(m.file.Bytes(), "", hcl.Pos{Line: 1, Column: 1}); diags.HasErrors() {
+		return []error{fmt.Errorf("modified HCL no longer parses: %w", diags)}
+	}
+
+	var errs []error
+	for _, block := range m.file.Body().Blocks() {
+		if block.Type() != "resource" || len(block.Labels()) < 2 || block.Labels()[0] != "google_container_cluster" {
+			continue
+		}
+		logger := m.Logger.With(zap.Strings("resourceLabels", block.Labels()))
+		for _, conflict := range clusterConflicts {
+			if m.checkConditions(block.Body(), block.Body(), conflict.Conditions, logger) {
+				errs = append(errs, fmt.Errorf("resource %v: %s", block.Labels(), conflict.Message))
+			}
+		}
+	}
+	return errs
+}
@@ -0,0 +1,145 @@
+package hclmodifier
+
+import (
+	"testing"
+
+	"github.com/kotatut/cluster_import_cleaner/hclmodifier/rules"
+	"github.com/kotatut/cluster_import_cleaner/hclmodifier/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateRules(t *testing.T) {
+	t.Run("Default rules are valid", func(t *testing.T) {
+		assert.Empty(t, rules.Validate(rules.AllRules()))
+	})
+
+	validAction := types.RuleAction{Type: types.RemoveAttribute, Path: []string{"id"}}
+	tests := []struct {
+		name          string
+		rule          types.Rule
+		expectedError string
+	}{
+		{
+			name: "Unknown condition type",
+			rule: types.Rule{
+				Name:               "typo",
+				TargetResourceType: "google_container_cluster",
+				Conditions:         []types.RuleCondition{{Type: "AttributeExist", Path: []string{"id"}}},
+				Actions:            []types.RuleAction{validAction},
+			},
+			expectedError: `rule "typo": condition 0 (AttributeExist): unknown condition type`,
+		},
+		{
+			name: "Unknown action type",
+			rule: types.Rule{
+				Name:               "typo",
+				TargetResourceType: "google_container_cluster",
+				Actions:            []types.RuleAction{{Type: "RemoveAttributes", Path: []string{"id"}}},
+			},
+			expectedError: `rule "typo": action 0 (RemoveAttributes): unknown action type`,
+		},
+		{
+			name: "ForEachNestedBlock without NestedBlockTargetType",
+			rule: types.Rule{
+				Name:               "nested",
+				TargetResourceType: "google_container_cluster",
+				ExecutionType:      types.RuleExecutionForEachNestedBlock,
+				Actions:            []types.RuleAction{validAction},
+			},
+			expectedError: `rule "nested": NestedBlockTargetType is required for ForEachNestedBlock rules`,
+		},
+		{
+			name: "Condition without path",
+			rule: types.Rule{
+				Name:               "no path",
+				TargetResourceType: "google_container_cluster",
+				ResourceConditions: []types.RuleCondition{{Type: types.AttributeExists}},
+				Actions:            []types.RuleAction{validAction},
+			},
+			expectedError: `rule "no path": resource condition 0 (AttributeExists): Path is empty`,
+		},
+		{
+			name: "Invalid block condition pattern",
+			rule: types.Rule{
+				Name:               "bad pattern",
+				TargetResourceType: "google_container_cluster",
+				Actions: []types.RuleAction{{
+					Type:              types.RemoveAllBlocksOfType,
+					BlockTypeToRemove: "taint",
+					BlockConditions:   []types.RuleCondition{{Type: types.AttributeValueMatches, Path: []string{"key"}, ExpectedValue: "("}},
+				}},
+			},
+			expectedError: `rule "bad pattern": action 0 (RemoveAllBlocksOfType): block condition 0 (AttributeValueMatches): invalid ExpectedValue pattern`,
+		},
+		{
+			name: "RenameAttribute without NewName",
+			rule: types.Rule{
+				Name:               "rename",
+				TargetResourceType: "google_container_cluster",
+				Actions:            []types.RuleAction{{Type: types.RenameAttribute, Path: []string{"old"}}},
+			},
+			expectedError: `rule "rename": action 0 (RenameAttribute): NewName is empty`,
+		},
+		{
+			name: "Missing target resource type",
+			rule: types.Rule{
+				Name:    "untargeted",
+				Actions: []types.RuleAction{validAction},
+			},
+			expectedError: `rule "untargeted": TargetResourceType is empty`,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			errs := rules.Validate([]types.Rule{tc.rule})
+			if assert.Len(t, errs, 1) {
+				assert.ErrorContains(t, errs[0], tc.expectedError)
+			}
+		})
+	}
+
+	t.Run("Every problem is reported", func(t *testing.T) {
+		errs := rules.Validate([]types.Rule{
+			{Name: "first", Actions: []types.RuleAction{{Type: "Unknown"}}},
+			{Name: "second", TargetResourceType: "google_container_cluster"},
+		})
+		assert.Len(t, errs, 3) // missing TargetResourceType, unknown action type, no actions
+	})
+}
+
+func TestModifierValidate(t *testing.T) {
+	t.Run("Conflicting settings", func(t *testing.T) {
+		modifier := newTestModifier(t, `resource "google_container_cluster" "primary" {
+  logging_service    = "logging.googleapis.com/kubernetes"
+  monitoring_service = "monitoring.googleapis.com/kubernetes"
+
+  cluster_telemetry {
+    type = "ENABLED"
+  }
+}
+
+resource "google_container_node_pool" "pool" {
+  logging_service = "logging.googleapis.com/kubernetes"
+
+  logging_config {
+    enable_components = ["SYSTEM_COMPONENTS"]
+  }
+}`)
+		errs := modifier.Validate()
+		if assert.Len(t, errs, 1, "Only google_container_cluster resources are checked") {
+			assert.EqualError(t, errs[0], "resource [google_container_cluster primary]: logging_service is set while cluster_telemetry.type is ENABLED")
+		}
+	})
+
+	t.Run("Cleaned cluster", func(t *testing.T) {
+		modifier := newTestModifier(t, `resource "google_container_cluster" "primary" {
+  monitoring_service = "monitoring.googleapis.com/kubernetes"
+
+  cluster_telemetry {
+    type = "ENABLED"
+  }
+}`)
+		assert.Empty(t, modifier.Validate())
+	})
+}
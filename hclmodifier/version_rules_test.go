@@ -36,6 +36,13 @@ func TestApplyNodeVersionRule(t *testing.T) {
 			expectNodeVersionRemoved: true,
 			resourceLabelsToVerify:   []string{"google_container_cluster", "primary"},
 		},
+		{
+			name:                     "Node version present with a release channel",
+			hclContentFile:           "testdata/TestApplyNodeVersionRule_ReleaseChannel.tf",
+			expectedModifications:    0,
+			expectNodeVersionRemoved: false,
+			resourceLabelsToVerify:   []string{"google_container_cluster", "primary"},
+		},
 	}
 
 	for _, tc := range tests {
@@ -77,3 +84,145 @@ func TestApplyNodeVersionRule(t *testing.T) {
 		})
 	}
 }
+
+func TestApplyStaleMinMasterVersionRule(t *testing.T) {
+	tests := []struct {
+		name                  string
+		fixture               string
+		expectedModifications int
+	}{
+		{
+			name:                  "min_master_version older minor version",
+			fixture:               "testdata/TestApplyStaleMinMasterVersionRule_MasterOlder.tf",
+			expectedModifications: 1,
+		},
+		{
+			name:                  "min_master_version older GKE build",
+			fixture:               "testdata/TestApplyStaleMinMasterVersionRule_MasterOlderBuild.tf",
+			expectedModifications: 1,
+		},
+		{
+			name:                  "min_master_version newer than node_version",
+			fixture:               "testdata/TestApplyStaleMinMasterVersionRule_MasterNewer.tf",
+			expectedModifications: 0,
+		},
+		{
+			name:                  "Equal versions",
+			fixture:               "testdata/TestApplyStaleMinMasterVersionRule_Equal.tf",
+			expectedModifications: 0,
+		},
+		{
+			name:                  "Partial min_master_version matching node_version",
+			fixture:               "testdata/TestApplyStaleMinMasterVersionRule_PartialVersion.tf",
+			expectedModifications: 0,
+		},
+		{
+			name:                  "Partial min_master_version older than node_version",
+			fixture:               "testdata/TestApplyStaleMinMasterVersionRule_PartialVersionOlder.tf",
+			expectedModifications: 1,
+		},
+		{
+			name:                  "Unparseable min_master_version",
+			fixture:               "testdata/TestApplyStaleMinMasterVersionRule_Unparseable.tf",
+			expectedModifications: 0,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			modifier, modifications := applyRulesToFixture(t, tc.fixture, []types.Rule{rules.StaleMinMasterVersionRule})
+			assert.Equal(t, tc.expectedModifications, modifications)
+			assertMatchesGolden(t, modifier, tc.fixture+".golden")
+		})
+	}
+}
+
+func TestApplyReleaseChannelMinMasterVersionRule(t *testing.T) {
+	tests := []struct {
+		name                  string
+		fixture               string
+		expectedModifications int
+	}{
+		{
+			name:                  "REGULAR channel with min_master_version",
+			fixture:               "testdata/TestApplyReleaseChannelMinMasterVersionRule_Regular.tf",
+			expectedModifications: 1,
+		},
+		{
+			name:                  "UNSPECIFIED channel with min_master_version",
+			fixture:               "testdata/TestApplyReleaseChannelMinMasterVersionRule_Unspecified.tf",
+			expectedModifications: 0,
+		},
+		{
+			name:                  "No release_channel block",
+			fixture:               "testdata/TestApplyReleaseChannelMinMasterVersionRule_NoReleaseChannel.tf",
+			expectedModifications: 0,
+		},
+		{
+			name:                  "No min_master_version",
+			fixture:               "testdata/TestApplyReleaseChannelMinMasterVersionRule_NoMinMasterVersion.tf",
+			expectedModifications: 0,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			modifier, modifications := applyRulesToFixture(t, tc.fixture, []types.Rule{rules.ReleaseChannelMinMasterVersionRule, rules.SetMinVersionRule})
+			assert.Equal(t, tc.expectedModifications, modifications)
+			assertMatchesGolden(t, modifier, tc.fixture+".golden")
+		})
+	}
+}
+
+func TestApplyNodePoolVersionRule(t *testing.T) {
+	tests := []struct {
+		name                  string
+		fixture               string
+		expectedModifications int
+	}{
+		{
+			name:                  "Matching and differing node pool versions",
+			fixture:               "testdata/TestApplyNodePoolVersionRule_Mixed.tf",
+			expectedModifications: 1,
+		},
+		{
+			name:                  "No min_master_version",
+			fixture:               "testdata/TestApplyNodePoolVersionRule_NoMinMasterVersion.tf",
+			expectedModifications: 0,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			modifier, modifications := applyRulesToFixture(t, tc.fixture, []types.Rule{rules.NodePoolVersionRule})
+			assert.Equal(t, tc.expectedModifications, modifications)
+			assertMatchesGolden(t, modifier, tc.fixture+".golden")
+		})
+	}
+}
+
+func TestCompareGKEVersions(t *testing.T) {
+	tests := []struct {
+		a, b         string
+		expected     int
+		expectParsed bool
+	}{
+		{"1.27.8-gke.200", "1.28.3-gke.1286000", -1, true},
+		{"1.28.3-gke.1286000", "1.27.8-gke.200", 1, true},
+		{"1.27.8-gke.200", "1.27.8-gke.1067004", -1, true},
+		{"1.27.8-gke.200", "1.27.8-gke.200", 0, true},
+		{"1.27.10", "1.27.9", 1, true},
+		{"1.27", "1.27.8-gke.200", 0, true},
+		{"1.26", "1.27.8-gke.200", -1, true},
+		{"latest", "1.27.8-gke.200", 0, false},
+		{"1.27.8-gke.200", "", 0, false},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.a+"_vs_"+tc.b, func(t *testing.T) {
+			cmp, ok := compareGKEVersions(tc.a, tc.b)
+			assert.Equal(t, tc.expectParsed, ok)
+			assert.Equal(t, tc.expected, cmp)
+		})
+	}
+}
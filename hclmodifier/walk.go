@@ -0,0 +1,29 @@
+package hclmodifier
+
+import (
+	"github.com/hashicorp/hcl/v2/hclwrite"
+)
+
+// WalkFunc is called by Walk for every block in the file. path holds the types of the enclosing blocks
+// followed by the block's own type, e.g. ["resource", "node_pool", "node_config"].
+// Returning false skips the block's nested blocks.
+type WalkFunc func(block *hclwrite.Block, path []string) bool
+
+// Walk visits every block in the file depth-first, in source order, calling fn for each one.
+// fn must not add or remove blocks while walking.
+func (m *Modifier) Walk(fn WalkFunc) {
+	if m.file == nil || m.file.Body() == nil {
+		return
+	}
+	walkBody(m.file.Body(), nil, fn)
+}
+
+// walkBody calls fn for every block in body and, unless fn returns false, for the blocks nested in it.
+func walkBody(body *hclwrite.Body, path []string, fn WalkFunc) {
+	for _, block := range body.Blocks() {
+		blockPath := append(append([]string{}, path...), block.Type())
+		if fn(block, blockPath) {
+			walkBody(block.Body(), blockPath, fn)
+		}
+	}
+}